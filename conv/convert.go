@@ -23,11 +23,11 @@ import (
 // same as ECMA Number.MAX_SAFE_INTEGER and Number.MIN_SAFE_INTEGER
 const (
 	maxJSONFloat         = float64(1<<53 - 1)  // 9007199254740991.0 	 	 2^53 - 1
-	minJSONFloat         = -float64(1<<53 - 1) //-9007199254740991.0	-2^53 - 1
+	minJSONFloat         = -float64(1<<53 - 1) //-9007199254740991.0	-2^53 + 1
 	epsilon      float64 = 1e-9
 )
 
-// IsFloat64AJSONInteger allows for integers [-2^53, 2^53-1] inclusive.
+// IsFloat64AJSONInteger allows for integers [-2^53+1, 2^53-1] inclusive.
 func IsFloat64AJSONInteger(f float64) bool {
 	if math.IsNaN(f) || math.IsInf(f, 0) || f < minJSONFloat || f > maxJSONFloat {
 		return false
@@ -59,6 +59,64 @@ func TestFormatBool(t *testing.T) {
 	assert.Equal(t, "false", FormatBool(false))
 }
 
+func TestConvertFormatRoundTrip(t *testing.T) {
+	t.Run("should convert truthy tokens regardless of case", func(t *testing.T) {
+		for _, k := range []string{"TRUE", "True", "Yes", "Y", "ON", "T"} {
+			r, err := ConvertBool(k)
+			require.NoError(t, err)
+			assert.Truef(t, r, "expected %q to evaluate as true", k)
+		}
+	})
+
+	t.Run("should convert falsy tokens", func(t *testing.T) {
+		for _, k := range []string{"false", "FALSE", "0", "no", "No", "n", "off", "f"} {
+			r, err := ConvertBool(k)
+			require.NoError(t, err)
+			assert.Falsef(t, r, "expected %q to evaluate as false", k)
+		}
+	})
+
+	t.Run("should round-trip booleans", func(t *testing.T) {
+		for _, b := range []bool{true, false} {
+			r, err := ConvertBool(FormatBool(b))
+			require.NoError(t, err)
+			assert.Equal(t, b, r)
+		}
+	})
+
+	t.Run("should format floats canonically and round-trip", func(t *testing.T) {
+		for str, f := range map[string]float64{
+			"1":           1.0,
+			"-1.5":        -1.5,
+			"0":           0,
+			"0.1":         0.1,
+			"5.494430303": 5.494430303,
+			"1000000":     1e6,
+		} {
+			assert.Equal(t, str, FormatFloat(f))
+
+			c, err := ConvertFloat64(str)
+			require.NoError(t, err)
+			assert.Equal(t, f, c) //nolint:testifylint // exact round-trip is expected
+		}
+	})
+
+	t.Run("should format integers canonically and round-trip", func(t *testing.T) {
+		for str, i := range map[string]int64{
+			"0":                    0,
+			"-42":                  -42,
+			"9223372036854775807":  math.MaxInt64,
+			"-9223372036854775808": math.MinInt64,
+		} {
+			assert.Equal(t, str, FormatInteger(i))
+
+			c, err := ConvertInt64(str)
+			require.NoError(t, err)
+			assert.Equal(t, i, c)
+		}
+	})
+}
+
 func TestConvertFloat(t *testing.T) {
 	t.Run("with float32", func(t *testing.T) {
 		validFloats := []float32{1.0, -1, math.MaxFloat32, math.SmallestNonzeroFloat32, 0, 5.494430303}
@@ -306,6 +364,11 @@ func TestConvertUinteger(t *testing.T) {
 
 func TestIsFloat64AJSONInteger(t *testing.T) {
 	assert.False(t, IsFloat64AJSONInteger(math.Inf(1)))
+	assert.False(t, IsFloat64AJSONInteger(math.Inf(-1)))
+	assert.False(t, IsFloat64AJSONInteger(math.NaN()))
+	assert.False(t, IsFloat64AJSONInteger(float64(1<<53)))
+	assert.False(t, IsFloat64AJSONInteger(-float64(1<<53)))
+	assert.False(t, IsFloat64AJSONInteger(1.5))
 	assert.False(t, IsFloat64AJSONInteger(maxJSONFloat+1))
 	assert.False(t, IsFloat64AJSONInteger(minJSONFloat-1))
 	assert.False(t, IsFloat64AJSONInteger(math.SmallestNonzeroFloat64))
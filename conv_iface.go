@@ -20,7 +20,7 @@ import (
 	"github.com/go-openapi/swag/conv"
 )
 
-// IsFloat64AJSONInteger allows for integers [-2^53, 2^53-1] inclusive.
+// IsFloat64AJSONInteger allows for integers [-2^53+1, 2^53-1] inclusive.
 //
 // Deprecated: use [conv.IsFloat64AJSONInteger] instead.
 func IsFloat64AJSONInteger(f float64) bool { return conv.IsFloat64AJSONInteger(f) }
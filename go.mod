@@ -17,6 +17,7 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-openapi/swag/internal/yamlnodes v0.0.0-00010101000000-000000000000 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -27,6 +28,8 @@ replace github.com/go-openapi/swag/conv => ./conv
 
 replace github.com/go-openapi/swag/fileutils => ./fileutils
 
+replace github.com/go-openapi/swag/internal/yamlnodes => ./internal/yamlnodes
+
 replace github.com/go-openapi/swag/jsonname => ./jsonname
 
 replace github.com/go-openapi/swag/jsonutils => ./jsonutils
@@ -0,0 +1,106 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlnodes
+
+import (
+	"fmt"
+	"math"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+const (
+	// minExpandedNodes is the number of nodes any YAML document may expand to with aliases.
+	minExpandedNodes = 10000
+
+	// maxAliasExpansion is the factor by which aliases may expand the nodes of a larger YAML document.
+	maxAliasExpansion = 10
+)
+
+// CheckAliases verifies that the aliases of a YAML node tree can safely be expanded,
+// before converting the tree to go values.
+//
+// An error is returned if an anchor is referenced from within its own value, which would make the conversion
+// recurse forever, or if aliases expand to too many nodes compared to the size of the tree, like with
+// the "billion laughs" document. The check is linear in the size of the tree.
+func CheckAliases(root *yaml.Node) error {
+	c := aliasChecker{
+		sizes:     make(map[*yaml.Node]int),
+		expanding: make(map[*yaml.Node]struct{}),
+	}
+
+	expanded, err := c.size(root)
+	if err != nil {
+		return err
+	}
+
+	limit := maxAliasExpansion * c.nodes
+	if limit < minExpandedNodes {
+		limit = minExpandedNodes
+	}
+
+	if expanded > limit {
+		return fmt.Errorf("YAML aliases expand to more than %d nodes", limit)
+	}
+
+	return nil
+}
+
+// aliasChecker computes the number of nodes of a YAML node tree once its aliases are expanded.
+type aliasChecker struct {
+	sizes     map[*yaml.Node]int      // expanded size of anchored nodes
+	expanding map[*yaml.Node]struct{} // targets of the aliases being expanded
+	nodes     int                     // nodes actually held by the tree
+}
+
+func (c *aliasChecker) size(node *yaml.Node) (int, error) {
+	if size, ok := c.sizes[node]; ok {
+		return size, nil
+	}
+
+	c.nodes++
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		target := node.Alias
+		if _, ok := c.expanding[target]; ok {
+			return 0, fmt.Errorf("YAML anchor %q at line %d is referenced from within its own value", target.Anchor, target.Line)
+		}
+
+		c.expanding[target] = struct{}{}
+		size, err := c.size(target)
+		delete(c.expanding, target)
+
+		return size, err
+	}
+
+	size := 1
+	for _, child := range node.Content {
+		n, err := c.size(child)
+		if err != nil {
+			return 0, err
+		}
+
+		// saturate, since expanded sizes grow exponentially with crafted documents
+		size += n
+		if size > math.MaxInt32 {
+			size = math.MaxInt32
+		}
+	}
+
+	if node.Anchor != "" {
+		c.sizes[node] = size
+	}
+
+	return size, nil
+}
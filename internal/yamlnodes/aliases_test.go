@@ -0,0 +1,51 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlnodes
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestCheckAliases(t *testing.T) {
+	t.Run("should accept a document with aliases", func(t *testing.T) {
+		var doc yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte("a: &a {x: 1}\nb: *a\nc: {<<: *a}\n"), &doc))
+		require.NoError(t, CheckAliases(&doc))
+	})
+
+	t.Run("should reject an alias of itself", func(t *testing.T) {
+		alias := &yaml.Node{Kind: yaml.AliasNode}
+		alias.Alias = alias
+		root := &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{alias}}
+		require.ErrorContains(t, CheckAliases(root), "is referenced from within its own value")
+	})
+
+	t.Run("should reject aliases expanding to too many nodes", func(t *testing.T) {
+		var doc strings.Builder
+		doc.WriteString("a0: &a0 [x, x, x, x, x, x, x, x, x, x]\n")
+		for i := 1; i < 6; i++ {
+			fmt.Fprintf(&doc, "a%d: &a%d [%s*a%d]\n", i, i, strings.Repeat(fmt.Sprintf("*a%d, ", i-1), 9), i-1)
+		}
+
+		var root yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(doc.String()), &root))
+		require.ErrorContains(t, CheckAliases(&root), "YAML aliases expand to more than")
+	})
+}
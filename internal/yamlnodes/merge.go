@@ -0,0 +1,75 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlnodes
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Merge appends to the items of a mapping the items of the mappings merged with merge keys ("<<"),
+// following https://yaml.org/type/merge.html.
+//
+// The merged nodes are the values of the merge keys of a YAML mapping: either mappings, aliases of mappings,
+// or sequences of those. The mappings are converted by decode and the key of their items is given by key.
+// Keys already present take precedence: when several mappings are merged, the first ones override the others.
+//
+// The aliases of the tree holding the merged nodes should be checked first with [CheckAliases].
+func Merge[S ~[]E, E any](s S, key func(E) string, merged []*yaml.Node, decode func(*yaml.Node) (S, error)) (S, error) {
+	seen := make(map[string]struct{}, len(s))
+	for _, item := range s {
+		seen[key(item)] = struct{}{}
+	}
+
+	for _, source := range merged {
+		sources := []*yaml.Node{source}
+		if resolved := resolveAlias(source); resolved.Kind == yaml.SequenceNode {
+			sources = resolved.Content
+		}
+
+		for _, mergedNode := range sources {
+			resolved := resolveAlias(mergedNode)
+			if resolved.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("expected a YAML mapping to merge at line %d, but got node kind %v", resolved.Line, resolved.Kind)
+			}
+
+			m, err := decode(resolved)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range m {
+				k := key(item)
+				if _, found := seen[k]; found {
+					continue
+				}
+
+				seen[k] = struct{}{}
+				s = append(s, item)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlnodes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestMerge(t *testing.T) {
+	type item struct {
+		Key   string
+		Value string
+	}
+
+	itemKey := func(i item) string { return i.Key }
+	decode := func(node *yaml.Node) ([]item, error) {
+		s := make([]item, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			s = append(s, item{Key: node.Content[i].Value, Value: node.Content[i+1].Value})
+		}
+
+		return s, nil
+	}
+
+	merged := func(t *testing.T, doc string) []*yaml.Node {
+		t.Helper()
+
+		var root yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &root))
+		require.NoError(t, CheckAliases(&root))
+
+		return []*yaml.Node{root.Content[0]}
+	}
+
+	t.Run("should append the keys of a merged mapping which are not already set", func(t *testing.T) {
+		s, err := Merge([]item{{Key: "a", Value: "1"}}, itemKey, merged(t, "{a: 2, b: 3}"), decode)
+		require.NoError(t, err)
+		assert.Equal(t, []item{{Key: "a", Value: "1"}, {Key: "b", Value: "3"}}, s)
+	})
+
+	t.Run("should give precedence to the first mappings of a sequence", func(t *testing.T) {
+		s, err := Merge([]item(nil), itemKey, merged(t, "[{a: 1}, {a: 2, b: 3}]"), decode)
+		require.NoError(t, err)
+		assert.Equal(t, []item{{Key: "a", Value: "1"}, {Key: "b", Value: "3"}}, s)
+	})
+
+	t.Run("should resolve aliases of mappings", func(t *testing.T) {
+		var root yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte("base: &base {a: 1}\nother: [*base]\n"), &root))
+
+		s, err := Merge([]item(nil), itemKey, []*yaml.Node{root.Content[0].Content[3]}, decode)
+		require.NoError(t, err)
+		assert.Equal(t, []item{{Key: "a", Value: "1"}}, s)
+	})
+
+	t.Run("should reject merged nodes which are not mappings", func(t *testing.T) {
+		_, err := Merge([]item(nil), itemKey, merged(t, "[{a: 1}, x]"), decode)
+		require.ErrorContains(t, err, "expected a YAML mapping to merge")
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// GetString returns the string value associated to a key.
+//
+// It returns "" and false if the key is missing or if the value is not a string.
+func (s JSONMapSlice) GetString(key string) (string, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		return "", false
+	}
+
+	str, ok := v.(string)
+
+	return str, ok
+}
+
+// GetInt64 returns the integer value associated to a key.
+//
+// Since unmarshaled numbers may be either int64 or float64, floats with no fractional part
+// are accepted, provided they fit in an int64. Other go integer types and [json.Number] values are accepted too.
+//
+// It returns 0 and false if the key is missing or if the value is not an integer.
+func (s JSONMapSlice) GetInt64(key string) (int64, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	return asInt64(v)
+}
+
+// GetFloat64 returns the numerical value associated to a key, as a float64.
+//
+// Any go numerical type and [json.Number] values are accepted. Large integers may lose precision.
+//
+// It returns 0 and false if the key is missing or if the value is not a number.
+func (s JSONMapSlice) GetFloat64(key string) (float64, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	return asFloat64(v)
+}
+
+// GetBool returns the boolean value associated to a key.
+//
+// It returns false and false if the key is missing or if the value is not a boolean.
+func (s JSONMapSlice) GetBool(key string) (bool, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		return false, false
+	}
+
+	b, ok := v.(bool)
+
+	return b, ok
+}
+
+// GetSlice returns the array value associated to a key.
+//
+// It returns nil and false if the key is missing or if the value is not a []any.
+func (s JSONMapSlice) GetSlice(key string) ([]any, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	a, ok := v.([]any)
+
+	return a, ok
+}
+
+// GetMap returns the object value associated to a key.
+//
+// It returns nil and false if the key is missing or if the value is not a [JSONMapSlice].
+func (s JSONMapSlice) GetMap(key string) (JSONMapSlice, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	m, ok := v.(JSONMapSlice)
+
+	return m, ok
+}
+
+func asInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return uintAsInt64(uint64(v))
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return uintAsInt64(v)
+	case float32:
+		return floatAsInt64(float64(v))
+	case float64:
+		return floatAsInt64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, true
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+
+		return floatAsInt64(f)
+	default:
+		return 0, false
+	}
+}
+
+func uintAsInt64(u uint64) (int64, bool) {
+	if u > math.MaxInt64 {
+		return 0, false
+	}
+
+	return int64(u), true
+}
+
+func floatAsInt64(f float64) (int64, bool) {
+	// -2^63 is exactly representable, but 2^63 overflows an int64
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= -math.MinInt64 {
+		return 0, false
+	}
+
+	return int64(f), true
+}
+
+func asFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+
+		return f, err == nil
+	default:
+		i, ok := asInt64(value)
+
+		return float64(i), ok
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceAccessors(t *testing.T) {
+	const sd = `{"s":"x","i":42,"f":1.5,"w":3.0,"b":true,"a":[1,"y"],"m":{"k":"v"},"n":null}`
+
+	var data JSONMapSlice
+	require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+
+	t.Run("should get values of the expected type", func(t *testing.T) {
+		str, ok := data.GetString("s")
+		require.True(t, ok)
+		assert.Equal(t, "x", str)
+
+		i, ok := data.GetInt64("i")
+		require.True(t, ok)
+		assert.Equal(t, int64(42), i)
+
+		f, ok := data.GetFloat64("f")
+		require.True(t, ok)
+		assert.InDelta(t, 1.5, f, 1e-9)
+
+		b, ok := data.GetBool("b")
+		require.True(t, ok)
+		assert.True(t, b)
+
+		a, ok := data.GetSlice("a")
+		require.True(t, ok)
+		assert.Equal(t, []any{int64(1), "y"}, a)
+
+		m, ok := data.GetMap("m")
+		require.True(t, ok)
+		assert.Equal(t, JSONMapSlice{{Key: "k", Value: "v"}}, m)
+	})
+
+	t.Run("should convert between integers and floats", func(t *testing.T) {
+		i, ok := data.GetInt64("w")
+		require.True(t, ok, "a whole float should be accepted as an integer")
+		assert.Equal(t, int64(3), i)
+
+		f, ok := data.GetFloat64("i")
+		require.True(t, ok)
+		assert.InDelta(t, 42.0, f, 1e-9)
+
+		_, ok = data.GetInt64("f")
+		assert.False(t, ok, "a float with a fractional part should not be accepted as an integer")
+
+		t.Run("should reject floats out of the range of int64", func(t *testing.T) {
+			for _, f := range []float64{math.Inf(1), math.Inf(-1), math.NaN(), 1e19, -1e19, math.MaxInt64} {
+				_, ok := JSONMapSlice{{Key: "f", Value: f}}.GetInt64("f")
+				assert.Falsef(t, ok, "expected %v to be rejected", f)
+			}
+
+			i, ok := JSONMapSlice{{Key: "f", Value: float64(math.MinInt64)}}.GetInt64("f")
+			require.True(t, ok)
+			assert.Equal(t, int64(math.MinInt64), i)
+		})
+
+		t.Run("should accept other go numerical types", func(t *testing.T) {
+			obj := JSONMapSlice{
+				{Key: "int", Value: 1},
+				{Key: "uint8", Value: uint8(2)},
+				{Key: "float32", Value: float32(3)},
+				{Key: "big", Value: uint64(math.MaxUint64)},
+			}
+
+			i, ok := obj.GetInt64("int")
+			require.True(t, ok)
+			assert.Equal(t, int64(1), i)
+
+			i, ok = obj.GetInt64("uint8")
+			require.True(t, ok)
+			assert.Equal(t, int64(2), i)
+
+			i, ok = obj.GetInt64("float32")
+			require.True(t, ok)
+			assert.Equal(t, int64(3), i)
+
+			_, ok = obj.GetInt64("big")
+			assert.False(t, ok)
+
+			f, ok := obj.GetFloat64("big")
+			require.True(t, ok)
+			assert.InDelta(t, float64(math.MaxUint64), f, 1)
+		})
+
+		t.Run("should accept json.Number values", func(t *testing.T) {
+			var numbers JSONMapSlice
+			require.NoError(t, numbers.UnmarshalJSONWithOptions([]byte(`{"i":12345678901234567,"w":2e3,"f":0.25}`), WithUseNumber(true)))
+
+			i, ok := numbers.GetInt64("i")
+			require.True(t, ok)
+			assert.Equal(t, int64(12345678901234567), i)
+
+			i, ok = numbers.GetInt64("w")
+			require.True(t, ok)
+			assert.Equal(t, int64(2000), i)
+
+			_, ok = numbers.GetInt64("f")
+			assert.False(t, ok)
+
+			f, ok := numbers.GetFloat64("f")
+			require.True(t, ok)
+			assert.InDelta(t, 0.25, f, 1e-9)
+
+			_, ok = JSONMapSlice{{Key: "n", Value: json.Number("x")}}.GetFloat64("n")
+			assert.False(t, ok)
+		})
+	})
+
+	t.Run("should return the zero value on a missing key", func(t *testing.T) {
+		str, ok := data.GetString("missing")
+		assert.False(t, ok)
+		assert.Empty(t, str)
+
+		i, ok := data.GetInt64("missing")
+		assert.False(t, ok)
+		assert.Zero(t, i)
+
+		f, ok := data.GetFloat64("missing")
+		assert.False(t, ok)
+		assert.Zero(t, f)
+
+		b, ok := data.GetBool("missing")
+		assert.False(t, ok)
+		assert.False(t, b)
+
+		a, ok := data.GetSlice("missing")
+		assert.False(t, ok)
+		assert.Nil(t, a)
+
+		m, ok := data.GetMap("missing")
+		assert.False(t, ok)
+		assert.Nil(t, m)
+
+		_, ok = JSONMapSlice(nil).GetString("s")
+		assert.False(t, ok)
+	})
+
+	t.Run("should return the zero value on a type mismatch", func(t *testing.T) {
+		for _, key := range []string{"i", "n", "m"} {
+			str, ok := data.GetString(key)
+			assert.False(t, ok)
+			assert.Empty(t, str)
+		}
+
+		for _, key := range []string{"s", "b", "n", "a"} {
+			i, ok := data.GetInt64(key)
+			assert.False(t, ok)
+			assert.Zero(t, i)
+
+			f, ok := data.GetFloat64(key)
+			assert.False(t, ok)
+			assert.Zero(t, f)
+		}
+
+		b, ok := data.GetBool("s")
+		assert.False(t, ok)
+		assert.False(t, b)
+
+		a, ok := data.GetSlice("m")
+		assert.False(t, ok)
+		assert.Nil(t, a)
+
+		m, ok := data.GetMap("a")
+		assert.False(t, ok)
+		assert.Nil(t, m)
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"bytes"
+	"io"
+)
+
+// utf8BOM is the byte order mark emitted by some tools at the start of UTF-8 documents.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// bomSkipper is a reader which strips a leading UTF-8 byte order mark from the input.
+//
+// Only the bytes which may start a BOM are read ahead, so a document which starts otherwise
+// is never held back.
+type bomSkipper struct {
+	r       io.Reader
+	started bool
+	buf     [3]byte
+	head    []byte // bytes read ahead, which are not a BOM
+	err     error  // error encountered while reading ahead
+}
+
+func skipBOM(r io.Reader) io.Reader {
+	return &bomSkipper{r: r}
+}
+
+func (b *bomSkipper) Read(p []byte) (int, error) {
+	if !b.started {
+		b.started = true
+		b.readAhead()
+	}
+
+	if len(b.head) > 0 {
+		n := copy(p, b.head)
+		b.head = b.head[n:]
+
+		return n, nil
+	}
+
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	return b.r.Read(p)
+}
+
+func (b *bomSkipper) readAhead() {
+	b.head = b.buf[:0]
+
+	for len(b.head) < len(utf8BOM) && bytes.HasPrefix(utf8BOM, b.head) && b.err == nil {
+		var n int
+		n, b.err = b.r.Read(b.buf[len(b.head):])
+		b.head = b.buf[:len(b.head)+n]
+	}
+
+	if bytes.Equal(b.head, utf8BOM) {
+		b.head = nil
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+// Builder constructs a [JSONMapSlice] fluently, with chainable methods.
+//
+// Keys are added in the order of calls.
+//
+// Example:
+//
+//	obj := NewBuilder().
+//		Set("type", "object").
+//		SetIf(len(required) > 0, "required", required).
+//		Object("properties", func(b *Builder) {
+//			b.Set("name", JSONMapSlice{{Key: "type", Value: "string"}})
+//		}).
+//		Build()
+//
+// The zero value is ready to use.
+type Builder struct {
+	s JSONMapSlice
+}
+
+// NewBuilder returns a [Builder] for a new, empty object.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Set the value of a key, like [JSONMapSlice.Set].
+//
+// If the key was already set, its value is updated and the key keeps its position.
+func (b *Builder) Set(key string, value any) *Builder {
+	b.s.Set(key, value)
+
+	return b
+}
+
+// SetIf sets the value of a key only when the condition is true.
+//
+// This is convenient for optional fields.
+func (b *Builder) SetIf(cond bool, key string, value any) *Builder {
+	if !cond {
+		return b
+	}
+
+	return b.Set(key, value)
+}
+
+// Object sets the value of a key to a nested object, built by the provided function.
+func (b *Builder) Object(key string, build func(*Builder)) *Builder {
+	nested := NewBuilder()
+	build(nested)
+
+	return b.Set(key, nested.Build())
+}
+
+// Build returns the object constructed so far.
+//
+// The result is never nil, so an empty builder renders as "{}". The builder may be reused afterwards
+// without affecting the returned object.
+func (b *Builder) Build() JSONMapSlice {
+	result := make(JSONMapSlice, len(b.s))
+	copy(result, b.s)
+
+	return result
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("should build an object in order", func(t *testing.T) {
+		obj := NewBuilder().Set("b", 1).Set("a", "x").Set("c", nil).Build()
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "b", Value: 1},
+			{Key: "a", Value: "x"},
+			{Key: "c", Value: nil},
+		}, obj)
+	})
+
+	t.Run("should update existing keys in place", func(t *testing.T) {
+		obj := NewBuilder().Set("a", 1).Set("b", 2).Set("a", 3).Build()
+
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: 3}, {Key: "b", Value: 2}}, obj)
+	})
+
+	t.Run("should set conditional fields", func(t *testing.T) {
+		build := func(required []string, deprecated bool) JSONMapSlice {
+			return NewBuilder().
+				Set("type", "object").
+				SetIf(len(required) > 0, "required", required).
+				SetIf(deprecated, "deprecated", true).
+				Build()
+		}
+
+		assert.Equal(t, JSONMapSlice{{Key: "type", Value: "object"}}, build(nil, false))
+		assert.Equal(t, JSONMapSlice{
+			{Key: "type", Value: "object"},
+			{Key: "required", Value: []string{"a"}},
+			{Key: "deprecated", Value: true},
+		}, build([]string{"a"}, true))
+	})
+
+	t.Run("should build nested objects", func(t *testing.T) {
+		obj := NewBuilder().
+			Set("type", "object").
+			Object("properties", func(b *Builder) {
+				b.Object("name", func(b *Builder) {
+					b.Set("type", "string").SetIf(false, "format", "email")
+				}).
+					Object("empty", func(*Builder) {})
+			}).
+			Build()
+
+		jazon, err := obj.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"object","properties":{"name":{"type":"string"},"empty":{}}}`, string(jazon))
+	})
+
+	t.Run("should build an empty object", func(t *testing.T) {
+		obj := NewBuilder().Build()
+		require.NotNil(t, obj)
+		assert.Equal(t, "{}", obj.String())
+
+		var zero Builder
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: 1}}, zero.Set("a", 1).Build())
+	})
+
+	t.Run("should not alter built objects when reusing the builder", func(t *testing.T) {
+		b := NewBuilder().Set("a", 1)
+		first := b.Build()
+
+		second := b.Set("a", 2).Set("b", 3).Build()
+
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: 1}}, first)
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: 2}, {Key: "b", Value: 3}}, second)
+	})
+}
@@ -0,0 +1,351 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// MarshalCanonical renders a [JSONMapSlice] as canonical JSON, following the JSON Canonicalization Scheme (JCS).
+//
+// The output is deterministic, and suitable for hashing or signing:
+//
+//   - the keys of all objects are sorted by their UTF-16 code units, as mandated by JCS
+//   - numbers are rendered like in ECMAScript, after conversion to an IEEE 754 double
+//   - strings are rendered with the minimal escaping required by JSON
+//   - there is no insignificant whitespace
+//
+// Values that are not known to [JSONMapSlice] (such as structs or maps) are first rendered with [WriteJSON],
+// then canonicalized.
+//
+// An error is returned for non-finite numbers, numbers out of the range of a double, strings that are not
+// valid UTF-8 and objects with duplicate keys, which can't be represented in canonical JSON.
+//
+// See https://www.rfc-editor.org/rfc/rfc8785
+func (s JSONMapSlice) MarshalCanonical() ([]byte, error) {
+	var e canonicalEncoder
+
+	return e.appendCanonical(make([]byte, 0, s.estimatedSize()), s)
+}
+
+// CanonicalHash returns the SHA-256 digest of the canonical JSON form of a [JSONMapSlice],
+// as rendered by [JSONMapSlice.MarshalCanonical].
+//
+// The canonical form is streamed through the hasher, and never fully held in memory.
+// The result is stable and independent of the order of keys, which makes it suitable
+// to compute an ETag or a cache key.
+//
+// The same errors as with [JSONMapSlice.MarshalCanonical] may be returned.
+func (s JSONMapSlice) CanonicalHash() ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	h := sha256.New()
+	e := canonicalEncoder{w: h}
+	buf, err := e.appendCanonical(make([]byte, 0, flushThreshold), s)
+	if err != nil {
+		return sum, err
+	}
+	_, _ = h.Write(buf) // a hash never returns an error
+
+	copy(sum[:], h.Sum(nil))
+
+	return sum, nil
+}
+
+// canonicalEncoder renders canonical JSON.
+//
+// When a writer is set, rendered bytes are flushed to it whenever they grow beyond [flushThreshold].
+type canonicalEncoder struct {
+	w io.Writer
+}
+
+// flushIfFull writes the rendered bytes to the writer when they grow beyond the threshold,
+// and returns the buffer to continue with.
+func (e canonicalEncoder) flushIfFull(dst []byte) ([]byte, error) {
+	if e.w == nil || len(dst) < flushThreshold {
+		return dst, nil
+	}
+
+	if _, err := e.w.Write(dst); err != nil {
+		return nil, err
+	}
+
+	return dst[:0], nil
+}
+
+func (e canonicalEncoder) appendCanonical(dst []byte, value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(dst, nullJSON...), nil
+	case JSONMapSlice:
+		if v == nil {
+			return append(dst, nullJSON...), nil
+		}
+
+		return e.appendCanonicalObject(dst, v)
+	case []any:
+		if v == nil {
+			return append(dst, nullJSON...), nil
+		}
+
+		return e.appendCanonicalArray(dst, v)
+	case string:
+		return appendCanonicalString(dst, v)
+	case bool:
+		return strconv.AppendBool(dst, v), nil
+	case json.Number:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot represent number %q in canonical JSON: %w", v, ErrJSON)
+		}
+
+		return appendCanonicalNumber(dst, f)
+	case float64:
+		return appendCanonicalNumber(dst, v)
+	case float32:
+		return appendCanonicalNumber(dst, float64(v))
+	case int:
+		return appendCanonicalNumber(dst, float64(v))
+	case int8:
+		return appendCanonicalNumber(dst, float64(v))
+	case int16:
+		return appendCanonicalNumber(dst, float64(v))
+	case int32:
+		return appendCanonicalNumber(dst, float64(v))
+	case int64:
+		return appendCanonicalNumber(dst, float64(v))
+	case uint:
+		return appendCanonicalNumber(dst, float64(v))
+	case uint8:
+		return appendCanonicalNumber(dst, float64(v))
+	case uint16:
+		return appendCanonicalNumber(dst, float64(v))
+	case uint32:
+		return appendCanonicalNumber(dst, float64(v))
+	case uint64:
+		return appendCanonicalNumber(dst, float64(v))
+	default:
+		// render opaque values, then canonicalize the result
+		jazon, err := WriteJSON(value)
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err := Unmarshal(jazon, WithUseNumber(true))
+		if err != nil {
+			return nil, err
+		}
+
+		return e.appendCanonical(dst, decoded)
+	}
+}
+
+func (e canonicalEncoder) appendCanonicalObject(dst []byte, s JSONMapSlice) ([]byte, error) {
+	type sortableItem struct {
+		key   []uint16
+		index int
+	}
+
+	items := make([]sortableItem, len(s))
+	for i, item := range s {
+		if !utf8.ValidString(item.Key) {
+			return nil, fmt.Errorf("cannot represent invalid UTF-8 key %q in canonical JSON: %w", item.Key, ErrJSON)
+		}
+
+		items[i] = sortableItem{key: utf16.Encode([]rune(item.Key)), index: i}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return compareUTF16(items[i].key, items[j].key) < 0
+	})
+
+	dst = append(dst, '{')
+	for i, item := range items {
+		if i > 0 {
+			if compareUTF16(items[i-1].key, item.key) == 0 {
+				return nil, fmt.Errorf("cannot represent duplicate key %q in canonical JSON: %w", s[item.index].Key, ErrJSON)
+			}
+
+			dst = append(dst, ',')
+		}
+
+		var err error
+		dst, err = appendCanonicalString(dst, s[item.index].Key)
+		if err != nil {
+			return nil, err
+		}
+
+		dst = append(dst, ':')
+		dst, err = e.appendCanonical(dst, s[item.index].Value)
+		if err != nil {
+			return nil, err
+		}
+
+		dst, err = e.flushIfFull(dst)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(dst, '}'), nil
+}
+
+func (e canonicalEncoder) appendCanonicalArray(dst []byte, a []any) ([]byte, error) {
+	dst = append(dst, '[')
+	for i, elem := range a {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		var err error
+		dst, err = e.appendCanonical(dst, elem)
+		if err != nil {
+			return nil, err
+		}
+
+		dst, err = e.flushIfFull(dst)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+func compareUTF16(left, right []uint16) int {
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if left[i] != right[i] {
+			if left[i] < right[i] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return len(left) - len(right)
+}
+
+// appendCanonicalString renders a string with the minimal escaping required by JSON.
+func appendCanonicalString(dst []byte, str string) ([]byte, error) {
+	if !utf8.ValidString(str) {
+		return nil, fmt.Errorf("cannot represent invalid UTF-8 string %q in canonical JSON: %w", str, ErrJSON)
+	}
+
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+
+		dst = append(dst, str[start:i]...)
+		switch c {
+		case '"', '\\':
+			dst = append(dst, '\\', c)
+		case '\b':
+			dst = append(dst, '\\', 'b')
+		case '\f':
+			dst = append(dst, '\\', 'f')
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		}
+		start = i + 1
+	}
+	dst = append(dst, str[start:]...)
+
+	return append(dst, '"'), nil
+}
+
+// appendCanonicalNumber renders a number like the ECMAScript Number.prototype.toString method.
+func appendCanonicalNumber(dst []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("cannot represent non-finite number %v in canonical JSON: %w", f, ErrJSON)
+	}
+
+	if f == 0 { // also applies to -0
+		return append(dst, '0'), nil
+	}
+
+	if f < 0 {
+		dst = append(dst, '-')
+		f = -f
+	}
+
+	// the shortest representation that round-trips, as d.ddde±x
+	repr := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(repr, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+
+	// the value is 0.digits × 10^n
+	n := e + 1
+	k := len(digits)
+
+	const maxDecimalExponent = 21
+
+	switch {
+	case k <= n && n <= maxDecimalExponent:
+		// integer: digits followed by n-k zeros
+		dst = append(dst, digits...)
+		for i := k; i < n; i++ {
+			dst = append(dst, '0')
+		}
+	case 0 < n && n <= maxDecimalExponent:
+		// decimal with the decimal point inside the digits
+		dst = append(dst, digits[:n]...)
+		dst = append(dst, '.')
+		dst = append(dst, digits[n:]...)
+	case -6 < n && n <= 0:
+		// decimal with leading zeros
+		dst = append(dst, '0', '.')
+		for i := n; i < 0; i++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, digits...)
+	default:
+		// exponential notation
+		dst = append(dst, digits[0])
+		if k > 1 {
+			dst = append(dst, '.')
+			dst = append(dst, digits[1:]...)
+		}
+		dst = append(dst, 'e')
+		if n-1 >= 0 {
+			dst = append(dst, '+')
+		}
+		dst = strconv.AppendInt(dst, int64(n-1), 10)
+	}
+
+	return dst, nil
+}
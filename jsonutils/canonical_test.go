@@ -0,0 +1,243 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceMarshalCanonical(t *testing.T) {
+	canonical := func(t *testing.T, sd string) string {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		jazon, err := data.MarshalCanonical()
+		require.NoError(t, err)
+
+		return string(jazon)
+	}
+
+	t.Run("should match the RFC 8785 example", func(t *testing.T) {
+		// RFC 8785, section 3.2.2
+		const sd = `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`
+
+		assert.Equal(t,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+			canonical(t, sd),
+		)
+	})
+
+	t.Run("should sort keys by UTF-16 code units", func(t *testing.T) {
+		// RFC 8785, section 3.2.3
+		const sd = `{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`
+
+		assert.Equal(t,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\","+
+				"\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+			canonical(t, sd),
+		)
+	})
+
+	t.Run("should sort keys recursively and preserve arrays", func(t *testing.T) {
+		assert.Equal(t,
+			`{"a":[{"x":1,"y":2},3,[{"b":1,"c":2}]],"b":{"a":{},"b":[]}}`,
+			canonical(t, `{"b":{"b":[],"a":{}},"a":[{"y":2,"x":1},3,[{"c":2,"b":1}]]}`),
+		)
+	})
+
+	t.Run("should render numbers like ECMAScript", func(t *testing.T) {
+		// RFC 8785, appendix B
+		for _, fixture := range []struct {
+			Bits     uint64
+			Expected string
+		}{
+			{Bits: 0x0000000000000000, Expected: "0"},
+			{Bits: 0x8000000000000000, Expected: "0"},
+			{Bits: 0x0000000000000001, Expected: "5e-324"},
+			{Bits: 0x8000000000000001, Expected: "-5e-324"},
+			{Bits: 0x7fefffffffffffff, Expected: "1.7976931348623157e+308"},
+			{Bits: 0xffefffffffffffff, Expected: "-1.7976931348623157e+308"},
+			{Bits: 0x4340000000000000, Expected: "9007199254740992"},
+			{Bits: 0xc340000000000000, Expected: "-9007199254740992"},
+			{Bits: 0x4430000000000000, Expected: "295147905179352830000"},
+			{Bits: 0x44b52d02c7e14af5, Expected: "9.999999999999997e+22"},
+			{Bits: 0x44b52d02c7e14af6, Expected: "1e+23"},
+			{Bits: 0x44b52d02c7e14af7, Expected: "1.0000000000000001e+23"},
+			{Bits: 0x444b1ae4d6e2ef4e, Expected: "999999999999999700000"},
+			{Bits: 0x444b1ae4d6e2ef4f, Expected: "999999999999999900000"},
+			{Bits: 0x444b1ae4d6e2ef50, Expected: "1e+21"},
+			{Bits: 0x3eb0c6f7a0b5ed8c, Expected: "9.999999999999997e-7"},
+			{Bits: 0x3eb0c6f7a0b5ed8d, Expected: "0.000001"},
+			{Bits: 0x41b3de4355555553, Expected: "333333333.3333332"},
+			{Bits: 0x41b3de4355555554, Expected: "333333333.33333325"},
+			{Bits: 0x41b3de4355555555, Expected: "333333333.3333333"},
+			{Bits: 0x41b3de4355555556, Expected: "333333333.3333334"},
+			{Bits: 0x41b3de4355555557, Expected: "333333333.33333343"},
+			{Bits: 0xbecbf647612f3696, Expected: "-0.0000033333333333333333"},
+			{Bits: 0x43143ff3c1cb0959, Expected: "1424953923781206.2"},
+		} {
+			data := JSONMapSlice{{Key: "n", Value: math.Float64frombits(fixture.Bits)}}
+			jazon, err := data.MarshalCanonical()
+			require.NoError(t, err)
+			assert.Equalf(t, `{"n":`+fixture.Expected+`}`, string(jazon), "for bits %#x", fixture.Bits)
+		}
+	})
+
+	t.Run("should render numbers of any type", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "a", Value: int64(9007199254740993)},
+			{Key: "b", Value: json.Number("1.50E2")},
+			{Key: "c", Value: uint8(7)},
+			{Key: "d", Value: float32(0.5)},
+			{Key: "e", Value: -1},
+		}
+
+		jazon, err := data.MarshalCanonical()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":9007199254740992,"b":150,"c":7,"d":0.5,"e":-1}`, string(jazon))
+	})
+
+	t.Run("should escape strings minimally", func(t *testing.T) {
+		data := JSONMapSlice{{Key: "s", Value: "<&> é\x00\x1f\t\"\\/"}}
+
+		jazon, err := data.MarshalCanonical()
+		require.NoError(t, err)
+		assert.Equal(t, `{"s":"<&>`+" "+`é\u0000\u001f\t\"\\/"}`, string(jazon))
+	})
+
+	t.Run("should canonicalize opaque values", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "map", Value: map[string]any{"b": 1.50, "a": "<x>"}},
+			{Key: "raw", Value: json.RawMessage(`{ "z": 1.0, "y": [1e1] }`)},
+			{Key: "struct", Value: SharedCounters{Counter1: 1, Counter2: 2}},
+			{Key: "nil", Value: JSONMapSlice(nil)},
+		}
+
+		jazon, err := data.MarshalCanonical()
+		require.NoError(t, err)
+		assert.Equal(t, `{"map":{"a":"<x>","b":1.5},"nil":null,"raw":{"y":[10],"z":1},"struct":{"counter1":1,"counter2:":2}}`, string(jazon))
+	})
+
+	t.Run("should be deterministic", func(t *testing.T) {
+		assert.Equal(t,
+			canonical(t, `{"b":[1.0,{"d":2,"c":1}],"a":"x"}`),
+			canonical(t, `{"a":"x","b":[1,{"c":1,"d":2e0}]}`),
+		)
+	})
+
+	t.Run("should marshal nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		jazon, err := empty.MarshalCanonical()
+		require.NoError(t, err)
+		assert.Equal(t, `null`, string(jazon))
+
+		jazon, err = JSONMapSlice{}.MarshalCanonical()
+		require.NoError(t, err)
+		assert.Equal(t, `{}`, string(jazon))
+	})
+
+	t.Run("should fail on values without a canonical form", func(t *testing.T) {
+		for _, data := range []JSONMapSlice{
+			{{Key: "a", Value: math.NaN()}},
+			{{Key: "a", Value: math.Inf(-1)}},
+			{{Key: "a", Value: json.Number("1e400")}},
+			{{Key: "a", Value: "\xff"}},
+			{{Key: "\xff", Value: 1}},
+			{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "a", Value: 3}},
+			{{Key: "a", Value: []any{JSONMapSlice{{Key: "x", Value: 1}, {Key: "x", Value: 1}}}}},
+			{{Key: "a", Value: func() {}}},
+		} {
+			_, err := data.MarshalCanonical()
+			require.Errorf(t, err, "expected an error for %v", data)
+		}
+	})
+}
+
+func TestJSONMapSliceCanonicalHash(t *testing.T) {
+	hash := func(t *testing.T, sd string) [sha256.Size]byte {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		sum, err := data.CanonicalHash()
+		require.NoError(t, err)
+
+		return sum
+	}
+
+	t.Run("should not depend on the order of keys", func(t *testing.T) {
+		assert.Equal(t,
+			hash(t, `{"b":[1.0,{"d":2,"c":1}],"a":"x","e":{"g":null,"f":true}}`),
+			hash(t, `{"e":{"f":true,"g":null},"a":"x","b":[1,{"c":1,"d":2e0}]}`),
+		)
+	})
+
+	t.Run("should differ for different objects", func(t *testing.T) {
+		assert.NotEqual(t, hash(t, `{"a":[1,2]}`), hash(t, `{"a":[2,1]}`))
+		assert.NotEqual(t, hash(t, `{"a":1}`), hash(t, `{"a":"1"}`))
+	})
+
+	t.Run("should hash the canonical form", func(t *testing.T) {
+		for _, data := range []JSONMapSlice{
+			nil,
+			{},
+			{{Key: "b", Value: 1}, {Key: "a", Value: "x"}},
+			makeSpecLikeMapSlice(100), // larger than the flush threshold
+		} {
+			jazon, err := data.MarshalCanonical()
+			require.NoError(t, err)
+
+			sum, err := data.CanonicalHash()
+			require.NoError(t, err)
+			assert.Equal(t, sha256.Sum256(jazon), sum)
+		}
+	})
+
+	t.Run("should be stable", func(t *testing.T) {
+		sum := hash(t, `{"b":2,"a":1}`)
+		assert.Equal(t, "43258cff783fe7036d8a43033f830adfc60ec037382473548ac742b888292777", hex.EncodeToString(sum[:]))
+	})
+
+	t.Run("should fail on values without a canonical form", func(t *testing.T) {
+		data := JSONMapSlice{{Key: "a", Value: []any{1, math.NaN()}}}
+
+		sum, err := data.CanonicalHash()
+		require.Error(t, err)
+		assert.Zero(t, sum)
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"bufio"
+	"io"
+)
+
+// commaSkipper is a reader which strips trailing commas before the closing delimiter of JSON objects and arrays,
+// e.g. `[1,2,]` is read as `[1,2]`.
+//
+// Strings are left untouched. Commas which do not follow a value, like in `[,]` or `[1,,]`, are retained,
+// so the decoder still rejects them.
+type commaSkipper struct {
+	r            *bufio.Reader
+	err          error
+	inString     bool
+	escaped      bool
+	pendingComma bool // a comma after a value, not yet known to be trailing
+	last         byte // last byte emitted outside of strings, other than white space
+}
+
+func skipTrailingCommas(r io.Reader) io.Reader {
+	return &commaSkipper{r: bufio.NewReader(r)}
+}
+
+func (s *commaSkipper) Read(p []byte) (int, error) {
+	var n int
+
+	for n < len(p) {
+		if n > 0 && s.r.Buffered() == 0 {
+			// don't block on the underlying reader when some content is available already
+			break
+		}
+
+		if s.err != nil {
+			break
+		}
+
+		c, err := s.r.ReadByte()
+		if err != nil {
+			s.err = err
+			if s.pendingComma {
+				// the input ends after a comma: let the decoder report the error
+				s.pendingComma = false
+				p[n] = ','
+				n++
+			}
+
+			break
+		}
+
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case c == '\\':
+				s.escaped = true
+			case c == '"':
+				s.inString = false
+				s.last = c
+			}
+			p[n] = c
+			n++
+
+			continue
+		}
+
+		if s.pendingComma {
+			if isJSONWhiteSpace(c) {
+				p[n] = c
+				n++
+
+				continue
+			}
+
+			s.pendingComma = false
+			if c != '}' && c != ']' {
+				// not a trailing comma: emit it, then process the current byte again
+				_ = s.r.UnreadByte()
+				s.last = ','
+				p[n] = ','
+				n++
+
+				continue
+			}
+		}
+
+		switch {
+		case c == ',' && s.followsValue():
+			s.pendingComma = true
+
+			continue
+		case c == '"':
+			s.inString = true
+		}
+
+		if !isJSONWhiteSpace(c) {
+			s.last = c
+		}
+		p[n] = c
+		n++
+	}
+
+	if n > 0 {
+		return n, nil
+	}
+
+	return 0, s.err
+}
+
+// followsValue reports whether the last byte ends a JSON value.
+func (s *commaSkipper) followsValue() bool {
+	switch s.last {
+	case 0, ',', ':', '[', '{':
+		return false
+	default:
+		return true
+	}
+}
+
+func isJSONWhiteSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// StripJSONComments removes line comments ("// ...") and block comments ("/* ... */") from JSONC input,
+// so the result may be passed to [JSONMapSlice.UnmarshalJSON] or any standard JSON decoder.
+//
+// Sequences such as "//" or "/*" inside string literals are not comments and are retained.
+// A block comment is replaced by a single space, so that tokens on both sides of it remain separate,
+// and the end of line after a line comment is retained.
+//
+// An unterminated block comment yields an error. The remaining content is not validated.
+func StripJSONComments(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("/")) {
+		return data, nil
+	}
+
+	result := make([]byte, 0, len(data))
+	var inString, escaped bool
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			result = append(result, c)
+
+			continue
+		}
+
+		if c != '/' || i+1 >= len(data) {
+			if c == '"' {
+				inString = true
+			}
+			result = append(result, c)
+
+			continue
+		}
+
+		switch data[i+1] {
+		case '/':
+			end := bytes.IndexAny(data[i+2:], "\r\n")
+			if end < 0 {
+				return result, nil
+			}
+			i += 1 + end // resume at the end of line
+		case '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at offset %d: %w", i, ErrJSON)
+			}
+			result = append(result, ' ')
+			i += 3 + end // resume after the closing "*/"
+		default:
+			result = append(result, c)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripJSONComments(t *testing.T) {
+	t.Run("should strip comments from JSONC", func(t *testing.T) {
+		const jsonc = `// leading comment
+{
+	"url": "https://example.com/a//b", // a URL is not a comment
+	"glob": "/*.json",/* a glob is not a comment */"quote": "a \"//\" b",
+	/* a comment
+	   on several lines */
+	"n": 1/**/
+}
+// trailing comment`
+
+		stripped, err := StripJSONComments([]byte(jsonc))
+		require.NoError(t, err)
+
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSON(stripped))
+		assert.Equal(t, JSONMapSlice{
+			{Key: "url", Value: "https://example.com/a//b"},
+			{Key: "glob", Value: "/*.json"},
+			{Key: "quote", Value: `a "//" b`},
+			{Key: "n", Value: int64(1)},
+		}, data)
+	})
+
+	t.Run("should render standard JSON", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Input    string
+			Expected string
+		}{
+			{Input: `{"a":1}`, Expected: `{"a":1}`},
+			{Input: `{"a":"//"}//x`, Expected: `{"a":"//"}`},
+			{Input: "{\"a\":1}// x\r\n", Expected: "{\"a\":1}\r\n"},
+			{Input: `{"a":/* x */1}`, Expected: `{"a": 1}`},
+			{Input: `[1/**/2]`, Expected: `[1 2]`},
+			{Input: `{"a\\":"/*"}`, Expected: `{"a\\":"/*"}`},
+			{Input: `{"a":1}/`, Expected: `{"a":1}/`},
+		} {
+			stripped, err := StripJSONComments([]byte(fixture.Input))
+			require.NoError(t, err)
+			assert.Equal(t, fixture.Expected, string(stripped))
+		}
+	})
+
+	t.Run("should reject an unterminated block comment", func(t *testing.T) {
+		_, err := StripJSONComments([]byte(`{"a":1} /* never closed *`))
+		require.ErrorIs(t, err, ErrJSON)
+	})
+}
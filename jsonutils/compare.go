@@ -0,0 +1,152 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+)
+
+// Equal compares two [JSONMapSlice] values, including the order of keys.
+//
+// Nested [JSONMapSlice] and []any values are compared recursively.
+//
+// Numbers are compared by their mathematical value, regardless of their go type, e.g. int64(1) and float64(1)
+// are equal. Other values are compared with [reflect.DeepEqual].
+func (s JSONMapSlice) Equal(other JSONMapSlice) bool {
+	return equalObjects(s, other, true)
+}
+
+// EqualUnordered compares two [JSONMapSlice] values, like [JSONMapSlice.Equal], but ignores the order of keys
+// in objects at any level.
+//
+// The order of elements in arrays is still relevant.
+func (s JSONMapSlice) EqualUnordered(other JSONMapSlice) bool {
+	return equalObjects(s, other, false)
+}
+
+func equalObjects(left, right JSONMapSlice, ordered bool) bool {
+	if (left == nil) != (right == nil) || len(left) != len(right) {
+		return false
+	}
+
+	if ordered {
+		for i := range left {
+			if left[i].Key != right[i].Key || !equalValues(left[i].Value, right[i].Value, ordered) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for _, item := range left {
+		v, ok := right.Get(item.Key)
+		if !ok || !equalValues(item.Value, v, ordered) {
+			return false
+		}
+	}
+
+	for _, item := range right {
+		if !left.Has(item.Key) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalValues(left, right any, ordered bool) bool {
+	left, _ = resolveLazy(left)
+	right, _ = resolveLazy(right)
+
+	switch l := left.(type) {
+	case JSONMapSlice:
+		r, ok := right.(JSONMapSlice)
+
+		return ok && equalObjects(l, r, ordered)
+	case []any:
+		r, ok := right.([]any)
+		if !ok || (l == nil) != (r == nil) || len(l) != len(r) {
+			return false
+		}
+
+		for i := range l {
+			if !equalValues(l[i], r[i], ordered) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if ln, isNumber := asRat(left); isNumber {
+		rn, ok := asRat(right)
+		if !ok {
+			return false
+		}
+
+		if ln == nil || rn == nil {
+			// non-finite floats
+			return reflect.DeepEqual(left, right)
+		}
+
+		return ln.Cmp(rn) == 0
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+// asRat converts any numerical value into an exact rational number.
+//
+// The returned value is nil for non-finite floats.
+func asRat(value any) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(v)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(v)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(v)), true
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(v)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(v)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(v)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(v)), true
+	case uint64:
+		return new(big.Rat).SetUint64(v), true
+	case float32:
+		return new(big.Rat).SetFloat64(float64(v)), true
+	case float64:
+		return new(big.Rat).SetFloat64(v), true
+	case json.Number:
+		r, ok := new(big.Rat).SetString(v.String())
+		if !ok {
+			return nil, false
+		}
+
+		return r, true
+	default:
+		return nil, false
+	}
+}
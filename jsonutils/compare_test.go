@@ -0,0 +1,110 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceEqual(t *testing.T) {
+	t.Run("should be equal", func(t *testing.T) {
+		const sd = `{"a":1,"b":{"c":[1,{"d":"x"},[true,null]]},"e":1.5}`
+		var left, right JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &left))
+		require.NoError(t, json.Unmarshal([]byte(sd), &right))
+
+		assert.True(t, left.Equal(right))
+		assert.True(t, left.EqualUnordered(right))
+		assert.True(t, left.Equal(left.Clone()))
+	})
+
+	t.Run("should be equal when empty or nil", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		assert.True(t, empty.Equal(nil))
+		assert.True(t, JSONMapSlice{}.Equal(JSONMapSlice{}))
+		assert.False(t, empty.Equal(JSONMapSlice{}))
+		assert.False(t, JSONMapSlice{}.EqualUnordered(nil))
+	})
+
+	t.Run("should compare numbers regardless of their type", func(t *testing.T) {
+		left := JSONMapSlice{
+			{Key: "a", Value: int64(1)},
+			{Key: "b", Value: []any{int64(2), float64(2.5)}},
+			{Key: "c", Value: JSONMapSlice{{Key: "d", Value: uint8(3)}}},
+			{Key: "e", Value: json.Number("9007199254740993")},
+		}
+		right := JSONMapSlice{
+			{Key: "a", Value: float64(1)},
+			{Key: "b", Value: []any{2, json.Number("2.50")}},
+			{Key: "c", Value: JSONMapSlice{{Key: "d", Value: float32(3)}}},
+			{Key: "e", Value: int64(9007199254740993)},
+		}
+
+		assert.True(t, left.Equal(right))
+		assert.True(t, right.Equal(left))
+
+		t.Run("with different mathematical values", func(t *testing.T) {
+			assert.False(t, JSONMapSlice{{Key: "a", Value: int64(1)}}.Equal(JSONMapSlice{{Key: "a", Value: 1.1}}))
+			assert.False(t, JSONMapSlice{{Key: "a", Value: int64(9007199254740993)}}.Equal(JSONMapSlice{{Key: "a", Value: float64(9007199254740992)}}))
+			assert.False(t, JSONMapSlice{{Key: "a", Value: int64(1)}}.Equal(JSONMapSlice{{Key: "a", Value: "1"}}))
+		})
+
+		t.Run("with non-finite floats", func(t *testing.T) {
+			assert.True(t, JSONMapSlice{{Key: "a", Value: math.Inf(1)}}.Equal(JSONMapSlice{{Key: "a", Value: math.Inf(1)}}))
+			assert.False(t, JSONMapSlice{{Key: "a", Value: math.Inf(1)}}.Equal(JSONMapSlice{{Key: "a", Value: int64(1)}}))
+			assert.False(t, JSONMapSlice{{Key: "a", Value: math.NaN()}}.Equal(JSONMapSlice{{Key: "a", Value: math.NaN()}}))
+		})
+	})
+
+	t.Run("should take the order of keys into account", func(t *testing.T) {
+		var left, right JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":{"c":1,"d":[{"x":1,"y":2}]}}`), &left))
+		require.NoError(t, json.Unmarshal([]byte(`{"b":{"d":[{"y":2,"x":1.0}],"c":1},"a":1}`), &right))
+
+		assert.False(t, left.Equal(right))
+		assert.True(t, left.EqualUnordered(right))
+
+		t.Run("with nested ordering differences only", func(t *testing.T) {
+			var nested JSONMapSlice
+			require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":{"d":[{"x":1,"y":2}],"c":1}}`), &nested))
+
+			assert.False(t, left.Equal(nested))
+			assert.True(t, left.EqualUnordered(nested))
+		})
+	})
+
+	t.Run("should not be equal", func(t *testing.T) {
+		base := JSONMapSlice{{Key: "a", Value: []any{1, 2}}, {Key: "b", Value: "x"}}
+
+		for _, other := range []JSONMapSlice{
+			{{Key: "a", Value: []any{1, 2}}},
+			{{Key: "a", Value: []any{1, 2}}, {Key: "c", Value: "x"}},
+			{{Key: "a", Value: []any{2, 1}}, {Key: "b", Value: "x"}},
+			{{Key: "a", Value: []any{1, 2, 3}}, {Key: "b", Value: "x"}},
+			{{Key: "a", Value: []any(nil)}, {Key: "b", Value: "x"}},
+			{{Key: "a", Value: JSONMapSlice{}}, {Key: "b", Value: "x"}},
+			{{Key: "a", Value: []any{1, 2}}, {Key: "b", Value: nil}},
+		} {
+			assert.False(t, base.Equal(other))
+			assert.False(t, base.EqualUnordered(other))
+		}
+	})
+}
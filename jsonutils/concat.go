@@ -16,6 +16,7 @@ package jsonutils
 
 import (
 	"bytes"
+	"fmt"
 )
 
 // nullJSON represents a JSON object with null type
@@ -98,3 +99,59 @@ func ConcatJSON(blobs ...[]byte) []byte {
 
 	return buf.Bytes()
 }
+
+// Concat concatenates several [JSONMapSlice] objects into a new one.
+//
+// Keys are appended in the order of the objects provided, then in their original order within each object.
+// When the same key appears several times, the last value wins: the key retains the position of its
+// first occurrence and takes the value of its last occurrence. Values are not merged recursively
+// (see [JSONMapSlice.Merge] for a deep merge).
+//
+// Nil slices are skipped. Concat returns nil if all slices are nil.
+func Concat(slices ...JSONMapSlice) JSONMapSlice {
+	var result JSONMapSlice
+	var seen map[string]int
+
+	for _, s := range slices {
+		if s == nil {
+			continue
+		}
+
+		if result == nil {
+			result = make(JSONMapSlice, 0, len(s))
+			seen = make(map[string]int, len(s))
+		}
+
+		for _, item := range s {
+			if i, isDuplicate := seen[item.Key]; isDuplicate {
+				result[i].Value = item.Value
+
+				continue
+			}
+
+			seen[item.Key] = len(result)
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// ConcatBytes parses several JSON objects and concatenates them into a single JSON object,
+// preserving the order of keys.
+//
+// Duplicate keys are resolved like with [Concat]: the last value wins.
+// Empty blobs and JSON nulls are skipped. If no object is found, the result is a JSON null.
+func ConcatBytes(blobs ...[]byte) ([]byte, error) {
+	slices := make([]JSONMapSlice, 0, len(blobs))
+	for i, blob := range blobs {
+		s, err := ReadJSONMapSlice(blob)
+		if err != nil {
+			return nil, fmt.Errorf("could not concatenate JSON object at index %d: %w", i, err)
+		}
+
+		slices = append(slices, s)
+	}
+
+	return Concat(slices...).MarshalJSON()
+}
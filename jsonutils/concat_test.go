@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //nolint:testifylint
@@ -124,3 +125,86 @@ func TestJSONConcatenation(t *testing.T) {
 		)
 	})
 }
+
+func TestConcat(t *testing.T) {
+	t.Run("should concat two objects with overlapping keys", func(t *testing.T) {
+		left := JSONMapSlice{{Key: "a", Value: 1}, {Key: "b", Value: JSONMapSlice{{Key: "c", Value: 1}}}}
+		right := JSONMapSlice{{Key: "d", Value: 2}, {Key: "b", Value: JSONMapSlice{{Key: "e", Value: 2}}}, {Key: "a", Value: 3}}
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "a", Value: 3},
+			{Key: "b", Value: JSONMapSlice{{Key: "e", Value: 2}}},
+			{Key: "d", Value: 2},
+		}, Concat(left, right))
+
+		t.Run("should not mutate the inputs", func(t *testing.T) {
+			assert.Equal(t, JSONMapSlice{{Key: "a", Value: 1}, {Key: "b", Value: JSONMapSlice{{Key: "c", Value: 1}}}}, left)
+			assert.Equal(t, JSONMapSlice{{Key: "d", Value: 2}, {Key: "b", Value: JSONMapSlice{{Key: "e", Value: 2}}}, {Key: "a", Value: 3}}, right)
+		})
+	})
+
+	t.Run("should concat three objects with overlapping keys", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "a", Value: "third"},
+			{Key: "b", Value: "second"},
+			{Key: "c", Value: "first"},
+			{Key: "d", Value: "third"},
+		}, Concat(
+			JSONMapSlice{{Key: "a", Value: "first"}, {Key: "b", Value: "first"}, {Key: "c", Value: "first"}},
+			JSONMapSlice{{Key: "b", Value: "second"}, {Key: "a", Value: "second"}},
+			JSONMapSlice{{Key: "d", Value: "third"}, {Key: "a", Value: "third"}},
+		))
+	})
+
+	t.Run("should collapse duplicate keys within an object", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: 2}, {Key: "b", Value: 1}},
+			Concat(JSONMapSlice{{Key: "a", Value: 1}, {Key: "b", Value: 1}, {Key: "a", Value: 2}}),
+		)
+	})
+
+	t.Run("should concat nil or empty objects", func(t *testing.T) {
+		assert.Nil(t, Concat())
+		assert.Nil(t, Concat(nil, nil))
+		assert.Equal(t, JSONMapSlice{}, Concat(nil, JSONMapSlice{}))
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: 1}}, Concat(nil, JSONMapSlice{{Key: "a", Value: 1}}, JSONMapSlice{}))
+	})
+}
+
+func TestConcatBytes(t *testing.T) {
+	t.Run("should concat two objects with overlapping keys", func(t *testing.T) {
+		jazon, err := ConcatBytes([]byte(`{"a":1,"b":{"c":1}}`), []byte(`{"d":2,"b":{"e":2},"a":3}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":3,"b":{"e":2},"d":2}`, string(jazon))
+	})
+
+	t.Run("should concat three objects with overlapping keys", func(t *testing.T) {
+		jazon, err := ConcatBytes(
+			[]byte(`{"z":"first","y":"first"}`),
+			[]byte(`null`),
+			[]byte(`{"y":"second","x":[1,2]}`),
+			nil,
+			[]byte(`{"x":[3],"w":"third"}`),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, `{"z":"first","y":"second","x":[3],"w":"third"}`, string(jazon))
+	})
+
+	t.Run("should concat nothing", func(t *testing.T) {
+		jazon, err := ConcatBytes()
+		require.NoError(t, err)
+		assert.Equal(t, `null`, string(jazon))
+
+		jazon, err = ConcatBytes([]byte(`{}`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, `{}`, string(jazon))
+	})
+
+	t.Run("should fail on invalid objects", func(t *testing.T) {
+		_, err := ConcatBytes([]byte(`{"a":1}`), []byte(`[1,2]`))
+		require.ErrorIs(t, err, ErrJSON)
+		assert.Contains(t, err.Error(), "index 1")
+
+		_, err = ConcatBytes([]byte(`{"a":`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// ToMap converts a [JSONMapSlice] into a map[string]any.
+//
+// Nested [JSONMapSlice] objects are converted recursively, including inside []any arrays.
+//
+// Notice that the order of keys is lost by design. If a key appears several times,
+// the last value is retained.
+func (s JSONMapSlice) ToMap() map[string]any {
+	if s == nil {
+		return nil
+	}
+
+	m := make(map[string]any, len(s))
+	for _, item := range s {
+		m[item.Key] = toMapValue(item.Value)
+	}
+
+	return m
+}
+
+func toMapValue(value any) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		return v.ToMap()
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		a := make([]any, len(v))
+		for i, elem := range v {
+			a[i] = toMapValue(elem)
+		}
+
+		return a
+	default:
+		return value
+	}
+}
+
+// FromMap converts a map[string]any into a [JSONMapSlice].
+//
+// Nested map[string]any objects are converted recursively, including inside []any arrays.
+//
+// Since maps are not ordered, keys are sorted lexicographically to produce a deterministic result.
+func FromMap(m map[string]any) JSONMapSlice {
+	if m == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	s := make(JSONMapSlice, 0, len(m))
+	for _, k := range keys {
+		s = append(s, JSONMapItem{Key: k, Value: fromMapValue(m[k])})
+	}
+
+	return s
+}
+
+func fromMapValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return FromMap(v)
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		a := make([]any, len(v))
+		for i, elem := range v {
+			a[i] = fromMapValue(elem)
+		}
+
+		return a
+	default:
+		return value
+	}
+}
+
+// ToURLValues converts a flat [JSONMapSlice] into [url.Values], e.g. to render form parameters.
+//
+// Values are rendered as text: strings are used as is, nil values (including nil pointers) as an empty string, and other scalars
+// such as numbers or booleans like they are rendered in JSON. A key appearing several times gets several values,
+// in their original order. However, [url.Values] is a map, so the order of distinct keys is lost,
+// and [url.Values.Encode] sorts them.
+//
+// An error is returned if a value is an object or an array, which can't be represented in a flat form.
+func (s JSONMapSlice) ToURLValues() (url.Values, error) {
+	values := make(url.Values, len(s))
+	for i := range s {
+		text, err := urlValue(s[i].Value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert key %q to url values: %w", s[i].Key, err)
+		}
+
+		values.Add(s[i].Key, text)
+	}
+
+	return values, nil
+}
+
+func urlValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case JSONMapSlice, []any, *LazyValue:
+		return "", fmt.Errorf("nested value of type %T is not supported: %w", value, ErrJSON)
+	}
+
+	jazon, err := WriteJSON(value)
+	if err != nil {
+		return "", err
+	}
+
+	if len(jazon) == 0 || string(jazon) == string(nullJSON) {
+		return "", nil
+	}
+
+	switch jazon[0] {
+	case '{', '[':
+		return "", fmt.Errorf("value of type %T is rendered as a nested JSON value: %w", value, ErrJSON)
+	case '"':
+		// e.g. values implementing encoding.TextMarshaler
+		var text string
+		if err := json.Unmarshal(jazon, &text); err != nil {
+			return "", err
+		}
+
+		return text, nil
+	default:
+		return string(jazon), nil
+	}
+}
+
+// MarshalAsArray renders a [JSONMapSlice] whose keys are array indices as a JSON array, e.g. {"1":"b","0":"a"}
+// as ["a","b"]. This is useful with tools storing arrays as objects keyed by stringified indices.
+//
+// Keys must be the decimal indices "0" to "n-1", in any order, without leading zeros:
+// otherwise, e.g. when an index is missing or a key is not an index, an error is returned.
+// Values are rendered in the order of their index. Nested objects are rendered as objects.
+//
+// An empty object renders as an empty array, and a nil object as null.
+func (s JSONMapSlice) MarshalAsArray() ([]byte, error) {
+	if s == nil {
+		return append([]byte(nil), nullJSON...), nil
+	}
+
+	elems := make([]any, len(s))
+	seen := make([]bool, len(s))
+	for i := range s {
+		idx, err := strconv.Atoi(s[i].Key)
+		if err != nil || idx < 0 || idx >= len(s) || strconv.Itoa(idx) != s[i].Key {
+			return nil, fmt.Errorf("cannot marshal object as an array: key %q is not an index in [0:%d]: %w", s[i].Key, len(s), ErrJSON)
+		}
+		if seen[idx] {
+			return nil, fmt.Errorf("cannot marshal object as an array: duplicate index %q: %w", s[i].Key, ErrJSON)
+		}
+
+		seen[idx] = true
+		elems[idx] = s[i].Value
+	}
+
+	return writeOrderedJSON(elems, defaultEncodeOptions())
+}
@@ -0,0 +1,189 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceToMap(t *testing.T) {
+	const sd = `{"z":1,"b":{"y":[1,{"x":"a","c":null}],"a":true},"e":[],"d":{}}`
+
+	t.Run("should convert to map", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		m := data.ToMap()
+		require.Equal(t, map[string]any{
+			"z": int64(1),
+			"b": map[string]any{
+				"y": []any{int64(1), map[string]any{"x": "a", "c": nil}},
+				"a": true,
+			},
+			"e": []any{},
+			"d": map[string]any{},
+		}, m)
+
+		t.Run("should convert back from map with sorted keys", func(t *testing.T) {
+			back := FromMap(m)
+
+			jazon, err := json.Marshal(back)
+			require.NoError(t, err)
+			assert.Equal(t, `{"b":{"a":true,"y":[1,{"c":null,"x":"a"}]},"d":{},"e":[],"z":1}`, string(jazon))
+			assert.True(t, back.EqualUnordered(data))
+		})
+	})
+
+	t.Run("FromMap should be deterministic", func(t *testing.T) {
+		m := map[string]any{"c": 1, "a": 2, "b": 3, "aa": 4, "B": 5}
+
+		for i := 0; i < 10; i++ {
+			s := FromMap(m)
+			keys := make([]string, 0, len(s))
+			for _, item := range s {
+				keys = append(keys, item.Key)
+			}
+			require.Equal(t, []string{"B", "a", "aa", "b", "c"}, keys)
+		}
+	})
+
+	t.Run("should retain the last value of duplicate keys", func(t *testing.T) {
+		data := JSONMapSlice{{Key: "a", Value: 1}, {Key: "a", Value: 2}}
+		assert.Equal(t, map[string]any{"a": 2}, data.ToMap())
+	})
+
+	t.Run("should convert nil", func(t *testing.T) {
+		var data JSONMapSlice
+		assert.Nil(t, data.ToMap())
+		assert.Nil(t, FromMap(nil))
+		assert.Equal(t, map[string]any{"a": []any(nil)}, JSONMapSlice{{Key: "a", Value: []any(nil)}}.ToMap())
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: []any(nil)}}, FromMap(map[string]any{"a": []any(nil)}))
+	})
+}
+
+func TestJSONMapSliceToURLValues(t *testing.T) {
+	t.Run("should convert a flat object", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(`{"name":"a b&c","limit":10,"ratio":0.5,"active":true,"tag":"x","empty":null,"tag":"y"}`), &data))
+		data = append(data,
+			JSONMapItem{Key: "since", Value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+			JSONMapItem{Key: "typed", Value: (*time.Time)(nil)},
+			JSONMapItem{Key: "number", Value: json.Number("1e3")},
+		)
+
+		values, err := data.ToURLValues()
+		require.NoError(t, err)
+		assert.Equal(t, url.Values{
+			"name":   {"a b&c"},
+			"limit":  {"10"},
+			"ratio":  {"0.5"},
+			"active": {"true"},
+			"tag":    {"x", "y"},
+			"empty":  {""},
+			"since":  {"2024-01-02T03:04:05Z"},
+			"typed":  {""},
+			"number": {"1e3"},
+		}, values, "duplicate keys should retain the order of their values")
+
+		assert.Equal(t,
+			"active=true&empty=&limit=10&name=a+b%26c&number=1e3&ratio=0.5&since=2024-01-02T03%3A04%3A05Z&tag=x&tag=y&typed=",
+			values.Encode(),
+		)
+	})
+
+	t.Run("should convert nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		values, err := empty.ToURLValues()
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("should reject nested values", func(t *testing.T) {
+		var lazy JSONMapSlice
+		require.NoError(t, lazy.UnmarshalJSONWithOptions([]byte(`{"a":{"b":1}}`), WithLazyNested(true)))
+
+		for _, data := range []JSONMapSlice{
+			{{Key: "a", Value: 1}, {Key: "b", Value: JSONMapSlice{{Key: "c", Value: 1}}}},
+			{{Key: "a", Value: []any{1, 2}}},
+			{{Key: "a", Value: map[string]any{"b": 1}}},
+			{{Key: "a", Value: struct{ B int }{B: 1}}},
+			{{Key: "a", Value: []int{1}}},
+			lazy,
+		} {
+			values, err := data.ToURLValues()
+			require.Errorf(t, err, "expected an error for %v", data)
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Nil(t, values)
+		}
+
+		_, err := JSONMapSlice{{Key: "a", Value: func() {}}}.ToURLValues()
+		require.Error(t, err)
+	})
+}
+
+func TestJSONMapSliceMarshalAsArray(t *testing.T) {
+	t.Run("should marshal contiguous indices as an array", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Input    JSONMapSlice
+			Expected string
+		}{
+			{
+				Title:    "in order",
+				Input:    JSONMapSlice{{Key: "0", Value: "a"}, {Key: "1", Value: int64(2)}, {Key: "2", Value: JSONMapSlice{{Key: "x", Value: nil}}}},
+				Expected: `["a",2,{"x":null}]`,
+			},
+			{
+				Title:    "in any order",
+				Input:    JSONMapSlice{{Key: "2", Value: "c"}, {Key: "0", Value: "a"}, {Key: "1", Value: []any{true}}},
+				Expected: `["a",[true],"c"]`,
+			},
+			{Title: "with an empty object", Input: JSONMapSlice{}, Expected: `[]`},
+			{Title: "with a nil object", Input: nil, Expected: `null`},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				jazon, err := fixture.Input.MarshalAsArray()
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, string(jazon))
+			})
+		}
+	})
+
+	t.Run("should reject non-contiguous indices", func(t *testing.T) {
+		for _, input := range []JSONMapSlice{
+			{{Key: "0", Value: "a"}, {Key: "2", Value: "c"}},
+			{{Key: "1", Value: "b"}},
+			{{Key: "0", Value: "a"}, {Key: "0", Value: "b"}},
+		} {
+			_, err := input.MarshalAsArray()
+			require.ErrorIs(t, err, ErrJSON)
+		}
+	})
+
+	t.Run("should reject non-numeric keys", func(t *testing.T) {
+		for _, key := range []string{"a", "", "-1", "01", "+1", "1.0"} {
+			_, err := JSONMapSlice{{Key: "0", Value: "a"}, {Key: key, Value: "b"}}.MarshalAsArray()
+			require.ErrorIsf(t, err, ErrJSON, "expected key %q to be rejected", key)
+			assert.ErrorContains(t, err, "is not an index")
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// orderTag is the value of the "swag" struct tag designating a field that records the order of keys.
+const orderTag = "order"
+
+// Decode populates a struct, or any other go value, from a [JSONMapSlice].
+//
+// Fields are matched against keys following the same rules as [json.Unmarshal], including json struct tags
+// and embedded structs. The target must be a non-nil pointer.
+//
+// Since structs do not retain the order of keys, a field of type []string tagged with `swag:"order"`
+// is populated with the keys of the corresponding JSON object, in their original order.
+// This applies to nested structs as well, including structs held in slices.
+// Such fields should be tagged with `json:"-"` too, so they are not mistaken for a regular key.
+//
+// Example:
+//
+//	type Person struct {
+//		Name  string   `json:"name"`
+//		Age   int      `json:"age,omitempty"`
+//		Order []string `json:"-" swag:"order"`
+//	}
+func (s JSONMapSlice) Decode(target any) error {
+	jazon, err := s.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	if err := ReadJSON(jazon, target); err != nil {
+		return err
+	}
+
+	return recordOrder(reflect.ValueOf(target), s)
+}
+
+// recordOrder walks a decoded value alongside its source, and populates fields tagged with `swag:"order"`.
+func recordOrder(target reflect.Value, source any) error {
+	for target.Kind() == reflect.Pointer || target.Kind() == reflect.Interface {
+		if target.IsNil() {
+			return nil
+		}
+		target = target.Elem()
+	}
+
+	switch target.Kind() {
+	case reflect.Struct:
+		obj, ok := source.(JSONMapSlice)
+		if !ok || !target.CanAddr() {
+			// struct values held in maps or interfaces cannot be updated in place
+			return nil
+		}
+
+		return recordStructOrder(target, obj)
+	case reflect.Slice, reflect.Array:
+		arr, ok := source.([]any)
+		if !ok {
+			return nil
+		}
+
+		for i := 0; i < target.Len() && i < len(arr); i++ {
+			if err := recordOrder(target.Index(i), arr[i]); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		obj, ok := source.(JSONMapSlice)
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+
+		for _, item := range obj {
+			elem := target.MapIndex(reflect.ValueOf(item.Key).Convert(target.Type().Key()))
+			if !elem.IsValid() {
+				continue
+			}
+
+			if err := recordOrder(elem, item.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func recordStructOrder(target reflect.Value, obj JSONMapSlice) error {
+	typ := target.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		value := target.Field(i)
+
+		if field.Tag.Get("swag") == orderTag {
+			if field.Type != reflect.TypeOf([]string(nil)) {
+				return fmt.Errorf("field %s tagged with `swag:%q` must be a []string, but is %v: %w", field.Name, orderTag, field.Type, ErrJSON)
+			}
+			if !value.CanSet() {
+				return fmt.Errorf("field %s tagged with `swag:%q` must be exported: %w", field.Name, orderTag, ErrJSON)
+			}
+
+			keys := make([]string, len(obj))
+			for j, item := range obj {
+				keys[j] = item.Key
+			}
+			value.Set(reflect.ValueOf(keys))
+
+			continue
+		}
+
+		name, isEmbedded := jsonFieldName(field)
+		if name == "" && !isEmbedded {
+			continue
+		}
+
+		if isEmbedded {
+			// the fields of embedded structs are promoted to the enclosing object
+			if err := recordOrder(value, obj); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		child, ok := lookupField(obj, name)
+		if !ok {
+			continue
+		}
+
+		if err := recordOrder(value, child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonFieldName returns the JSON name of a struct field, following the rules of [json.Marshal].
+//
+// It returns "" for ignored fields, and reports embedded structs with no explicit name.
+func jsonFieldName(field reflect.StructField) (name string, isEmbedded bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ = strings.Cut(tag, ",")
+	if name != "" {
+		return name, false
+	}
+
+	if field.Anonymous {
+		typ := field.Type
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		if typ.Kind() == reflect.Struct {
+			return "", true
+		}
+	}
+
+	return field.Name, false
+}
+
+// lookupField finds the value of a key, preferring an exact match, but otherwise matching
+// case-insensitively like [json.Unmarshal] does.
+func lookupField(obj JSONMapSlice, name string) (any, bool) {
+	if v, ok := obj.Get(name); ok {
+		return v, true
+	}
+
+	return obj.GetFold(name)
+}
@@ -0,0 +1,133 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeAddress struct {
+	Street string   `json:"street"`
+	City   string   `json:"city,omitempty"`
+	Order  []string `json:"-" swag:"order"`
+}
+
+type decodeBase struct {
+	ID      string `json:"id"`
+	Version int    `json:"version,omitempty"`
+}
+
+type decodePerson struct {
+	decodeBase
+
+	Name      string           `json:"name"`
+	Age       int              `json:"age,omitempty"`
+	Address   decodeAddress    `json:"address"`
+	Previous  []*decodeAddress `json:"previous,omitempty"`
+	Nicknames []string         `json:"nicknames,omitempty"`
+	Ignored   string           `json:"-"`
+	Order     []string         `json:"-" swag:"order"`
+}
+
+func TestJSONMapSliceDecode(t *testing.T) {
+	const sd = `{"name":"Ann","id":"p1","address":{"city":"Paris","street":"Rue X"},"age":42,` +
+		`"previous":[{"street":"Main St","city":"Rome"},{"city":"Oslo"}],"extra":true}`
+
+	var data JSONMapSlice
+	require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+
+	t.Run("should decode into a nested struct and record the order of keys", func(t *testing.T) {
+		var person decodePerson
+		require.NoError(t, data.Decode(&person))
+
+		assert.Equal(t, decodePerson{
+			decodeBase: decodeBase{ID: "p1"},
+			Name:       "Ann",
+			Age:        42,
+			Address: decodeAddress{
+				Street: "Rue X",
+				City:   "Paris",
+				Order:  []string{"city", "street"},
+			},
+			Previous: []*decodeAddress{
+				{Street: "Main St", City: "Rome", Order: []string{"street", "city"}},
+				{City: "Oslo", Order: []string{"city"}},
+			},
+			Order: []string{"name", "id", "address", "age", "previous", "extra"},
+		}, person)
+	})
+
+	t.Run("should leave absent fields untouched", func(t *testing.T) {
+		person := decodePerson{Ignored: "kept", Nicknames: []string{"A"}}
+		require.NoError(t, JSONMapSlice{{Key: "name", Value: "Bob"}}.Decode(&person))
+
+		assert.Equal(t, "Bob", person.Name)
+		assert.Equal(t, "kept", person.Ignored)
+		assert.Equal(t, []string{"A"}, person.Nicknames)
+		assert.Equal(t, []string{"name"}, person.Order)
+		assert.Nil(t, person.Address.Order)
+	})
+
+	t.Run("should decode into other go values", func(t *testing.T) {
+		var m map[string]*decodeAddress
+		obj := JSONMapSlice{{Key: "home", Value: JSONMapSlice{{Key: "street", Value: "S"}, {Key: "city", Value: "C"}}}}
+		require.NoError(t, obj.Decode(&m))
+		require.Contains(t, m, "home")
+		assert.Equal(t, []string{"street", "city"}, m["home"].Order)
+
+		var generic map[string]any
+		require.NoError(t, data.Decode(&generic))
+		assert.Equal(t, "Ann", generic["name"])
+	})
+
+	t.Run("should match keys case-insensitively", func(t *testing.T) {
+		var person decodePerson
+		obj := JSONMapSlice{{Key: "ADDRESS", Value: JSONMapSlice{{Key: "Street", Value: "S"}}}}
+		require.NoError(t, obj.Decode(&person))
+
+		assert.Equal(t, "S", person.Address.Street)
+		assert.Equal(t, []string{"Street"}, person.Address.Order)
+	})
+
+	t.Run("with errors", func(t *testing.T) {
+		t.Run("should reject a non-pointer target", func(t *testing.T) {
+			var person decodePerson
+			require.Error(t, data.Decode(person))
+		})
+
+		t.Run("should reject mismatching types", func(t *testing.T) {
+			var person decodePerson
+			require.Error(t, JSONMapSlice{{Key: "age", Value: "old"}}.Decode(&person))
+		})
+
+		t.Run("should reject an order field which is not a []string", func(t *testing.T) {
+			var target struct {
+				Order string `json:"-" swag:"order"`
+			}
+			require.ErrorIs(t, data.Decode(&target), ErrJSON)
+		})
+
+		t.Run("should reject an unexported order field", func(t *testing.T) {
+			var target struct {
+				order []string `swag:"order"`
+			}
+			require.ErrorIs(t, data.Decode(&target), ErrJSON)
+			assert.Nil(t, target.order)
+		})
+	})
+}
@@ -0,0 +1,154 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Diff computes a JSON Patch (RFC 6902) that transforms a [JSONMapSlice] into another one.
+//
+// Applying the result with [JSONMapSlice.ApplyPatch] on the first object reconstructs the second one,
+// including the order of keys.
+//
+// The patch only uses "add", "remove" and "replace" operations:
+//
+//   - nested objects are compared recursively, key by key
+//   - arrays are compared element by element, by index
+//   - all other values are replaced when they differ
+//
+// Keys that remain in both objects but appear in a different order are removed, then added again
+// at the end of the object, since a JSON Patch can't reorder keys otherwise.
+//
+// Values in the patch are copies: mutating the patch doesn't affect the inputs.
+//
+// An error is returned if objects compared key by key have duplicate keys, since these can't be addressed
+// by a JSON pointer.
+func Diff(a, b JSONMapSlice) ([]PatchOp, error) {
+	patch := make([]PatchOp, 0)
+
+	return diffValues(patch, "", a, b)
+}
+
+func diffValues(patch []PatchOp, path string, left, right any) ([]PatchOp, error) {
+	left, _ = resolveLazy(left)
+	right, _ = resolveLazy(right)
+
+	if l, ok := left.(JSONMapSlice); ok && l != nil {
+		if r, ok := right.(JSONMapSlice); ok && r != nil {
+			return diffObjects(patch, path, l, r)
+		}
+	}
+
+	if l, ok := left.([]any); ok && l != nil {
+		if r, ok := right.([]any); ok && r != nil {
+			return diffArrays(patch, path, l, r)
+		}
+	}
+
+	if equalValues(left, right, true) {
+		return patch, nil
+	}
+
+	return append(patch, PatchOp{Op: PatchOpReplace, Path: path, Value: cloneValue(right)}), nil
+}
+
+func diffObjects(patch []PatchOp, path string, left, right JSONMapSlice) ([]PatchOp, error) {
+	leftIndex, err := uniqueKeysIndex(path, left)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = uniqueKeysIndex(path, right); err != nil {
+		return nil, err
+	}
+
+	// the keys of the right object which may stay in place are the longest prefix of common keys
+	// that appear in the same order in both objects
+	inPlace := make(map[string]bool, len(right))
+	last := -1
+	for _, item := range right {
+		i, isCommon := leftIndex[item.Key]
+		if !isCommon || i < last {
+			break
+		}
+
+		inPlace[item.Key] = true
+		last = i
+	}
+
+	for _, item := range left {
+		if !inPlace[item.Key] {
+			patch = append(patch, PatchOp{Op: PatchOpRemove, Path: path + "/" + escapePointerToken(item.Key)})
+		}
+	}
+
+	for _, item := range right {
+		childPath := path + "/" + escapePointerToken(item.Key)
+		if inPlace[item.Key] {
+			patch, err = diffValues(patch, childPath, left[leftIndex[item.Key]].Value, item.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		patch = append(patch, PatchOp{Op: PatchOpAdd, Path: childPath, Value: cloneValue(item.Value)})
+	}
+
+	return patch, nil
+}
+
+func diffArrays(patch []PatchOp, path string, left, right []any) ([]PatchOp, error) {
+	common := len(left)
+	if len(right) < common {
+		common = len(right)
+	}
+
+	var err error
+	for i := 0; i < common; i++ {
+		patch, err = diffValues(patch, path+"/"+strconv.Itoa(i), left[i], right[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// remove trailing elements from the end, so indices remain valid
+	for i := len(left) - 1; i >= common; i-- {
+		patch = append(patch, PatchOp{Op: PatchOpRemove, Path: path + "/" + strconv.Itoa(i)})
+	}
+
+	for i := common; i < len(right); i++ {
+		patch = append(patch, PatchOp{Op: PatchOpAdd, Path: path + "/" + strconv.Itoa(i), Value: cloneValue(right[i])})
+	}
+
+	return patch, nil
+}
+
+// uniqueKeysIndex maps the keys of an object to their position, and fails on duplicate keys.
+func uniqueKeysIndex(path string, s JSONMapSlice) (map[string]int, error) {
+	index := make(map[string]int, len(s))
+	for i, item := range s {
+		if _, isDuplicate := index[item.Key]; isDuplicate {
+			return nil, fmt.Errorf("cannot compute a JSON patch with duplicate key %q at %q: %w", item.Key, path, ErrJSON)
+		}
+
+		index[item.Key] = i
+	}
+
+	return index, nil
+}
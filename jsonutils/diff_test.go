@@ -0,0 +1,171 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	parse := func(t *testing.T, sd string) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		return data
+	}
+
+	marshal := func(t *testing.T, value any) string {
+		t.Helper()
+
+		jazon, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		return string(jazon)
+	}
+
+	for _, fixture := range []struct {
+		Title    string
+		Left     string
+		Right    string
+		Expected string
+	}{
+		{
+			Title:    "with identical objects",
+			Left:     `{"a":1,"b":{"c":[1,2]}}`,
+			Right:    `{"a":1,"b":{"c":[1,2]}}`,
+			Expected: `[]`,
+		},
+		{
+			Title:    "with added keys",
+			Left:     `{"a":1}`,
+			Right:    `{"a":1,"b":{"c":true},"d/e~f":2}`,
+			Expected: `[{"op":"add","path":"/b","value":{"c":true}},{"op":"add","path":"/d~1e~0f","value":2}]`,
+		},
+		{
+			Title:    "with removed keys",
+			Left:     `{"a":1,"b":2,"c":3}`,
+			Right:    `{"b":2}`,
+			Expected: `[{"op":"remove","path":"/a"},{"op":"remove","path":"/c"}]`,
+		},
+		{
+			Title:    "with modified keys",
+			Left:     `{"a":1,"b":"x","c":[1],"d":{"e":1}}`,
+			Right:    `{"a":2,"b":null,"c":{"x":1},"d":"y"}`,
+			Expected: `[{"op":"replace","path":"/a","value":2},{"op":"replace","path":"/b","value":null},{"op":"replace","path":"/c","value":{"x":1}},{"op":"replace","path":"/d","value":"y"}]`,
+		},
+		{
+			Title:    "with nested objects",
+			Left:     `{"info":{"title":"API","version":"1.0","x-old":true}}`,
+			Right:    `{"info":{"title":"API","version":"2.0","x-new":false}}`,
+			Expected: `[{"op":"remove","path":"/info/x-old"},{"op":"replace","path":"/info/version","value":"2.0"},{"op":"add","path":"/info/x-new","value":false}]`,
+		},
+		{
+			Title:    "with modified array elements",
+			Left:     `{"a":[1,{"b":1},3]}`,
+			Right:    `{"a":[1,{"b":2},4]}`,
+			Expected: `[{"op":"replace","path":"/a/1/b","value":2},{"op":"replace","path":"/a/2","value":4}]`,
+		},
+		{
+			Title:    "with shorter array",
+			Left:     `{"a":[1,2,3,4]}`,
+			Right:    `{"a":[1,5]}`,
+			Expected: `[{"op":"replace","path":"/a/1","value":5},{"op":"remove","path":"/a/3"},{"op":"remove","path":"/a/2"}]`,
+		},
+		{
+			Title:    "with longer array",
+			Left:     `{"a":[1]}`,
+			Right:    `{"a":[1,2,[3]]}`,
+			Expected: `[{"op":"add","path":"/a/1","value":2},{"op":"add","path":"/a/2","value":[3]}]`,
+		},
+		{
+			Title:    "with reordered keys",
+			Left:     `{"a":1,"b":2,"c":3}`,
+			Right:    `{"a":1,"c":3,"b":2}`,
+			Expected: `[{"op":"remove","path":"/b"},{"op":"add","path":"/b","value":2}]`,
+		},
+		{
+			Title:    "with key inserted in the middle",
+			Left:     `{"a":1,"c":3}`,
+			Right:    `{"a":1,"b":2,"c":4}`,
+			Expected: `[{"op":"remove","path":"/c"},{"op":"add","path":"/b","value":2},{"op":"add","path":"/c","value":4}]`,
+		},
+		{
+			Title:    "with empty objects",
+			Left:     `{}`,
+			Right:    `{"a":[]}`,
+			Expected: `[{"op":"add","path":"/a","value":[]}]`,
+		},
+	} {
+		t.Run(fixture.Title, func(t *testing.T) {
+			left, right := parse(t, fixture.Left), parse(t, fixture.Right)
+
+			patch, err := Diff(left, right)
+			require.NoError(t, err)
+			assert.Equal(t, fixture.Expected, marshal(t, patch))
+
+			t.Run("should reconstruct the right object with ApplyPatch", func(t *testing.T) {
+				patched, err := left.ApplyPatch(patch)
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Right, marshal(t, patched))
+				assert.True(t, patched.Equal(right))
+			})
+
+			t.Run("should not mutate the inputs", func(t *testing.T) {
+				assert.Equal(t, fixture.Left, marshal(t, left))
+				assert.Equal(t, fixture.Right, marshal(t, right))
+			})
+		})
+	}
+
+	t.Run("should diff nil objects", func(t *testing.T) {
+		patch, err := Diff(nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, patch)
+
+		right := JSONMapSlice{{Key: "a", Value: 1}}
+		patch, err = Diff(nil, right)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"op":"replace","path":"","value":{"a":1}}]`, marshal(t, patch))
+
+		var empty JSONMapSlice
+		patched, err := empty.ApplyPatch(patch)
+		require.NoError(t, err)
+		assert.Equal(t, right, patched)
+	})
+
+	t.Run("should not share values with the inputs", func(t *testing.T) {
+		right := parse(t, `{"a":{"b":1}}`)
+		patch, err := Diff(JSONMapSlice{}, right)
+		require.NoError(t, err)
+		require.Len(t, patch, 1)
+
+		patch[0].Value.(JSONMapSlice)[0].Value = "changed"
+		assert.Equal(t, `{"a":{"b":1}}`, marshal(t, right))
+	})
+
+	t.Run("should fail on duplicate keys", func(t *testing.T) {
+		_, err := Diff(parse(t, `{"a":1,"a":2}`), parse(t, `{"a":1}`))
+		require.ErrorIs(t, err, ErrJSON)
+
+		_, err = Diff(parse(t, `{"a":{"b":1}}`), parse(t, `{"a":{"b":1,"b":2}}`))
+		require.ErrorIs(t, err, ErrJSON)
+	})
+}
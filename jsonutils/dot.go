@@ -0,0 +1,121 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ToDOT renders the structure of a [JSONMapSlice] as a Graphviz DOT graph, for debugging purposes.
+//
+// Every key of an object and every index of an array is rendered as a node, linked to its parent.
+// Values are not rendered, except for the kind of nested containers: "{}" for objects and "[]" for arrays.
+//
+// The output may be rendered with the dot command of Graphviz, e.g. "dot -Tsvg".
+func (s JSONMapSlice) ToDOT() string {
+	return s.ToDOTWithDepth(0)
+}
+
+// ToDOTWithDepth renders the structure of a [JSONMapSlice] as a Graphviz DOT graph like [JSONMapSlice.ToDOT],
+// up to some depth.
+//
+// Top-level keys are at depth 1. The content of containers beyond the maximum depth is rendered as a single
+// "..." node. A depth lower than or equal to 0 disables the limit.
+func (s JSONMapSlice) ToDOTWithDepth(depth int) string {
+	g := &dotGraph{maxDepth: depth}
+	g.b.WriteString("digraph JSONMapSlice {\n\tnode [shape=box];\n")
+	root := g.node("{}")
+	g.object(root, s, 1)
+	g.b.WriteString("}\n")
+
+	return g.b.String()
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+type dotGraph struct {
+	b        strings.Builder
+	nodes    int
+	maxDepth int
+}
+
+// node declares a new node and returns its identifier.
+func (g *dotGraph) node(label string) string {
+	id := "n" + strconv.Itoa(g.nodes)
+	g.nodes++
+
+	g.b.WriteString("\t" + id + ` [label="` + dotEscaper.Replace(label) + "\"];\n")
+
+	return id
+}
+
+func (g *dotGraph) edge(from, to string) {
+	g.b.WriteString("\t" + from + " -> " + to + ";\n")
+}
+
+// truncated reports whether the content of a container at this depth is not rendered,
+// and renders a placeholder instead.
+func (g *dotGraph) truncated(parent string, depth, length int) bool {
+	if g.maxDepth <= 0 || depth <= g.maxDepth || length == 0 {
+		return false
+	}
+
+	g.edge(parent, g.node("..."))
+
+	return true
+}
+
+func (g *dotGraph) object(parent string, s JSONMapSlice, depth int) {
+	if g.truncated(parent, depth, len(s)) {
+		return
+	}
+
+	for i := range s {
+		value, _ := resolveLazy(s[i].Value)
+		id := g.node(s[i].Key + dotKind(value))
+		g.edge(parent, id)
+		g.value(id, value, depth+1)
+	}
+}
+
+func (g *dotGraph) value(parent string, value any, depth int) {
+	switch v := value.(type) {
+	case JSONMapSlice:
+		g.object(parent, v, depth)
+	case []any:
+		if g.truncated(parent, depth, len(v)) {
+			return
+		}
+
+		for i, elem := range v {
+			id := g.node("[" + strconv.Itoa(i) + "]" + dotKind(elem))
+			g.edge(parent, id)
+			g.value(id, elem, depth+1)
+		}
+	}
+}
+
+// dotKind renders the kind of containers, as a suffix to the label of a node.
+func dotKind(value any) string {
+	switch value.(type) {
+	case JSONMapSlice:
+		return " {}"
+	case []any:
+		return " []"
+	default:
+		return ""
+	}
+}
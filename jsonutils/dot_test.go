@@ -0,0 +1,65 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONMapSliceToDOT(t *testing.T) {
+	data := JSONMapSlice{
+		{Key: "info", Value: JSONMapSlice{{Key: "title", Value: "x"}}},
+		{Key: "tags", Value: []any{JSONMapSlice{{Key: "name", Value: "a"}}, "b"}},
+		{Key: `a "quoted" key`, Value: nil},
+	}
+
+	t.Run("should render the structure as a DOT graph", func(t *testing.T) {
+		assert.Equal(t, `digraph JSONMapSlice {
+	node [shape=box];
+	n0 [label="{}"];
+	n1 [label="info {}"];
+	n0 -> n1;
+	n2 [label="title"];
+	n1 -> n2;
+	n3 [label="tags []"];
+	n0 -> n3;
+	n4 [label="[0] {}"];
+	n3 -> n4;
+	n5 [label="name"];
+	n4 -> n5;
+	n6 [label="[1]"];
+	n3 -> n6;
+	n7 [label="a \"quoted\" key"];
+	n0 -> n7;
+}
+`, data.ToDOT())
+	})
+
+	t.Run("should cap the depth of the graph", func(t *testing.T) {
+		dot := data.ToDOTWithDepth(1)
+
+		assert.Contains(t, dot, `n1 [label="info {}"];`)
+		assert.Contains(t, dot, `n2 [label="..."];`)
+		assert.Contains(t, dot, `n1 -> n2;`)
+		assert.NotContains(t, dot, `label="title"`)
+		assert.NotContains(t, dot, `label="[0] {}"`)
+	})
+
+	t.Run("should render an empty object", func(t *testing.T) {
+		assert.Equal(t, "digraph JSONMapSlice {\n\tnode [shape=box];\n\tn0 [label=\"{}\"];\n}\n", JSONMapSlice{}.ToDOT())
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExpandEnv returns a copy of this [JSONMapSlice] with references to variables such as "${VAR}" in string values
+// replaced by the value of the variable, e.g. for templated specs.
+//
+// Variables are resolved with the lookup function, or with [os.LookupEnv] if it is nil.
+// References to missing variables are left as-is: use [JSONMapSlice.ExpandEnvStrict] to reject them.
+//
+// Only the "${VAR}" form is supported: "$VAR" is left as-is. String values are expanded at any depth,
+// including inside arrays, but keys and other values are untouched. The receiver is not mutated.
+func (s JSONMapSlice) ExpandEnv(lookup func(string) (string, bool)) JSONMapSlice {
+	result, _ := s.expandEnv(lookup, false)
+
+	return result
+}
+
+// ExpandEnvStrict behaves like [JSONMapSlice.ExpandEnv], but returns an error if a variable is missing.
+func (s JSONMapSlice) ExpandEnvStrict(lookup func(string) (string, bool)) (JSONMapSlice, error) {
+	return s.expandEnv(lookup, true)
+}
+
+func (s JSONMapSlice) expandEnv(lookup func(string) (string, bool), strict bool) (JSONMapSlice, error) {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	var err error
+	result := s.Walk(func(path, _ string, value any) (any, bool) {
+		value, _ = resolveLazy(value)
+
+		str, ok := value.(string)
+		if !ok || err != nil {
+			return value, true
+		}
+
+		expanded, missing := expandEnvString(str, lookup)
+		if strict && missing != "" {
+			err = fmt.Errorf("environment variable %q is not set, at %q: %w", missing, path, ErrJSON)
+		}
+
+		return expanded, true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// expandEnvString replaces "${VAR}" references in a string, and reports the first missing variable, if any.
+//
+// Unterminated or empty references are left as-is.
+func expandEnvString(str string, lookup func(string) (string, bool)) (expanded string, missing string) {
+	if !strings.Contains(str, "${") {
+		return str, ""
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(str, "${")
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(str[start+2:], '}')
+		if end < 0 {
+			break
+		}
+
+		ref := str[start : start+3+end]
+		name := ref[2 : len(ref)-1]
+		b.WriteString(str[:start])
+		str = str[start+len(ref):]
+
+		if name == "" {
+			b.WriteString(ref)
+
+			continue
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			value = ref
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(str)
+
+	return b.String(), missing
+}
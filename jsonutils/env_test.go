@@ -0,0 +1,106 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceExpandEnv(t *testing.T) {
+	vars := map[string]string{
+		"HOST":  "example.com",
+		"PORT":  "8080",
+		"EMPTY": "",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+
+		return v, ok
+	}
+
+	data := JSONMapSlice{
+		{Key: "host", Value: "${HOST}"},
+		{Key: "url", Value: "https://${HOST}:${PORT}/api"},
+		{Key: "${HOST}", Value: int64(1)},
+		{Key: "servers", Value: []any{
+			JSONMapSlice{{Key: "url", Value: "http://${HOST}"}},
+			"${PORT}",
+			true,
+		}},
+		{Key: "literal", Value: "$HOST ${} ${HOST"},
+		{Key: "empty", Value: "[${EMPTY}]"},
+	}
+
+	t.Run("should expand present variables", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "host", Value: "example.com"},
+			{Key: "url", Value: "https://example.com:8080/api"},
+			{Key: "${HOST}", Value: int64(1)},
+			{Key: "servers", Value: []any{
+				JSONMapSlice{{Key: "url", Value: "http://example.com"}},
+				"8080",
+				true,
+			}},
+			{Key: "literal", Value: "$HOST ${} ${HOST"},
+			{Key: "empty", Value: "[]"},
+		}, data.ExpandEnv(lookup))
+
+		assert.Equal(t, "${HOST}", data[0].Value, "the receiver should not be mutated")
+	})
+
+	t.Run("with missing variables", func(t *testing.T) {
+		withMissing := JSONMapSlice{
+			{Key: "a", Value: "${HOST}/${MISSING}"},
+			{Key: "b", Value: []any{"${OTHER}"}},
+		}
+
+		t.Run("should leave references to missing variables as-is", func(t *testing.T) {
+			assert.Equal(t, JSONMapSlice{
+				{Key: "a", Value: "example.com/${MISSING}"},
+				{Key: "b", Value: []any{"${OTHER}"}},
+			}, withMissing.ExpandEnv(lookup))
+		})
+
+		t.Run("should reject missing variables in strict mode", func(t *testing.T) {
+			_, err := withMissing.ExpandEnvStrict(lookup)
+			require.ErrorIs(t, err, ErrJSON)
+			assert.ErrorContains(t, err, `"MISSING"`)
+			assert.ErrorContains(t, err, `at "/a"`)
+
+			expanded, err := data.ExpandEnvStrict(lookup)
+			require.NoError(t, err)
+			assert.Equal(t, "example.com", expanded[0].Value)
+		})
+	})
+
+	t.Run("should default to environment variables", func(t *testing.T) {
+		t.Setenv("JSONUTILS_TEST_VAR", "from env")
+
+		assert.Equal(t,
+			JSONMapSlice{{Key: "a", Value: "from env"}},
+			JSONMapSlice{{Key: "a", Value: "${JSONUTILS_TEST_VAR}"}}.ExpandEnv(nil),
+		)
+	})
+
+	t.Run("should expand lazy values", func(t *testing.T) {
+		var lazy JSONMapSlice
+		require.NoError(t, lazy.UnmarshalJSONWithOptions([]byte(`{"a":{"b":"${PORT}"}}`), WithLazyNested(true)))
+
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: JSONMapSlice{{Key: "b", Value: "8080"}}}}, lazy.ExpandEnv(lookup))
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type jsonError string
+
+const (
+	// ErrJSON is an error raised by JSON utilities
+	ErrJSON jsonError = "json error"
+)
+
+func (e jsonError) Error() string {
+	return string(e)
+}
+
+// ParseError describes a failure to parse a JSON document.
+//
+// A ParseError is an [ErrJSON]. It also wraps the underlying syntax error reported by
+// the [json.Decoder], if any, or [io.ErrUnexpectedEOF] when the document is truncated.
+type ParseError struct {
+	// Offset is the number of bytes of input consumed when the error was detected,
+	// i.e. the position right after the offending token.
+	Offset int64
+
+	// Expected describes what was expected at this position, if known.
+	Expected string
+
+	// Actual describes what was found instead, if known.
+	Actual string
+
+	err error
+}
+
+func (e *ParseError) Error() string {
+	msg := "invalid JSON at offset " + strconv.FormatInt(e.Offset, 10)
+	if e.Expected != "" {
+		msg += ": expected " + e.Expected + ", but got " + e.Actual
+	}
+	if e.err != nil {
+		msg += ": " + e.err.Error()
+	}
+
+	return msg
+}
+
+func (e *ParseError) Unwrap() []error {
+	if e.err == nil {
+		return []error{ErrJSON}
+	}
+
+	return []error{ErrJSON, e.err}
+}
+
+// newParseError reports an unexpected token found in the stream read by the decoder.
+func newParseError(d *json.Decoder, expected string, t json.Token) *ParseError {
+	return &ParseError{
+		Offset:   d.InputOffset(),
+		Expected: expected,
+		Actual:   describeToken(t),
+	}
+}
+
+// asParseError converts the syntax errors reported by the decoder into a [ParseError].
+//
+// Other errors, e.g. from the underlying reader, are returned unchanged.
+func asParseError(d *json.Decoder, err error) error {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &ParseError{Offset: syntaxErr.Offset, err: err}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &ParseError{Offset: d.InputOffset(), err: err}
+	default:
+		return err
+	}
+}
+
+func describeToken(t json.Token) string {
+	switch v := t.(type) {
+	case json.Delim:
+		return "'" + v.String() + "'"
+	case string:
+		return "string " + strconv.Quote(v)
+	case json.Number:
+		return "number " + v.String()
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
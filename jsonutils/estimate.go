@@ -0,0 +1,65 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import "encoding/json"
+
+// size estimates (in bytes) for JSON values without a known length
+const (
+	estimatedScalarSize = 8
+	estimatedOpaqueSize = 32
+)
+
+// estimatedSize is a lightweight estimate of the size of a [JSONMapSlice] rendered as compact JSON.
+//
+// It is used to pre-allocate marshaling buffers.
+func (s JSONMapSlice) estimatedSize() int {
+	const overhead = len(`"":,`)
+
+	n := len("{}")
+	for _, item := range s {
+		n += len(item.Key) + overhead + estimatedValueSize(item.Value)
+	}
+
+	return n
+}
+
+func estimatedValueSize(value any) int {
+	switch v := value.(type) {
+	case nil:
+		return len("null")
+	case JSONMapSlice:
+		return v.estimatedSize()
+	case []any:
+		n := len("[]")
+		for _, elem := range v {
+			n += estimatedValueSize(elem) + len(",")
+		}
+
+		return n
+	case string:
+		return len(v) + len(`""`)
+	case json.Number:
+		return len(v)
+	case json.RawMessage:
+		return len(v)
+	case *LazyValue:
+		return len(v.raw)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return estimatedScalarSize
+	default:
+		return estimatedOpaqueSize
+	}
+}
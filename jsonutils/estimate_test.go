@@ -0,0 +1,44 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimatedSize(t *testing.T) {
+	for _, fixture := range []struct {
+		Title string
+		Data  JSONMapSlice
+	}{
+		{Title: "with empty object", Data: JSONMapSlice{}},
+		{Title: "with large object", Data: makeLargeMapSlice(100)},
+		{Title: "with spec-like object", Data: makeSpecLikeMapSlice(100)},
+		{Title: "with opaque values", Data: JSONMapSlice{{Key: "a", Value: map[string]any{"b": "a longer string value"}}, {Key: "d", Value: json.Number("12")}}},
+	} {
+		t.Run(fixture.Title, func(t *testing.T) {
+			jazon, err := fixture.Data.MarshalJSON()
+			require.NoError(t, err)
+
+			estimate := fixture.Data.estimatedSize()
+			assert.GreaterOrEqual(t, estimate, len(jazon)/2)
+			assert.LessOrEqual(t, estimate, 2*len(jazon))
+		})
+	}
+}
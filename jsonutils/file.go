@@ -0,0 +1,60 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ReadFile reads a local JSON or YAML file into a [JSONMapSlice], preserving the order of keys.
+//
+// The format is determined by the extension of the file, regardless of its case:
+//
+//   - ".json" files are unmarshaled like with [JSONMapSlice.UnmarshalJSON]
+//   - ".yaml" and ".yml" files are unmarshaled like with [JSONMapSlice.UnmarshalYAML]
+//
+// Other extensions are not supported. Like with [JSONMapSlice.UnmarshalJSON], an empty file or a null document
+// yields a nil [JSONMapSlice]. This is otherwise intended for documents which are objects, such as specs.
+//
+// Errors mention the path of the file.
+func ReadFile(path string) (JSONMapSlice, error) {
+	var unmarshal func([]byte, *JSONMapSlice) error
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		unmarshal = func(data []byte, s *JSONMapSlice) error { return s.UnmarshalJSON(data) }
+	case ".yaml", ".yml":
+		unmarshal = func(data []byte, s *JSONMapSlice) error { return yaml.Unmarshal(data, s) }
+	default:
+		return nil, fmt.Errorf("cannot read file %q: unsupported extension %q, expecting .json, .yaml or .yml: %w", path, ext, ErrJSON)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file %q: %w", path, err)
+	}
+
+	var s JSONMapSlice
+	if err := unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("cannot parse file %q: %w", path, err)
+	}
+
+	return s, nil
+}
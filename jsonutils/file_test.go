@@ -0,0 +1,88 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(t *testing.T, name, content string) string {
+		t.Helper()
+
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	expected := JSONMapSlice{
+		{Key: "swagger", Value: "2.0"},
+		{Key: "info", Value: JSONMapSlice{{Key: "version", Value: "1.0"}, {Key: "title", Value: "test"}}},
+		{Key: "tags", Value: []any{"b", "a"}},
+		{Key: "port", Value: int64(8080)},
+	}
+
+	t.Run("should read files, preserving the order of keys", func(t *testing.T) {
+		for _, path := range []string{
+			writeFile(t, "spec.json", `{"swagger":"2.0","info":{"version":"1.0","title":"test"},"tags":["b","a"],"port":8080}`),
+			writeFile(t, "spec.yaml", "swagger: \"2.0\"\ninfo:\n  version: \"1.0\"\n  title: test\ntags: [b, a]\nport: 8080\n"),
+			writeFile(t, "spec.YML", "swagger: \"2.0\"\ninfo: {version: \"1.0\", title: test}\ntags:\n  - b\n  - a\nport: 8080\n"),
+		} {
+			data, err := ReadFile(path)
+			require.NoErrorf(t, err, "unexpected error for %q", path)
+			assert.Equalf(t, expected, data, "unexpected result for %q", path)
+		}
+	})
+
+	t.Run("should read empty files", func(t *testing.T) {
+		for _, path := range []string{
+			writeFile(t, "empty.json", ""),
+			writeFile(t, "empty.yaml", ""),
+			writeFile(t, "null.yml", "null\n"),
+		} {
+			data, err := ReadFile(path)
+			require.NoErrorf(t, err, "unexpected error for %q", path)
+			assert.Nil(t, data)
+		}
+	})
+
+	t.Run("should fail with the path of the file", func(t *testing.T) {
+		for _, path := range []string{
+			filepath.Join(dir, "missing.json"),
+			writeFile(t, "invalid.json", `{"a":`),
+			writeFile(t, "invalid.yaml", "a: [1\n"),
+			writeFile(t, "array.yml", "- 1\n"),
+			writeFile(t, "spec.txt", `{}`),
+		} {
+			data, err := ReadFile(path)
+			require.Errorf(t, err, "expected an error for %q", path)
+			assert.Contains(t, err.Error(), path)
+			assert.Nil(t, data)
+		}
+
+		_, err := ReadFile(filepath.Join(dir, "missing.json"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		_, err = ReadFile(filepath.Join(dir, "spec.txt"))
+		require.ErrorIs(t, err, ErrJSON)
+	})
+}
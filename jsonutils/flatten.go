@@ -0,0 +1,181 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Flatten returns the leaf values of this [JSONMapSlice], indexed by their JSON Pointer, e.g. "/a/b/0".
+//
+// Nested [JSONMapSlice] objects and []any arrays are visited recursively, and only scalar values are retained.
+// Escaped tokens are used in pointers: "~1" stands for "/" and "~0" stands for "~".
+//
+// Empty objects and arrays have no leaf, so they don't appear in the result. A nil object or array is
+// retained as a nil value. When an object has duplicate keys, the last value wins.
+//
+// The receiver is not mutated.
+func (s JSONMapSlice) Flatten() map[string]any {
+	result := make(map[string]any)
+	s.flatten("", result)
+
+	return result
+}
+
+func (s JSONMapSlice) flatten(prefix string, result map[string]any) {
+	for i := range s {
+		flattenValue(prefix+"/"+escapePointerToken(s[i].Key), s[i].Value, result)
+	}
+}
+
+func flattenValue(path string, value any, result map[string]any) {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		if v == nil {
+			result[path] = nil
+
+			return
+		}
+
+		v.flatten(path, result)
+	case []any:
+		if v == nil {
+			result[path] = nil
+
+			return
+		}
+
+		for i, elem := range v {
+			flattenValue(path+"/"+strconv.Itoa(i), elem, result)
+		}
+	default:
+		result[path] = value
+	}
+}
+
+// Unflatten builds a [JSONMapSlice] from leaf values indexed by their JSON Pointer, like produced by [JSONMapSlice.Flatten].
+//
+// Since a map has no order, the keys of objects are ordered by sorting the pointers of their leaves,
+// so the original order of keys is not retained.
+//
+// A node is rebuilt as an []any array if its children are exactly indexed from 0 to n-1,
+// and as a [JSONMapSlice] otherwise. Objects with such keys, e.g. {"0": true}, are therefore rebuilt as arrays.
+//
+// An error is returned if a pointer is invalid or empty, or if a pointer refers to a value
+// inside another leaf value, e.g. with both "/a" and "/a/b".
+func Unflatten(flat map[string]any) (JSONMapSlice, error) {
+	paths := make([]string, 0, len(flat))
+	for path := range flat {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	root := &flatNode{}
+	for _, path := range paths {
+		if path == "" {
+			return nil, fmt.Errorf("cannot unflatten a value at the root: %w", ErrJSON)
+		}
+
+		tokens, err := parsePointer(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := root.insert(tokens, flat[path], path); err != nil {
+			return nil, err
+		}
+	}
+
+	return root.object(), nil
+}
+
+// flatNode is an intermediate node used to rebuild a document from its leaves.
+type flatNode struct {
+	keys     []string
+	children map[string]*flatNode
+	value    any
+	isLeaf   bool
+}
+
+func (n *flatNode) insert(tokens []string, value any, path string) error {
+	if n.isLeaf {
+		return fmt.Errorf("cannot unflatten %q: a parent holds a value: %w", path, ErrJSON)
+	}
+
+	if len(tokens) == 0 {
+		if len(n.keys) > 0 {
+			return fmt.Errorf("cannot unflatten %q: it holds nested values: %w", path, ErrJSON)
+		}
+
+		n.value = value
+		n.isLeaf = true
+
+		return nil
+	}
+
+	if n.children == nil {
+		n.children = make(map[string]*flatNode)
+	}
+
+	child, ok := n.children[tokens[0]]
+	if !ok {
+		child = &flatNode{}
+		n.children[tokens[0]] = child
+		n.keys = append(n.keys, tokens[0])
+	}
+
+	return child.insert(tokens[1:], value, path)
+}
+
+func (n *flatNode) build() any {
+	if n.isLeaf {
+		return n.value
+	}
+
+	if n.isArray() {
+		a := make([]any, len(n.keys))
+		for i := range a {
+			a[i] = n.children[strconv.Itoa(i)].build()
+		}
+
+		return a
+	}
+
+	return n.object()
+}
+
+func (n *flatNode) object() JSONMapSlice {
+	s := make(JSONMapSlice, 0, len(n.keys))
+	for _, key := range n.keys {
+		s = append(s, JSONMapItem{Key: key, Value: n.children[key].build()})
+	}
+
+	return s
+}
+
+// isArray tells if the children of a node are indexed from 0 to n-1.
+func (n *flatNode) isArray() bool {
+	for i := range n.keys {
+		if _, ok := n.children[strconv.Itoa(i)]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceFlatten(t *testing.T) {
+	const sd = `{"a":{"b":[1,{"c":"x"},[true,null]],"d/e~f":1.5},"g":"y","h":{},"i":[],` +
+		`"j":[0,1,2,3,4,5,6,7,8,9,10,11]}`
+
+	var data JSONMapSlice
+	require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+	t.Run("should flatten leaf values by JSON pointer", func(t *testing.T) {
+		flat := data.Flatten()
+
+		expected := map[string]any{
+			"/a/b/0":     int64(1),
+			"/a/b/1/c":   "x",
+			"/a/b/2/0":   true,
+			"/a/b/2/1":   nil,
+			"/a/d~1e~0f": 1.5,
+			"/g":         "y",
+		}
+		for i := 0; i < 12; i++ {
+			expected["/j/"+strconv.Itoa(i)] = int64(i)
+		}
+		assert.Equal(t, expected, flat)
+
+		for path, value := range flat {
+			resolved, err := data.AtPointer(path)
+			require.NoError(t, err)
+			assert.Equal(t, value, resolved)
+		}
+
+		t.Run("should unflatten back, with keys sorted by path", func(t *testing.T) {
+			rebuilt, err := Unflatten(flat)
+			require.NoError(t, err)
+
+			jazon, err := json.Marshal(rebuilt)
+			require.NoError(t, err)
+			assert.Equal(t, `{"a":{"b":[1,{"c":"x"},[true,null]],"d/e~f":1.5},"g":"y",`+
+				`"j":[0,1,2,3,4,5,6,7,8,9,10,11]}`, string(jazon),
+				"empty objects and arrays have no leaf and should not be rebuilt",
+			)
+
+			assert.Equal(t, flat, rebuilt.Flatten())
+		})
+	})
+
+	t.Run("should sort keys by path when unflattening", func(t *testing.T) {
+		rebuilt, err := Unflatten(map[string]any{
+			"/z":       1,
+			"/b/200":   "ok",
+			"/b/0":     "zero",
+			"/a/1":     "second",
+			"/a/0":     "first",
+			"/numbers": nil,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, JSONMapSlice{
+			{Key: "a", Value: []any{"first", "second"}},
+			{Key: "b", Value: JSONMapSlice{{Key: "0", Value: "zero"}, {Key: "200", Value: "ok"}}},
+			{Key: "numbers", Value: nil},
+			{Key: "z", Value: 1},
+		}, rebuilt)
+	})
+
+	t.Run("should flatten nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+		assert.Empty(t, empty.Flatten())
+		assert.Empty(t, JSONMapSlice{}.Flatten())
+		assert.Equal(t, map[string]any{"/a": nil, "/b": nil}, JSONMapSlice{
+			{Key: "a", Value: JSONMapSlice(nil)},
+			{Key: "b", Value: []any(nil)},
+		}.Flatten())
+
+		rebuilt, err := Unflatten(nil)
+		require.NoError(t, err)
+		assert.Equal(t, JSONMapSlice{}, rebuilt)
+	})
+
+	t.Run("should resolve lazy values", func(t *testing.T) {
+		var lazy JSONMapSlice
+		require.NoError(t, lazy.UnmarshalJSONWithOptions([]byte(sd), WithLazyNested(true)))
+		assert.Equal(t, data.Flatten(), lazy.Flatten())
+	})
+
+	t.Run("should not unflatten invalid input", func(t *testing.T) {
+		for _, flat := range []map[string]any{
+			{"": 1},
+			{"a": 1},
+			{"/a~2": 1},
+			{"/a": 1, "/a/b": 2},
+			{"/a/b": 2, "/a": 1},
+			{"/a/0": 1, "/a": nil},
+		} {
+			rebuilt, err := Unflatten(flat)
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Nil(t, rebuilt)
+		}
+	})
+}
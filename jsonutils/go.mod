@@ -1,13 +1,21 @@
 module github.com/go-openapi/swag/jsonutils
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/go-openapi/swag/internal/yamlnodes v0.0.0-00010101000000-000000000000
+	github.com/go-openapi/swag/mangling v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/kr/text v0.2.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
+replace github.com/go-openapi/swag/internal/yamlnodes => ../internal/yamlnodes
+
+replace github.com/go-openapi/swag/mangling => ../mangling
+
 go 1.20
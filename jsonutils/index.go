@@ -0,0 +1,112 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+// IndexedJSONMapSlice wraps a [JSONMapSlice] with an index of its keys, so that lookups run in constant time.
+//
+// This is useful when the same large object is queried many times. For small objects or occasional lookups,
+// the linear search of [JSONMapSlice.Get] is cheaper.
+//
+// The index is built lazily on the first lookup. [IndexedJSONMapSlice.Set] maintains it, whereas
+// [IndexedJSONMapSlice.Delete] invalidates it, so it is rebuilt on the next lookup.
+//
+// Like with [JSONMapSlice.Get], the first occurrence of a duplicate key is retrieved.
+//
+// An IndexedJSONMapSlice is not safe for concurrent use.
+type IndexedJSONMapSlice struct {
+	s         JSONMapSlice
+	positions map[string]int
+}
+
+// Index returns an [IndexedJSONMapSlice] over a copy of the top-level entries of this object.
+//
+// Later changes made to the receiver are not reflected by the index, and conversely.
+func (s JSONMapSlice) Index() *IndexedJSONMapSlice {
+	var c JSONMapSlice
+	if s != nil {
+		c = make(JSONMapSlice, len(s))
+		copy(c, s)
+	}
+
+	return &IndexedJSONMapSlice{s: c}
+}
+
+// Get returns the value associated to a key, and whether this key was found.
+func (x *IndexedJSONMapSlice) Get(key string) (any, bool) {
+	i, ok := x.index()[key]
+	if !ok {
+		return nil, false
+	}
+
+	return x.s.valueAt(i), true
+}
+
+// Has indicates if a key is present.
+func (x *IndexedJSONMapSlice) Has(key string) bool {
+	_, ok := x.index()[key]
+
+	return ok
+}
+
+// Set the value of a key, like [JSONMapSlice.Set].
+func (x *IndexedJSONMapSlice) Set(key string, value any) {
+	if i, ok := x.index()[key]; ok {
+		x.s[i].Value = value
+
+		return
+	}
+
+	x.positions[key] = len(x.s)
+	x.s = append(x.s, JSONMapItem{Key: key, Value: value})
+}
+
+// Delete removes a key, like [JSONMapSlice.Delete].
+//
+// It returns true if the key was found.
+func (x *IndexedJSONMapSlice) Delete(key string) bool {
+	if !x.s.Delete(key) {
+		return false
+	}
+
+	x.positions = nil // positions have shifted
+
+	return true
+}
+
+// Len returns the number of keys, including duplicate keys.
+func (x *IndexedJSONMapSlice) Len() int {
+	return len(x.s)
+}
+
+// JSONMapSlice returns the indexed object.
+//
+// The result shares its entries with the index: it should not be modified while the index is still in use.
+func (x *IndexedJSONMapSlice) JSONMapSlice() JSONMapSlice {
+	return x.s
+}
+
+func (x *IndexedJSONMapSlice) index() map[string]int {
+	if x.positions != nil {
+		return x.positions
+	}
+
+	x.positions = make(map[string]int, len(x.s))
+	for i := len(x.s) - 1; i >= 0; i-- {
+		// iterate backwards so the first occurrence of a duplicate key wins
+		x.positions[x.s[i].Key] = i
+	}
+
+	return x.positions
+}
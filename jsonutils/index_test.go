@@ -0,0 +1,101 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexedJSONMapSlice(t *testing.T) {
+	data := JSONMapSlice{
+		{Key: "a", Value: 1},
+		{Key: "b", Value: "x"},
+		{Key: "a", Value: 2},
+		{Key: "c", Value: nil},
+	}
+
+	t.Run("should look up keys like JSONMapSlice", func(t *testing.T) {
+		idx := data.Index()
+
+		for _, key := range []string{"a", "b", "c", "z"} {
+			expected, expectedOK := data.Get(key)
+			v, ok := idx.Get(key)
+			assert.Equalf(t, expectedOK, ok, "unexpected presence for %q", key)
+			assert.Equalf(t, expected, v, "expected the first occurrence for %q", key)
+			assert.Equal(t, data.Has(key), idx.Has(key))
+		}
+		assert.Equal(t, 4, idx.Len())
+	})
+
+	t.Run("should maintain the index with Set", func(t *testing.T) {
+		idx := data.Index()
+		idx.Set("b", "y")
+		idx.Set("d", true)
+
+		v, ok := idx.Get("d")
+		require.True(t, ok)
+		assert.Equal(t, true, v)
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "a", Value: 1},
+			{Key: "b", Value: "y"},
+			{Key: "a", Value: 2},
+			{Key: "c", Value: nil},
+			{Key: "d", Value: true},
+		}, idx.JSONMapSlice())
+
+		t.Run("should not alter the original object", func(t *testing.T) {
+			assert.Equal(t, "x", data[1].Value)
+			assert.Len(t, data, 4)
+		})
+	})
+
+	t.Run("should invalidate the index with Delete", func(t *testing.T) {
+		idx := data.Index()
+		require.True(t, idx.Has("c"))
+
+		require.True(t, idx.Delete("a"))
+		v, ok := idx.Get("a")
+		require.True(t, ok, "the duplicate key should now be found")
+		assert.Equal(t, 2, v)
+
+		v, ok = idx.Get("c")
+		require.True(t, ok)
+		assert.Nil(t, v)
+
+		require.True(t, idx.Delete("a"))
+		assert.False(t, idx.Has("a"))
+		assert.False(t, idx.Delete("a"))
+
+		idx.Set("a", 3)
+		assert.Equal(t, JSONMapSlice{
+			{Key: "b", Value: "x"},
+			{Key: "c", Value: nil},
+			{Key: "a", Value: 3},
+		}, idx.JSONMapSlice())
+	})
+
+	t.Run("should index nil or empty objects", func(t *testing.T) {
+		idx := JSONMapSlice(nil).Index()
+		assert.False(t, idx.Has("a"))
+		assert.Nil(t, idx.JSONMapSlice())
+
+		idx.Set("a", 1)
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: 1}}, idx.JSONMapSlice())
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-openapi/swag/mangling"
+)
+
+// keyInitialisms holds the initialisms known to the key transforms [ToCamelKeys] and [ToPascalKeys].
+//
+// The name mangler is rebuilt whenever initialisms are added or removed, so transforms
+// may run concurrently with such changes.
+var keyInitialisms struct {
+	sync.RWMutex
+	words   []string
+	mangler *mangling.NameMangler
+}
+
+// AddInitialisms declares extra initialisms for the key transforms [ToCamelKeys] and [ToPascalKeys],
+// on top of the default initialisms of the mangling package (such as "ID", "HTTP"...), e.g. "SKU".
+//
+// Words must start with a letter, otherwise they are ignored. Lower-case only words are considered capitalized.
+//
+// AddInitialisms is safe for concurrent use.
+func AddInitialisms(words ...string) {
+	keyInitialisms.Lock()
+	defer keyInitialisms.Unlock()
+
+	current := knownInitialisms()
+	for _, word := range words {
+		word = normalizeInitialism(word)
+		if first, _ := utf8.DecodeRuneInString(word); !unicode.IsLetter(first) || containsString(current, word) {
+			continue
+		}
+		current = append(current, word)
+	}
+
+	setInitialisms(current)
+}
+
+// RemoveInitialisms removes some initialisms from those known to the key transforms [ToCamelKeys] and [ToPascalKeys].
+// This applies to default initialisms as well. Words which are not known initialisms are ignored.
+//
+// RemoveInitialisms is safe for concurrent use.
+func RemoveInitialisms(words ...string) {
+	keyInitialisms.Lock()
+	defer keyInitialisms.Unlock()
+
+	removed := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		removed[normalizeInitialism(word)] = struct{}{}
+	}
+
+	current := knownInitialisms()
+	kept := make([]string, 0, len(current))
+	for _, word := range current {
+		if _, found := removed[word]; !found {
+			kept = append(kept, word)
+		}
+	}
+
+	setInitialisms(kept)
+}
+
+// keyMangler returns the current name mangler. A mangler is never mutated once built, so it may be used
+// without holding the lock.
+func keyMangler() *mangling.NameMangler {
+	keyInitialisms.RLock()
+	m := keyInitialisms.mangler
+	keyInitialisms.RUnlock()
+	if m != nil {
+		return m
+	}
+
+	keyInitialisms.Lock()
+	defer keyInitialisms.Unlock()
+	if keyInitialisms.mangler == nil {
+		setInitialisms(knownInitialisms())
+	}
+
+	return keyInitialisms.mangler
+}
+
+// knownInitialisms must be called with the lock held.
+func knownInitialisms() []string {
+	if keyInitialisms.words == nil {
+		return mangling.DefaultInitialisms()
+	}
+
+	return keyInitialisms.words
+}
+
+// setInitialisms must be called with the lock held.
+func setInitialisms(words []string) {
+	m := mangling.NewNameMangler(mangling.WithInitialisms(words...))
+	keyInitialisms.words = words
+	keyInitialisms.mangler = &m
+}
+
+// normalizeInitialism applies the same rules as the mangling package: words are trimmed,
+// and lower-case only words are capitalized.
+func normalizeInitialism(word string) string {
+	word = strings.TrimSpace(word)
+	if word == strings.ToLower(word) {
+		return strings.ToUpper(word)
+	}
+
+	return word
+}
+
+func containsString(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitialisms(t *testing.T) {
+	t.Cleanup(resetInitialisms)
+
+	data := JSONMapSlice{
+		{Key: "product_sku", Value: "a"},
+		{Key: "user_id", Value: "b"},
+	}
+
+	t.Run("should transform keys with default initialisms", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "ProductSku", Value: "a"},
+			{Key: "UserID", Value: "b"},
+		}, data.TransformKeys(ToPascalKeys))
+	})
+
+	t.Run("should transform keys with a custom initialism", func(t *testing.T) {
+		AddInitialisms("sku", " SKU ", "")
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "ProductSKU", Value: "a"},
+			{Key: "UserID", Value: "b"},
+		}, data.TransformKeys(ToPascalKeys))
+		assert.Equal(t, "productSKU", ToCamelKeys("product_sku"))
+		assert.Equal(t, "skuList", ToCamelKeys("sku_list"))
+
+		t.Run("should ignore words which don't start with a letter", func(t *testing.T) {
+			AddInitialisms("2FA", "_ID", "-")
+
+			keyInitialisms.RLock()
+			defer keyInitialisms.RUnlock()
+			assert.Contains(t, keyInitialisms.words, "SKU")
+			for _, word := range []string{"2FA", "_ID", "-", ""} {
+				assert.NotContains(t, keyInitialisms.words, word)
+			}
+		})
+	})
+
+	t.Run("should transform keys without a removed initialism", func(t *testing.T) {
+		RemoveInitialisms("id", "SKU", "NotAnInitialism")
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "ProductSku", Value: "a"},
+			{Key: "UserId", Value: "b"},
+		}, data.TransformKeys(ToPascalKeys))
+		assert.Equal(t, "HTTPURL", ToPascalKeys("http_url"))
+	})
+
+	t.Run("should update initialisms concurrently with transforms", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				AddInitialisms("UUID", "ID")
+			}()
+			go func() {
+				defer wg.Done()
+				_ = JSONMapSlice{{Key: "user_uuid", Value: "c"}}.TransformKeys(ToCamelKeys)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, "UserID", ToPascalKeys("user_id"))
+	})
+}
+
+func resetInitialisms() {
+	keyInitialisms.Lock()
+	defer keyInitialisms.Unlock()
+
+	keyInitialisms.words = nil
+	keyInitialisms.mangler = nil
+}
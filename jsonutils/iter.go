@@ -0,0 +1,41 @@
+//go:build go1.23
+
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import "iter"
+
+// All returns an iterator over the key-value pairs of a [JSONMapSlice], in order.
+func (s JSONMapSlice) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, item := range s {
+			if !yield(item.Key, item.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys of a [JSONMapSlice], in order.
+func (s JSONMapSlice) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, item := range s {
+			if !yield(item.Key) {
+				return
+			}
+		}
+	}
+}
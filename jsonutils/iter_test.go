@@ -0,0 +1,87 @@
+//go:build go1.23
+
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONMapSliceIterators(t *testing.T) {
+	data := JSONMapSlice{
+		{Key: "a", Value: 1},
+		{Key: "b", Value: "x"},
+		{Key: "c", Value: nil},
+	}
+
+	t.Run("All should iterate over key-value pairs in order", func(t *testing.T) {
+		var (
+			keys   []string
+			values []any
+		)
+		for k, v := range data.All() {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+
+		assert.Equal(t, []string{"a", "b", "c"}, keys)
+		assert.Equal(t, []any{1, "x", nil}, values)
+	})
+
+	t.Run("Keys should iterate over keys in order", func(t *testing.T) {
+		var keys []string
+		for k := range data.Keys() {
+			keys = append(keys, k)
+		}
+
+		assert.Equal(t, []string{"a", "b", "c"}, keys)
+	})
+
+	t.Run("should support early termination", func(t *testing.T) {
+		var keys []string
+		for k, v := range data.All() {
+			if v == "x" {
+				break
+			}
+			keys = append(keys, k)
+		}
+		assert.Equal(t, []string{"a"}, keys)
+
+		keys = keys[:0]
+		for k := range data.Keys() {
+			keys = append(keys, k)
+			if k == "b" {
+				break
+			}
+		}
+		assert.Equal(t, []string{"a", "b"}, keys)
+	})
+
+	t.Run("should iterate over empty slices", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		for range empty.All() {
+			t.Fatal("unexpected iteration")
+		}
+
+		empty = JSONMapSlice{}
+		for range empty.Keys() {
+			t.Fatal("unexpected iteration")
+		}
+	})
+}
@@ -16,20 +16,107 @@ package jsonutils
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/json"
+	"reflect"
 )
 
 // WriteJSON marshals a data structure as JSON.
 //
 // The difference with [json.Marshal] is that it may check among several alternatives
-// to do so.
+// to do so. This is the entry point used to render values consistently with [JSONMapSlice]:
+//
+//   - nil is rendered as null
+//   - [JSONMapSlice] values are rendered as JSON objects, preserving the order of keys
+//   - []any values are rendered as JSON arrays, with any inner [JSONMapSlice] rendered as above
+//   - strings are rendered as JSON strings, with the same escaping rules as [json.Marshal]
+//   - [json.Number] values are rendered verbatim, and an empty number is rendered as 0
+//   - [json.RawMessage] values are checked to be valid JSON, then rendered verbatim, and a nil message is rendered as null
+//   - pointers to [JSONMapSlice] or []any are rendered like the value they point to, and nil pointers as null
+//   - values implementing [OrderedMarshaler] are rendered with their MarshalOrderedJSON method
+//   - other values implementing [json.Marshaler] are rendered with their MarshalJSON method
+//   - other values implementing [encoding.TextMarshaler], e.g. [time.Time] or [net.IP], are rendered
+//     as a JSON string holding the result of their MarshalText method
+//   - nil pointers implementing one of these interfaces are rendered as null, like with [json.Marshal]
+//   - all other values, including other scalars, structs, maps and pointers, fall back to [json.Marshal],
+//     which uses reflection to render arbitrary go values. [JSONMapSlice] values nested in such
+//     values still retain the order of their keys.
+//
+// Like with [json.Marshal], the characters '<', '>' and '&' are escaped in JSON strings.
+// Except for raw messages, the result is compact, with no insignificant whitespace.
 func WriteJSON(value interface{}) ([]byte, error) {
-	if d, ok := value.(json.Marshaler); ok {
-		return d.MarshalJSON()
+	switch v := value.(type) {
+	case nil:
+		return append([]byte(nil), nullJSON...), nil
+	case JSONMapSlice, []any, *JSONMapSlice, *[]any, string, json.Number, json.RawMessage:
+		return writeOrderedJSON(v, defaultEncodeOptions())
+	case OrderedMarshaler:
+		return writeOrderedJSON(v, defaultEncodeOptions())
+	case json.Marshaler:
+		if isNilPointer(v) {
+			return append([]byte(nil), nullJSON...), nil
+		}
+
+		return v.MarshalJSON()
+	case encoding.TextMarshaler:
+		if isNilPointer(v) {
+			return append([]byte(nil), nullJSON...), nil
+		}
+
+		text, err := v.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return writeOrderedJSON(string(text), defaultEncodeOptions())
 	}
+
 	return json.Marshal(value)
 }
 
+// isNilPointer tells if a value is a nil pointer, which [json.Marshal] renders as null
+// without calling its marshaling methods.
+func isNilPointer(value any) bool {
+	v := reflect.ValueOf(value)
+
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// writeOrderedJSON renders the values known to the ordered-map writer.
+func writeOrderedJSON(value any, o encodeOptions) ([]byte, error) {
+	w := poolOfJSONBuffers.BorrowJSONBuffer(o)
+	defer poolOfJSONBuffers.RedeemJSONBuffer(w)
+
+	w.grow(estimatedValueSize(value))
+	w.appendValue(value)
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	return w.bytes(), nil
+}
+
+// Minify renders JSON bytes of any kind as compact JSON, preserving the order of keys in objects.
+//
+// Unlike [json.Compact], which strips whitespace byte-wise, the input is decoded then rendered again,
+// so the result follows the same rules as [JSONMapSlice.MarshalJSON]: strings are escaped consistently,
+// e.g. "\u00e9" is rendered as "é" and '<', '>' and '&' are escaped by default.
+//
+// Numbers are preserved verbatim. Use [WithUseNumber](false) to have them normalized too, like when
+// unmarshaling with the default options, at the risk of losing precision.
+//
+// Other options, such as limits or a [DuplicateKeyPolicy], apply when decoding or encoding as usual.
+func Minify(data []byte, opts ...Option) ([]byte, error) {
+	o := optionsWithDefaults(append([]Option{WithUseNumber(true)}, opts...))
+
+	value, err := newJSONDecoder(bytes.NewReader(data), o.decodeOptions).decodeValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return writeOrderedJSON(value, o.encodeOptions)
+}
+
 // ReadJSON unmarshals JSON data into a data structure.
 //
 // The difference with [json.Unmarshal] is that it may check among several alternatives
@@ -44,6 +131,23 @@ func ReadJSON(data []byte, value interface{}) error {
 	return json.Unmarshal(trimmedData, value)
 }
 
+// ReadJSONMapSlice unmarshals a JSON object into a new [JSONMapSlice], preserving the order of keys.
+//
+// This is a shorthand for [JSONMapSlice.UnmarshalJSONWithOptions] that returns the result
+// instead of requiring a receiver. Like [JSONMapSlice.UnmarshalJSON], an empty input or
+// a JSON null yields a nil [JSONMapSlice].
+//
+// It is the counterpart of [WriteJSON] for ordered objects. The name [ReadJSON] is retained
+// for the generic version which unmarshals into an arbitrary target.
+func ReadJSONMapSlice(data []byte, opts ...Option) (JSONMapSlice, error) {
+	var s JSONMapSlice
+	if err := s.UnmarshalJSONWithOptions(data, opts...); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
 // FromDynamicJSON turns a go value into a properly JSON typed structure.
 //
 // "Dynamic JSON" refers to what you get when unmarshaling JSON into an untyped interface{},
@@ -15,8 +15,12 @@
 package jsonutils
 
 import (
+	"bytes"
 	"encoding/json"
+	"net"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -179,3 +183,276 @@ func TestReadWriteJSON(t *testing.T) {
 		})
 	})
 }
+
+func TestWriteJSON(t *testing.T) {
+	t.Run("should render each category of value", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Value    any
+			Expected string
+		}{
+			{Title: "with nil", Value: nil, Expected: `null`},
+			{Title: "with string", Value: "a <b> & \"c\"\n", Expected: `"a \u003cb\u003e \u0026 \"c\"\n"`},
+			{Title: "with integer", Value: int64(-42), Expected: `-42`},
+			{Title: "with float", Value: 10.35, Expected: `10.35`},
+			{Title: "with boolean", Value: true, Expected: `true`},
+			{Title: "with number", Value: json.Number("12345678901234567890.5"), Expected: `12345678901234567890.5`},
+			{Title: "with empty number", Value: json.Number(""), Expected: `0`},
+			{Title: "with nil ordered map", Value: JSONMapSlice(nil), Expected: `null`},
+			{Title: "with empty ordered map", Value: JSONMapSlice{}, Expected: `{}`},
+			{Title: "with ordered map", Value: JSONMapSlice{{Key: "b", Value: 1}, {Key: "a", Value: "x"}}, Expected: `{"b":1,"a":"x"}`},
+			{
+				Title: "with nested ordered maps",
+				Value: JSONMapSlice{
+					{Key: "z", Value: JSONMapSlice{{Key: "y", Value: []any{JSONMapSlice{{Key: "x", Value: nil}, {Key: "w", Value: false}}}}}},
+					{Key: "a", Value: map[string]any{"d": 1, "c": 2}},
+				},
+				Expected: `{"z":{"y":[{"x":null,"w":false}]},"a":{"c":2,"d":1}}`,
+			},
+			{Title: "with nil array", Value: []any(nil), Expected: `null`},
+			{Title: "with empty array", Value: []any{}, Expected: `[]`},
+			{Title: "with array", Value: []any{1, "x", nil, []any{true}, JSONMapSlice{{Key: "b", Value: 1}, {Key: "a", Value: 2}}}, Expected: `[1,"x",null,[true],{"b":1,"a":2}]`},
+			{Title: "with struct", Value: SharedCounters{Counter1: 1}, Expected: `{"counter1":1}`},
+			{Title: "with pointer to struct", Value: &SharedCounters{Counter2: 2}, Expected: `{"counter2:":2}`},
+			{Title: "with nil pointer to struct", Value: (*SharedCounters)(nil), Expected: `null`},
+			{Title: "with map", Value: map[string]any{"b": 1, "a": []int{1, 2}}, Expected: `{"a":[1,2],"b":1}`},
+			{Title: "with nil map", Value: map[string]any(nil), Expected: `null`},
+			{
+				Title:    "with map of ordered maps",
+				Value:    map[string]any{"x": JSONMapSlice{{Key: "z", Value: 1}, {Key: "y", Value: 2}}},
+				Expected: `{"x":{"z":1,"y":2}}`,
+			},
+			{
+				Title: "with struct of ordered maps",
+				Value: struct {
+					Name  string       `json:"name"`
+					Inner JSONMapSlice `json:"inner"`
+					Ptr   *JSONMapSlice
+				}{Name: "n", Inner: JSONMapSlice{{Key: "z", Value: 1}, {Key: "y", Value: 2}}},
+				Expected: `{"name":"n","inner":{"z":1,"y":2},"Ptr":null}`,
+			},
+			{Title: "with pointer to ordered map", Value: &JSONMapSlice{{Key: "z", Value: 1}, {Key: "y", Value: 2}}, Expected: `{"z":1,"y":2}`},
+			{Title: "with nil pointer to ordered map", Value: (*JSONMapSlice)(nil), Expected: `null`},
+			{Title: "with pointer to array", Value: &[]any{JSONMapSlice{{Key: "z", Value: 1}, {Key: "y", Value: 2}}}, Expected: `[{"z":1,"y":2}]`},
+			{Title: "with nil pointer to array", Value: (*[]any)(nil), Expected: `null`},
+			{Title: "with raw object", Value: json.RawMessage(`{"b": [1, 2], "a": {}}`), Expected: `{"b": [1, 2], "a": {}}`},
+			{Title: "with raw array", Value: json.RawMessage(`[ {"x":"<y>"} ]`), Expected: `[ {"x":"<y>"} ]`},
+			{Title: "with nil raw message", Value: json.RawMessage(nil), Expected: `null`},
+			{Title: "with time", Value: time.Date(2024, 2, 29, 13, 4, 5, 0, time.UTC), Expected: `"2024-02-29T13:04:05Z"`},
+			{Title: "with IP address", Value: net.IPv4(192, 168, 0, 1), Expected: `"192.168.0.1"`},
+			{Title: "with custom marshaler", Value: customJSONMarshaler{V: 3}, Expected: `{"custom":3}`},
+			{Title: "with nil custom marshaler", Value: (*customJSONMarshaler)(nil), Expected: `null`},
+			{Title: "with custom text marshaler", Value: customTextMarshaler{R: 1, G: 2}, Expected: `"\u003c1,2\u003e"`},
+			{Title: "with pointer to custom text marshaler", Value: &customTextMarshaler{R: 3}, Expected: `"\u003c3,0\u003e"`},
+			{Title: "with nil custom text marshaler", Value: (*customTextMarshaler)(nil), Expected: `null`},
+			{Title: "with pointer receiver text marshaler", Value: &pointerTextMarshaler{V: "v"}, Expected: `"text:v"`},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				jazon, err := WriteJSON(fixture.Value)
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, string(jazon))
+
+				t.Run("should be consistent with the ordered-map writer", func(t *testing.T) {
+					data := JSONMapSlice{{Key: "value", Value: fixture.Value}}
+					jazonMap, err := data.MarshalJSON()
+					require.NoError(t, err)
+					assert.Equal(t, `{"value":`+fixture.Expected+`}`, string(jazonMap))
+				})
+			})
+		}
+	})
+
+	t.Run("should not share the rendered bytes", func(t *testing.T) {
+		first, err := WriteJSON(nil)
+		require.NoError(t, err)
+		first[0] = 'x'
+
+		second, err := WriteJSON(nil)
+		require.NoError(t, err)
+		assert.Equal(t, `null`, string(second))
+	})
+
+	t.Run("should fail on unsupported values", func(t *testing.T) {
+		_, err := WriteJSON(func() {})
+		require.Error(t, err)
+
+		_, err = WriteJSON([]any{1, make(chan int)})
+		require.Error(t, err)
+
+		_, err = WriteJSON(JSONMapSlice{{Key: "a", Value: func() {}}})
+		require.Error(t, err)
+
+		_, err = WriteJSON(json.RawMessage(`{"a":`))
+		require.ErrorIs(t, err, ErrJSON)
+
+		_, err = WriteJSON(customTextMarshaler{R: -1})
+		require.ErrorIs(t, err, errTestWriter)
+	})
+
+	t.Run("should match json.Marshal with marshalers", func(t *testing.T) {
+		for _, value := range []any{
+			time.Date(2024, 2, 29, 13, 4, 5, 123, time.FixedZone("X", 3600)),
+			customTextMarshaler{R: 1, G: 2},
+			&pointerTextMarshaler{V: "<v>"},
+			[]pointerTextMarshaler{{V: "a"}, {V: "b"}},
+			map[string]customTextMarshaler{"x": {R: 1}},
+		} {
+			expected, err := json.Marshal(value)
+			require.NoError(t, err)
+
+			jazon, err := WriteJSON(value)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(jazon))
+
+			data := JSONMapSlice{{Key: "a", Value: value}}
+			var buf bytes.Buffer
+			require.NoError(t, data.EncodeTo(&buf))
+			assert.Equal(t, `{"a":`+string(expected)+`}`, buf.String())
+		}
+	})
+}
+
+// customJSONMarshaler renders itself with a custom MarshalJSON method.
+type customJSONMarshaler struct {
+	V int
+}
+
+func (c customJSONMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"custom":` + strconv.Itoa(c.V) + `}`), nil
+}
+
+// customTextMarshaler renders itself as text, with a value receiver.
+type customTextMarshaler struct {
+	R, G int
+}
+
+func (c customTextMarshaler) MarshalText() ([]byte, error) {
+	if c.R < 0 {
+		return nil, errTestWriter
+	}
+
+	return []byte("<" + strconv.Itoa(c.R) + "," + strconv.Itoa(c.G) + ">"), nil
+}
+
+// pointerTextMarshaler renders itself as text, with a pointer receiver.
+type pointerTextMarshaler struct {
+	V string
+}
+
+func (p *pointerTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte("text:" + p.V), nil
+}
+
+func TestReadJSONMapSlice(t *testing.T) {
+	t.Run("should match the result of UnmarshalJSON", func(t *testing.T) {
+		for _, sd := range []string{
+			`{"b":1,"a":{"d":[1,{"f":null,"e":"x"}],"c":true}}`,
+			`{}`,
+			`null`,
+			``,
+		} {
+			var expected JSONMapSlice
+			require.NoError(t, expected.UnmarshalJSON([]byte(sd)))
+
+			data, err := ReadJSONMapSlice([]byte(sd))
+			require.NoError(t, err)
+			assert.Equal(t, expected, data)
+		}
+	})
+
+	t.Run("should read with options", func(t *testing.T) {
+		data, err := ReadJSONMapSlice([]byte(`{"a":1.0}{}`), WithUseNumber(true), WithAllowTrailingContent(true))
+		require.NoError(t, err)
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: json.Number("1.0")}}, data)
+	})
+
+	t.Run("should fail on invalid input", func(t *testing.T) {
+		data, err := ReadJSONMapSlice([]byte(`[1,2]`))
+		require.Error(t, err)
+		assert.Nil(t, data)
+
+		data, err = ReadJSONMapSlice([]byte(`{"a":`))
+		require.Error(t, err)
+		assert.Nil(t, data)
+	})
+}
+
+func TestMinify(t *testing.T) {
+	const pretty = `{
+  "b": 1,
+  "a": {
+    "d": [ 1.50, { "f": null, "e": "xé<y>" } ],
+    "c": true
+  },
+  "big": 12345678901234567890,
+  "empty": [ ],
+  "none": { }
+}
+`
+
+	t.Run("should minify pretty-printed JSON, preserving the order of keys", func(t *testing.T) {
+		jazon, err := Minify([]byte(pretty))
+		require.NoError(t, err)
+		assert.Equal(t,
+			`{"b":1,"a":{"d":[1.50,{"f":null,"e":"xé\u003cy\u003e"}],"c":true},"big":12345678901234567890,"empty":[],"none":{}}`,
+			string(jazon),
+		)
+
+		t.Run("result should be consistent with MarshalJSON", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(pretty), WithUseNumber(true)))
+
+			expected, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(jazon))
+		})
+
+		t.Run("result should be equivalent to json.Compact", func(t *testing.T) {
+			var compacted bytes.Buffer
+			require.NoError(t, json.Compact(&compacted, []byte(pretty)))
+			assert.JSONEq(t, compacted.String(), string(jazon))
+		})
+	})
+
+	t.Run("should minify JSON values of any kind", func(t *testing.T) {
+		for _, tc := range []struct {
+			input    string
+			expected string
+		}{
+			{input: " [ 1, \"a\" ,\n null ] ", expected: `[1,"a",null]`},
+			{input: ` "a" `, expected: `"a"`},
+			{input: ` "\u00e9\/" `, expected: `"é/"`},
+			{input: " 1e3\n", expected: `1e3`},
+			{input: ` null `, expected: `null`},
+		} {
+			jazon, err := Minify([]byte(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(jazon))
+		}
+	})
+
+	t.Run("should minify with options", func(t *testing.T) {
+		jazon, err := Minify([]byte(pretty), WithUseNumber(false), WithEscapeHTML(false))
+		require.NoError(t, err)
+		assert.Equal(t,
+			`{"b":1,"a":{"d":[1.5,{"f":null,"e":"xé<y>"}],"c":true},"big":12345678901234567000,"empty":[],"none":{}}`,
+			string(jazon),
+		)
+
+		_, err = Minify([]byte(`{"a":1,"a":2}`), WithDuplicateKeyPolicy(DuplicateKeyError))
+		require.Error(t, err)
+	})
+
+	t.Run("should fail on invalid input", func(t *testing.T) {
+		for _, sd := range []string{
+			`{"a":`,
+			`{"a":1}}`,
+			`[1,]`,
+		} {
+			jazon, err := Minify([]byte(sd))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Nil(t, jazon)
+		}
+	})
+}
@@ -0,0 +1,258 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query evaluates a JSONPath expression against a [JSONMapSlice], and returns all matching values in document order.
+//
+// Only a small subset of JSONPath is supported. The grammar is:
+//
+//	path     = "$" { segment }
+//	segment  = "." selector | ".." selector | "[" bracket "]" | ".." "[" bracket "]"
+//	selector = name | "*"
+//	bracket  = index | "*" | quoted
+//	index    = [ "-" ] digits             (negative indices count from the end of an array)
+//	quoted   = "'" chars "'" | '"' chars '"' (a backslash escapes the next character)
+//
+// A name selects the values under this key in objects, an index selects an element in arrays,
+// and the wildcard "*" selects all values of objects and all elements of arrays.
+// The recursive descent ".." applies the selector to the current value and all its descendants.
+//
+// For example, "$.store.book[*].author" selects the author of every book, and "$..author"
+// selects all values under the key "author", at any depth.
+//
+// Query returns an empty result if nothing matches: an error is returned only on an invalid path.
+func (s JSONMapSlice) Query(path string) ([]any, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []any{s}
+	for _, segment := range segments {
+		var next []any
+		for _, node := range nodes {
+			if segment.recursive {
+				next = segment.selectDescendants(node, next)
+
+				continue
+			}
+
+			next = segment.selectChildren(node, next)
+		}
+		nodes = next
+	}
+
+	if nodes == nil {
+		return []any{}, nil
+	}
+
+	return nodes, nil
+}
+
+type jsonPathSelector uint8
+
+const (
+	jsonPathName jsonPathSelector = iota
+	jsonPathIndex
+	jsonPathWildcard
+)
+
+type jsonPathSegment struct {
+	selector  jsonPathSelector
+	name      string
+	index     int
+	recursive bool
+}
+
+// selectChildren appends the children of a node matched by this segment.
+func (g jsonPathSegment) selectChildren(node any, matches []any) []any {
+	switch v := node.(type) {
+	case JSONMapSlice:
+		for _, item := range v {
+			if g.matchesKey(item.Key) {
+				value, _ := resolveLazy(item.Value)
+				matches = append(matches, value)
+			}
+		}
+	case []any:
+		for i, elem := range v {
+			if g.matchesIndex(i, len(v)) {
+				matches = append(matches, elem)
+			}
+		}
+	}
+
+	return matches
+}
+
+// selectDescendants appends the children matched by this segment of a node and all its descendants,
+// in document order.
+func (g jsonPathSegment) selectDescendants(node any, matches []any) []any {
+	switch v := node.(type) {
+	case JSONMapSlice:
+		for _, item := range v {
+			value, _ := resolveLazy(item.Value)
+			if g.matchesKey(item.Key) {
+				matches = append(matches, value)
+			}
+			matches = g.selectDescendants(value, matches)
+		}
+	case []any:
+		for i, elem := range v {
+			if g.matchesIndex(i, len(v)) {
+				matches = append(matches, elem)
+			}
+			matches = g.selectDescendants(elem, matches)
+		}
+	}
+
+	return matches
+}
+
+func (g jsonPathSegment) matchesKey(key string) bool {
+	return g.selector == jsonPathWildcard || (g.selector == jsonPathName && key == g.name)
+}
+
+func (g jsonPathSegment) matchesIndex(i, length int) bool {
+	switch g.selector {
+	case jsonPathWildcard:
+		return true
+	case jsonPathIndex:
+		return i == g.index || i == g.index+length
+	default:
+		return false
+	}
+}
+
+// parseJSONPath splits a JSONPath expression into segments.
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with '$': %w", path, ErrJSON)
+	}
+
+	var segments []jsonPathSegment
+	for pos := 1; pos < len(path); {
+		var segment jsonPathSegment
+		var err error
+
+		switch {
+		case strings.HasPrefix(path[pos:], ".."):
+			segment.recursive = true
+			pos += len("..")
+			if pos < len(path) && path[pos] == '[' {
+				segment, pos, err = parseJSONPathBracket(path, pos, segment)
+			} else {
+				segment, pos, err = parseJSONPathName(path, pos, segment)
+			}
+		case path[pos] == '.':
+			segment, pos, err = parseJSONPathName(path, pos+1, segment)
+		case path[pos] == '[':
+			segment, pos, err = parseJSONPathBracket(path, pos, segment)
+		default:
+			err = jsonPathError(path, pos, "expected '.' or '['")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// parseJSONPathName parses a name or a wildcard in dot notation.
+func parseJSONPathName(path string, pos int, segment jsonPathSegment) (jsonPathSegment, int, error) {
+	end := pos
+	for end < len(path) && path[end] != '.' && path[end] != '[' {
+		end++
+	}
+
+	name := path[pos:end]
+	switch name {
+	case "":
+		return segment, pos, jsonPathError(path, pos, "expected a name or '*'")
+	case "*":
+		segment.selector = jsonPathWildcard
+	default:
+		segment.selector = jsonPathName
+		segment.name = name
+	}
+
+	return segment, end, nil
+}
+
+// parseJSONPathBracket parses an index, a wildcard or a quoted name in bracket notation.
+func parseJSONPathBracket(path string, pos int, segment jsonPathSegment) (jsonPathSegment, int, error) {
+	pos++ // skip '['
+	if pos >= len(path) {
+		return segment, pos, jsonPathError(path, pos, "unterminated bracket")
+	}
+
+	switch c := path[pos]; c {
+	case '\'', '"':
+		var name strings.Builder
+		end := pos + 1
+		for ; end < len(path) && path[end] != c; end++ {
+			if path[end] == '\\' {
+				end++
+				if end >= len(path) {
+					break
+				}
+			}
+			name.WriteByte(path[end])
+		}
+		if end >= len(path) {
+			return segment, pos, jsonPathError(path, pos, "unterminated quoted name")
+		}
+
+		segment.selector = jsonPathName
+		segment.name = name.String()
+		pos = end + 1
+	case '*':
+		segment.selector = jsonPathWildcard
+		pos++
+	default:
+		end := strings.IndexByte(path[pos:], ']')
+		if end < 0 {
+			return segment, pos, jsonPathError(path, pos, "unterminated bracket")
+		}
+
+		index, err := strconv.Atoi(path[pos : pos+end])
+		if err != nil {
+			return segment, pos, jsonPathError(path, pos, "expected an index, '*' or a quoted name")
+		}
+
+		segment.selector = jsonPathIndex
+		segment.index = index
+		pos += end
+	}
+
+	if pos >= len(path) || path[pos] != ']' {
+		return segment, pos, jsonPathError(path, pos, "expected ']'")
+	}
+
+	return segment, pos + 1, nil
+}
+
+func jsonPathError(path string, pos int, reason string) error {
+	return fmt.Errorf("invalid JSONPath %q at offset %d: %s: %w", path, pos, reason, ErrJSON)
+}
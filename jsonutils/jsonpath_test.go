@@ -0,0 +1,92 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceQuery(t *testing.T) {
+	const sd = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99}
+    ],
+    "bicycle": {"color": "red", "price": 19.95, "owner": {"author": "nobody"}}
+  },
+  "a.b": {"c[0]": true},
+  "author": "top"
+}`
+
+	var data JSONMapSlice
+	require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+	t.Run("should query", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Path     string
+			Expected []any
+		}{
+			{Title: "with root", Path: "$", Expected: []any{data}},
+			{Title: "with dot notation", Path: "$.store.bicycle.color", Expected: []any{"red"}},
+			{Title: "with bracket index", Path: "$.store.book[1].title", Expected: []any{"Sword of Honour"}},
+			{Title: "with negative index", Path: "$.store.book[-1].title", Expected: []any{"Moby Dick"}},
+			{Title: "with quoted names", Path: `$['a.b']["c[0]"]`, Expected: []any{true}},
+			{Title: "with wildcard over an array", Path: "$.store.book[*].author", Expected: []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville"}},
+			{Title: "with wildcard in dot notation", Path: "$.store.book.*.price", Expected: []any{8.95, 12.99, 8.99}},
+			{Title: "with wildcard over an object", Path: "$.store.bicycle.*", Expected: []any{"red", 19.95, JSONMapSlice{{Key: "author", Value: "nobody"}}}},
+			{
+				Title:    "with recursive descent at multiple depths",
+				Path:     "$..author",
+				Expected: []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "nobody", "top"},
+			},
+			{Title: "with recursive descent under a key", Path: "$.store..price", Expected: []any{8.95, 12.99, 8.99, 19.95}},
+			{Title: "with recursive descent and bracket", Path: "$..book[0].isbn", Expected: []any{}},
+			{Title: "with recursive descent and index", Path: "$..[2].isbn", Expected: []any{"0-553-21311-3"}},
+			{Title: "with no match", Path: "$.store.book[3]", Expected: []any{}},
+			{Title: "with index on an object", Path: "$.store[0]", Expected: []any{}},
+			{Title: "with name on an array", Path: "$.store.book.author", Expected: []any{}},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				matches, err := data.Query(fixture.Path)
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, matches)
+			})
+		}
+	})
+
+	t.Run("should query duplicate keys", func(t *testing.T) {
+		dup := JSONMapSlice{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "a", Value: 3}}
+
+		matches, err := dup.Query("$.a")
+		require.NoError(t, err)
+		assert.Equal(t, []any{1, 3}, matches)
+	})
+
+	t.Run("should fail on invalid path", func(t *testing.T) {
+		for _, path := range []string{
+			"", "store", "$store", "$.", "$..", "$.a.", "$[", "$[0", "$[x]", "$['a]", "$['a'", "$[*", `$["a\`,
+		} {
+			_, err := data.Query(path)
+			require.ErrorIsf(t, err, ErrJSON, "expected an error for %q", path)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// LazyValue holds a nested JSON object or array which has not been decoded yet.
+//
+// Lazy values are produced when unmarshaling with [WithLazyNested]. They are resolved transparently,
+// and replaced in place by their decoded value, by [JSONMapSlice.Get] and the other lookup methods,
+// such as [JSONMapSlice.GetMap]. When marshaled, a lazy value renders exactly like its decoded value.
+//
+// Functions which look into nested values, such as [JSONMapSlice.ToMap], [JSONMapSlice.Clone],
+// [JSONMapSlice.Walk], [JSONMapSlice.Query] or [JSONMapSlice.Equal], decode lazy values as they go,
+// without modifying the object: their result does not depend on the lookups made before.
+//
+// Direct accesses to [JSONMapItem.Value], iterations with [JSONMapSlice.All] and the values passed to
+// the functions of [JSONMapSlice.Filter] and [JSONMapSlice.MapValues] may see a *LazyValue: use [LazyValue.Value]
+// to decode it explicitly. Since lookups replace lazy values in place, they are not safe for concurrent use
+// on an object holding lazy values.
+//
+// The unmarshaling options are retained to decode the value, including [WithLazyNested], so that objects
+// nested deeper are decoded only when accessed in turn. Limits such as [WithMaxTotalNodes] apply
+// to every decoded value separately.
+type LazyValue struct {
+	raw  json.RawMessage
+	opts decodeOptions
+}
+
+// Raw returns the JSON of the value, as found in the original document.
+//
+// The result should not be modified.
+func (v *LazyValue) Raw() json.RawMessage {
+	return v.raw
+}
+
+// Value decodes the lazy value, which yields a [JSONMapSlice] or a []any.
+//
+// A lazy value is never modified, so every call decodes the raw JSON again.
+func (v *LazyValue) Value() (any, error) {
+	d := newJSONDecoder(bytes.NewReader(v.raw), v.opts)
+
+	return d.decodeValue()
+}
+
+// MarshalJSON renders the decoded value.
+func (v *LazyValue) MarshalJSON() ([]byte, error) {
+	value, err := v.Value()
+	if err != nil {
+		return nil, err
+	}
+
+	return WriteJSON(value)
+}
+
+// lazyValue reads the value of a key, without decoding it if it is an object or an array.
+func (d *jsonDecoder) lazyValue() any {
+	if !d.checkContext() {
+		return nil
+	}
+
+	var raw json.RawMessage
+	if err := d.decoder.Decode(&raw); err != nil {
+		d.setReadError(err)
+
+		return nil
+	}
+
+	var token json.Token
+	switch raw[0] {
+	case '{', '[':
+		delim := json.Delim(raw[0])
+		if !d.countNode(delim) || !d.enterNested(delim) {
+			return nil
+		}
+		d.leaveNested()
+
+		opts := d.decodeOptions
+		if opts.maxDepth > 0 {
+			// the nesting levels above this value are already consumed
+			opts.maxDepth -= d.depth
+		}
+
+		return &LazyValue{raw: raw, opts: opts}
+	case '"':
+		var str string
+		_ = json.Unmarshal(raw, &str) // the raw string is valid JSON
+		token = str
+	case 't':
+		token = true
+	case 'f':
+		token = false
+	case 'n':
+		token = nil
+	default:
+		token = json.Number(raw)
+	}
+
+	d.currentToken = token
+	var mi JSONMapItem
+
+	return mi.asInterface(d)
+}
+
+// resolveLazy decodes a lazy value, if this is one, without modifying the object holding it.
+//
+// It reports false if the lazy value cannot be decoded, and returns the value unchanged.
+func resolveLazy(value any) (any, bool) {
+	lazy, ok := value.(*LazyValue)
+	if !ok {
+		return value, true
+	}
+
+	resolved, err := lazy.Value()
+	if err != nil {
+		return value, false
+	}
+
+	return resolved, true
+}
+
+// valueAt returns the value at some position, resolving it in place if it is a lazy value.
+//
+// Other values are never written, so that concurrent reads of an object without lazy values are safe.
+func (s JSONMapSlice) valueAt(i int) any {
+	if _, isLazy := s[i].Value.(*LazyValue); !isLazy {
+		return s[i].Value
+	}
+
+	value, ok := resolveLazy(s[i].Value)
+	if ok {
+		s[i].Value = value
+	}
+
+	return value
+}
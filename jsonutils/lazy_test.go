@@ -0,0 +1,254 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestLazyNested(t *testing.T) {
+	const sd = `{"openapi":"3.1.0","info":{"title":"x","version":1},"paths":{"/a":{"get":{"responses":[200,404]}}},` +
+		`"tags":[{"name":"t"}],"count":12,"ratio":0.5,"ok":true,"none":null}`
+
+	parseLazy := func(t *testing.T, opts ...Option) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSONWithOptions([]byte(sd), append(opts, WithLazyNested(true))...))
+
+		return data
+	}
+
+	t.Run("should defer nested values and decode scalars", func(t *testing.T) {
+		data := parseLazy(t)
+
+		require.Len(t, data, 8)
+		assert.Equal(t, "3.1.0", data[0].Value)
+		assert.IsType(t, &LazyValue{}, data[1].Value)
+		assert.IsType(t, &LazyValue{}, data[2].Value)
+		assert.IsType(t, &LazyValue{}, data[3].Value)
+		assert.Equal(t, int64(12), data[4].Value)
+		assert.Equal(t, 0.5, data[5].Value)
+		assert.Equal(t, true, data[6].Value)
+		assert.Nil(t, data[7].Value)
+
+		lazy, ok := data[1].Value.(*LazyValue)
+		require.True(t, ok)
+		assert.Equal(t, `{"title":"x","version":1}`, string(lazy.Raw()))
+	})
+
+	t.Run("should decode lazy values on access", func(t *testing.T) {
+		data := parseLazy(t)
+
+		info, ok := data.GetMap("info")
+		require.True(t, ok)
+		assert.Equal(t, JSONMapSlice{{Key: "title", Value: "x"}, {Key: "version", Value: int64(1)}}, info)
+		assert.Equal(t, info, data[1].Value, "the decoded value should replace the lazy value")
+
+		tags, ok := data.GetSlice("tags")
+		require.True(t, ok)
+		assert.Equal(t, []any{JSONMapSlice{{Key: "name", Value: "t"}}}, tags, "arrays should be decoded entirely")
+
+		paths, ok := data.GetMap("paths")
+		require.True(t, ok)
+		require.IsType(t, &LazyValue{}, paths[0].Value, "deeper objects should remain lazy")
+
+		responses, err := data.AtPointer("/paths/~1a/get/responses")
+		require.NoError(t, err, "pointers should resolve lazy values")
+		assert.Equal(t, []any{int64(200), int64(404)}, responses)
+
+		path, ok := paths.GetFold("/A")
+		require.True(t, ok)
+		get, ok := path.(JSONMapSlice).Get("get")
+		require.True(t, ok)
+		assert.IsType(t, JSONMapSlice{}, get)
+	})
+
+	t.Run("should match eager parsing", func(t *testing.T) {
+		var eager JSONMapSlice
+		require.NoError(t, eager.UnmarshalJSON([]byte(sd)))
+
+		data := parseLazy(t)
+		assert.True(t, eager.Equal(data))
+
+		eagerJSON, err := eager.MarshalJSON()
+		require.NoError(t, err)
+
+		lazyJSON, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, string(eagerJSON), string(lazyJSON))
+
+		indented, err := data.MarshalJSONIndent("", "  ")
+		require.NoError(t, err)
+		eagerIndented, err := eager.MarshalJSONIndent("", "  ")
+		require.NoError(t, err)
+		assert.Equal(t, string(eagerIndented), string(indented))
+
+		stdJSON, err := json.Marshal(data[2].Value)
+		require.NoError(t, err)
+		assert.Equal(t, `{"/a":{"get":{"responses":[200,404]}}}`, string(stdJSON))
+
+	})
+
+	t.Run("should decode lazy values with the original options", func(t *testing.T) {
+		data := parseLazy(t, WithUseNumber(true))
+		assert.Equal(t, json.Number("12"), data[4].Value)
+
+		info, ok := data.GetMap("info")
+		require.True(t, ok)
+		assert.Equal(t, json.Number("1"), info[1].Value)
+
+		t.Run("should apply the max depth at the position of the lazy value", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"a":{"b":{"c":1}}}`), WithLazyNested(true), WithMaxDepth(3)))
+
+			a, ok := data.GetMap("a")
+			require.True(t, ok)
+			b, ok := a.GetMap("b")
+			require.True(t, ok)
+			assert.Equal(t, JSONMapSlice{{Key: "c", Value: int64(1)}}, b)
+
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"a":{"b":{"c":[]}}}`), WithLazyNested(true), WithMaxDepth(3)))
+			a, ok = data.GetMap("a")
+			require.True(t, ok)
+			_, ok = a.GetMap("b")
+			require.False(t, ok, "a lazy value exceeding the max depth cannot be resolved")
+
+			_, err := a[0].Value.(*LazyValue).Value()
+			require.ErrorIs(t, err, ErrJSON)
+
+			err = data.UnmarshalJSONWithOptions([]byte(`{"a":{}}`), WithLazyNested(true), WithMaxDepth(1))
+			require.ErrorIs(t, err, ErrJSON)
+		})
+	})
+
+	t.Run("should accept lazy values in the other decoding functions", func(t *testing.T) {
+		data, err := UnmarshalReader(strings.NewReader(sd), WithLazyNested(true))
+		require.NoError(t, err)
+		assert.IsType(t, &LazyValue{}, data[1].Value)
+
+		root, err := Unmarshal([]byte(`[{"a":{"b":1}}]`), WithLazyNested(true))
+		require.NoError(t, err)
+		arr, ok := root.([]any)
+		require.True(t, ok)
+		require.Len(t, arr, 1)
+		assert.IsType(t, &LazyValue{}, arr[0].(JSONMapSlice)[0].Value)
+	})
+
+	t.Run("should decode lazy values in traversals without modifying the object", func(t *testing.T) {
+		var eager JSONMapSlice
+		require.NoError(t, eager.UnmarshalJSON([]byte(sd)))
+		data := parseLazy(t)
+
+		identity := func(key string) string { return key }
+		walkAll := func(_, _ string, value any) (any, bool) {
+			_, isLazy := value.(*LazyValue)
+			assert.False(t, isLazy, "the walk function should not see lazy values")
+
+			return value, true
+		}
+
+		assert.Equal(t, eager.ToMap(), data.ToMap())
+		assert.Equal(t, eager, data.Clone())
+		assert.Equal(t, eager, data.Walk(walkAll))
+		assert.Equal(t, eager.Redact(nil, nil), data.Redact(nil, nil))
+		assert.Equal(t, eager.Flatten(), data.Flatten())
+		assert.Equal(t, eager.Normalize(), data.Normalize())
+		assert.Equal(t, eager.SortKeysRecursive(), data.SortKeysRecursive())
+		assert.Equal(t, eager, data.TransformKeysRecursive(identity))
+		assert.Equal(t, eager.ToDOT(), data.ToDOT())
+		assert.Equal(t, eager, data.Merge(nil, MergeOptions{}))
+		assert.Equal(t, eager, JSONMapSlice(nil).Merge(data, MergeOptions{}))
+
+		renamed, _ := data.RenameKeys(identity)
+		assert.Equal(t, eager, renamed)
+
+		query, err := data.Query("$..responses[*]")
+		require.NoError(t, err)
+		assert.Equal(t, []any{int64(200), int64(404)}, query)
+
+		query, err = data.Query("$.info")
+		require.NoError(t, err)
+		assert.Equal(t, []any{JSONMapSlice{{Key: "title", Value: "x"}, {Key: "version", Value: int64(1)}}}, query)
+
+		assert.True(t, data.Equal(eager))
+		assert.True(t, eager.Equal(data))
+		patch, err := Diff(data, eager)
+		require.NoError(t, err)
+		assert.Empty(t, patch)
+
+		yamlEager, err := yaml.Marshal(eager)
+		require.NoError(t, err)
+		yamlLazy, err := yaml.Marshal(data)
+		require.NoError(t, err)
+		assert.Equal(t, string(yamlEager), string(yamlLazy))
+
+		assert.IsType(t, &LazyValue{}, data[1].Value, "the receiver should retain its lazy values")
+		assert.IsType(t, &LazyValue{}, data[2].Value, "the receiver should retain its lazy values")
+		assert.IsType(t, &LazyValue{}, data[3].Value, "the receiver should retain its lazy values")
+
+		t.Run("should yield the same results after lookups", func(t *testing.T) {
+			_, ok := data.Get("paths")
+			require.True(t, ok)
+			assert.IsType(t, JSONMapSlice{}, data[2].Value)
+
+			query, err := data.Query("$..responses[*]")
+			require.NoError(t, err)
+			assert.Equal(t, []any{int64(200), int64(404)}, query)
+			assert.Equal(t, eager.ToMap(), data.ToMap())
+		})
+	})
+
+	t.Run("should pass lazy values as they are to callbacks", func(t *testing.T) {
+		data := parseLazy(t)
+
+		filtered := data.Filter(func(_ string, value any) bool {
+			_, isLazy := value.(*LazyValue)
+
+			return isLazy
+		})
+		require.Len(t, filtered, 3)
+		assert.Equal(t, "info", filtered[0].Key)
+		assert.Equal(t, "paths", filtered[1].Key)
+		assert.Equal(t, "tags", filtered[2].Key)
+	})
+
+	t.Run("should report errors in lazy values", func(t *testing.T) {
+		for _, input := range []string{`{"a":{"b":}`, `{"a":[1,}`, `{"a":{"b":1}`, `{"a":`, `{"a":tru}`} {
+			var data JSONMapSlice
+			require.Errorf(t, data.UnmarshalJSONWithOptions([]byte(input), WithLazyNested(true)), "expected an error for %q", input)
+		}
+
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"a":{"b":1,"b":2}}`), WithLazyNested(true), WithDuplicateKeyPolicy(DuplicateKeyError)))
+		v, ok := data.Get("a")
+		require.True(t, ok)
+		lazy, ok := v.(*LazyValue)
+		require.True(t, ok, "a lazy value which fails to decode is returned as is")
+
+		_, err := lazy.Value()
+		require.ErrorIs(t, err, ErrJSON)
+
+		var buf bytes.Buffer
+		require.ErrorIs(t, data.EncodeTo(&buf), ErrJSON)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+// OrderedMarshaler is implemented by types which render themselves with the ordered JSON encoder,
+// e.g. when found as values of a [JSONMapSlice].
+//
+// This is an alternative to [json.Marshaler], which avoids allocating the intermediate output of MarshalJSON
+// and honors the options of the encoder, such as indentation or [WithEscapeHTML].
+// When a type implements both interfaces, [OrderedMarshaler] is preferred by [WriteJSON] and the ordered encoder.
+//
+// MarshalOrderedJSON must write exactly one JSON value to the [Writer]. Failures are reported with [Writer.SetError].
+//
+// Notice that values nested in other go values, such as the fields of a struct, are rendered by [json.Marshal],
+// which ignores this interface.
+type OrderedMarshaler interface {
+	MarshalOrderedJSON(w *Writer)
+}
+
+// Writer renders JSON values on behalf of an [OrderedMarshaler], with the options of the current encoding.
+type Writer struct {
+	jb *jsonBuffer
+}
+
+// WriteValue writes any value as JSON, like the values of a [JSONMapSlice] are rendered.
+//
+// Objects are best written as a [JSONMapSlice], which preserves the order of keys and
+// honors the indentation of the output.
+func (w *Writer) WriteValue(value any) {
+	if w.jb.err != nil {
+		return
+	}
+
+	w.jb.appendValue(value)
+}
+
+// WriteString writes a string as a quoted JSON string, escaped like [json.Marshal] does.
+func (w *Writer) WriteString(str string) {
+	if w.jb.err != nil {
+		return
+	}
+
+	w.jb.appendString(str)
+}
+
+// WriteRaw writes some pre-serialized JSON verbatim.
+//
+// The raw JSON must be a single valid JSON value: unlike [json.RawMessage] values passed to [Writer.WriteValue],
+// it is not checked. Objects and arrays are indented at the current nesting level when the output is indented.
+func (w *Writer) WriteRaw(raw []byte) {
+	if w.jb.err != nil {
+		return
+	}
+
+	w.jb.appendOpaque(raw)
+}
+
+// SetError reports a failure to render a value. Encoding stops at the first error, which is returned
+// by the marshaling function.
+//
+// Subsequent calls to the writer are ignored.
+func (w *Writer) SetError(err error) {
+	if w.jb.err == nil {
+		w.jb.err = err
+	}
+}
+
+// Err returns the first error met while writing, if any.
+func (w *Writer) Err() error {
+	return w.jb.err
+}
+
+// appendOrderedMarshaler writes a value implementing [OrderedMarshaler].
+//
+// Like with [json.Marshaler], nil pointers are rendered as null.
+func (jb *jsonBuffer) appendOrderedMarshaler(value OrderedMarshaler) {
+	if isNilPointer(value) {
+		jb.appendByteSlice(nullJSON)
+
+		return
+	}
+
+	value.MarshalOrderedJSON(&Writer{jb: jb})
+}
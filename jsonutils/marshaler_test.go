@@ -0,0 +1,128 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// money renders itself with the ordered encoder.
+type money struct {
+	cents    int64
+	currency string
+}
+
+func (m money) MarshalOrderedJSON(w *Writer) {
+	if m.currency == "" {
+		w.SetError(errors.New("missing currency"))
+
+		return
+	}
+
+	w.WriteValue(JSONMapSlice{
+		{Key: "currency", Value: m.currency},
+		{Key: "amount", Value: rawAmount(m.cents)},
+	})
+}
+
+// MarshalJSON is shadowed by MarshalOrderedJSON.
+func (m money) MarshalJSON() ([]byte, error) {
+	return []byte(`"shadowed"`), nil
+}
+
+type rawAmount int64
+
+func (a rawAmount) MarshalOrderedJSON(w *Writer) {
+	sign, cents := "", int64(a)
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+
+	w.WriteRaw([]byte(fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)))
+}
+
+type label string
+
+func (l *label) MarshalOrderedJSON(w *Writer) {
+	w.WriteString("<" + string(*l) + ">")
+}
+
+func TestOrderedMarshaler(t *testing.T) {
+	t.Run("should render custom values in a JSONMapSlice", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "price", Value: money{cents: 1250, currency: "EUR"}},
+			{Key: "prices", Value: []any{money{cents: 199, currency: "USD"}}},
+			{Key: "amounts", Value: []any{rawAmount(105), rawAmount(-105), rawAmount(-5), rawAmount(0)}},
+		}
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"price":{"currency":"EUR","amount":12.50},"prices":[{"currency":"USD","amount":1.99}],`+
+			`"amounts":[1.05,-1.05,-0.05,0.00]}`, string(jazon))
+	})
+
+	t.Run("should honor the indentation of the output", func(t *testing.T) {
+		data := JSONMapSlice{{Key: "price", Value: money{cents: 1250, currency: "EUR"}}}
+
+		jazon, err := data.MarshalJSONIndent("", "  ")
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"price\": {\n    \"currency\": \"EUR\",\n    \"amount\": 12.50\n  }\n}", string(jazon))
+	})
+
+	t.Run("should honor the escaping options", func(t *testing.T) {
+		l := label("a&b")
+		data := JSONMapSlice{{Key: "label", Value: &l}}
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"label":"\u003ca\u0026b\u003e"}`, string(jazon))
+
+		jazon, err = data.MarshalJSONWithOptions(WithEscapeHTML(false))
+		require.NoError(t, err)
+		assert.Equal(t, `{"label":"<a&b>"}`, string(jazon))
+	})
+
+	t.Run("should be preferred by WriteJSON", func(t *testing.T) {
+		jazon, err := WriteJSON(money{cents: 1250, currency: "EUR"})
+		require.NoError(t, err)
+		assert.Equal(t, `{"currency":"EUR","amount":12.50}`, string(jazon))
+	})
+
+	t.Run("should render nil pointers as null", func(t *testing.T) {
+		var l *label
+
+		jazon, err := WriteJSON(l)
+		require.NoError(t, err)
+		assert.Equal(t, `null`, string(jazon))
+
+		jazon, err = JSONMapSlice{{Key: "label", Value: l}}.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"label":null}`, string(jazon))
+	})
+
+	t.Run("should report errors", func(t *testing.T) {
+		_, err := JSONMapSlice{{Key: "price", Value: money{cents: 1250}}}.MarshalJSON()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "missing currency")
+
+		_, err = WriteJSON(money{cents: 1250})
+		require.Error(t, err)
+	})
+}
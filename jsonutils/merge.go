@@ -0,0 +1,80 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+// MergeOptions control how two [JSONMapSlice] values are merged by [JSONMapSlice.Merge].
+//
+// The zero value deep-merges nested objects and replaces arrays.
+type MergeOptions struct {
+	// ConcatArrays appends the elements of arrays found under the same key, instead of replacing them.
+	ConcatArrays bool
+
+	// OverwriteObjects replaces objects found under the same key wholesale, instead of merging them recursively.
+	OverwriteObjects bool
+}
+
+// Merge the keys of another [JSONMapSlice] into a copy of this one.
+//
+// Keys present only in the other object are appended in their original order.
+// Keys present in both objects keep their original position, and take the value from the other object,
+// possibly merged with the original value according to the [MergeOptions].
+//
+// Neither the receiver nor the other object are mutated.
+func (s JSONMapSlice) Merge(other JSONMapSlice, opts MergeOptions) JSONMapSlice {
+	if s == nil && other == nil {
+		return nil
+	}
+
+	result := s.Clone()
+	if result == nil {
+		result = make(JSONMapSlice, 0, len(other))
+	}
+
+	for _, item := range other {
+		i := result.index(item.Key)
+		if i < 0 {
+			result = append(result, JSONMapItem{Key: item.Key, Value: cloneValue(item.Value), Comment: item.Comment})
+
+			continue
+		}
+
+		result[i].Value = mergeValues(result[i].Value, item.Value, opts)
+	}
+
+	return result
+}
+
+func mergeValues(original, value any, opts MergeOptions) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		if o, ok := original.(JSONMapSlice); ok && !opts.OverwriteObjects && o != nil && v != nil {
+			return o.Merge(v, opts)
+		}
+	case []any:
+		if o, ok := original.([]any); ok && opts.ConcatArrays && o != nil && v != nil {
+			merged := make([]any, 0, len(o)+len(v))
+			merged = append(merged, o...) // original is already a clone
+			for _, elem := range v {
+				merged = append(merged, cloneValue(elem))
+			}
+
+			return merged
+		}
+	}
+
+	return cloneValue(value)
+}
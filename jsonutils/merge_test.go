@@ -0,0 +1,88 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceMerge(t *testing.T) {
+	const (
+		left  = `{"a":1,"b":{"c":"x","d":[1,2]},"e":[1,2],"f":"keep"}`
+		right = `{"g":true,"b":{"d":[3],"h":null},"a":2,"e":[3]}`
+	)
+
+	parse := func(t *testing.T, sd string) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		return data
+	}
+
+	marshal := func(t *testing.T, data JSONMapSlice) string {
+		t.Helper()
+
+		jazon, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		return string(jazon)
+	}
+
+	t.Run("should deep merge objects and replace arrays by default", func(t *testing.T) {
+		l, r := parse(t, left), parse(t, right)
+
+		merged := l.Merge(r, MergeOptions{})
+		assert.Equal(t, `{"a":2,"b":{"c":"x","d":[3],"h":null},"e":[3],"f":"keep","g":true}`, marshal(t, merged))
+
+		t.Run("should not mutate the inputs", func(t *testing.T) {
+			assert.Equal(t, left, marshal(t, l))
+			assert.Equal(t, right, marshal(t, r))
+
+			b, _ := merged.Get("b")
+			b.(JSONMapSlice)[0].Value = "changed"
+			assert.Equal(t, left, marshal(t, l))
+		})
+	})
+
+	t.Run("should concatenate arrays", func(t *testing.T) {
+		merged := parse(t, left).Merge(parse(t, right), MergeOptions{ConcatArrays: true})
+		assert.Equal(t, `{"a":2,"b":{"c":"x","d":[1,2,3],"h":null},"e":[1,2,3],"f":"keep","g":true}`, marshal(t, merged))
+	})
+
+	t.Run("should overwrite objects", func(t *testing.T) {
+		merged := parse(t, left).Merge(parse(t, right), MergeOptions{OverwriteObjects: true, ConcatArrays: true})
+		assert.Equal(t, `{"a":2,"b":{"d":[3],"h":null},"e":[1,2,3],"f":"keep","g":true}`, marshal(t, merged))
+	})
+
+	t.Run("should overwrite scalars and mismatching types", func(t *testing.T) {
+		merged := parse(t, `{"a":1,"b":{"c":1},"d":[1],"e":"x"}`).Merge(parse(t, `{"a":"x","b":[1],"d":{"c":1},"e":null}`), MergeOptions{ConcatArrays: true})
+		assert.Equal(t, `{"a":"x","b":[1],"d":{"c":1},"e":null}`, marshal(t, merged))
+	})
+
+	t.Run("should merge with nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		assert.Nil(t, empty.Merge(nil, MergeOptions{}))
+		assert.Equal(t, right, marshal(t, empty.Merge(parse(t, right), MergeOptions{})))
+		assert.Equal(t, left, marshal(t, parse(t, left).Merge(nil, MergeOptions{})))
+		assert.Equal(t, `{}`, marshal(t, JSONMapSlice{}.Merge(JSONMapSlice{}, MergeOptions{})))
+	})
+}
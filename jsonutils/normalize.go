@@ -0,0 +1,96 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import "math"
+
+// NumberForm tells how [JSONMapSlice.NormalizeTo] represents numbers.
+type NumberForm uint8
+
+const (
+	// NumberFormInteger represents integers as int64 values, and other numbers as float64 values.
+	// Integers which overflow an int64 are represented as float64 values. This is the default.
+	NumberFormInteger NumberForm = iota
+	// NumberFormFloat represents all numbers as float64 values.
+	NumberFormFloat
+)
+
+// Normalize returns a deep copy of this [JSONMapSlice] with canonical representations of numbers,
+// like [JSONMapSlice.NormalizeTo] with [NumberFormInteger].
+//
+// For instance, 1.0 as a float64 and 1 as an int64 both become int64(1), so that documents which differ only by
+// the formatting of numbers are identical after normalization, e.g. with reflect.DeepEqual.
+func (s JSONMapSlice) Normalize() JSONMapSlice {
+	return s.NormalizeTo(NumberFormInteger)
+}
+
+// NormalizeTo returns a deep copy of this [JSONMapSlice] with all numbers converted to the same representation,
+// at any depth.
+//
+// Numbers of any go type are converted, including [encoding/json.Number] values. Non-finite floats are left as-is,
+// and so are other values. The receiver is not mutated.
+func (s JSONMapSlice) NormalizeTo(form NumberForm) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	for i := range s {
+		result[i] = JSONMapItem{Key: s[i].Key, Value: normalizeValue(s[i].Value, form), Comment: s[i].Comment}
+	}
+
+	return result
+}
+
+func normalizeValue(value any, form NumberForm) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		return v.NormalizeTo(form)
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = normalizeValue(elem, form)
+		}
+
+		return result
+	default:
+		return normalizeNumber(value, form)
+	}
+}
+
+func normalizeNumber(value any, form NumberForm) any {
+	r, isNumber := asRat(value)
+	if !isNumber || r == nil {
+		return value
+	}
+
+	if form == NumberFormInteger && r.IsInt() && r.Num().IsInt64() {
+		return r.Num().Int64()
+	}
+
+	f, _ := r.Float64()
+	if math.IsInf(f, 0) {
+		// e.g. a json.Number beyond the range of float64
+		return value
+	}
+
+	return f
+}
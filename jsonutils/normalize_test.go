@@ -0,0 +1,91 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceNormalize(t *testing.T) {
+	var left, right JSONMapSlice
+	require.NoError(t, left.UnmarshalJSON([]byte(`{"a":1,"b":[2,{"c":3.5,"d":4}],"e":{"f":1e2}}`)))
+	require.NoError(t, right.UnmarshalJSON([]byte(`{"a":1.0,"b":[2.00,{"c":3.5,"d":4e0}],"e":{"f":100}}`)))
+	require.NotEqual(t, left, right)
+
+	t.Run("should make documents with mixed numbers identical", func(t *testing.T) {
+		normalized := left.Normalize()
+		assert.Equal(t, normalized, right.Normalize())
+		assert.Equal(t, JSONMapSlice{
+			{Key: "a", Value: int64(1)},
+			{Key: "b", Value: []any{int64(2), JSONMapSlice{{Key: "c", Value: 3.5}, {Key: "d", Value: int64(4)}}}},
+			{Key: "e", Value: JSONMapSlice{{Key: "f", Value: int64(100)}}},
+		}, normalized)
+
+		lj, err := left.Normalize().MarshalJSON()
+		require.NoError(t, err)
+		rj, err := right.Normalize().MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, string(lj), string(rj))
+	})
+
+	t.Run("should normalize numbers to floats", func(t *testing.T) {
+		normalized := left.NormalizeTo(NumberFormFloat)
+		assert.Equal(t, normalized, right.NormalizeTo(NumberFormFloat))
+		assert.Equal(t, JSONMapSlice{
+			{Key: "a", Value: 1.0},
+			{Key: "b", Value: []any{2.0, JSONMapSlice{{Key: "c", Value: 3.5}, {Key: "d", Value: 4.0}}}},
+			{Key: "e", Value: JSONMapSlice{{Key: "f", Value: 100.0}}},
+		}, normalized)
+	})
+
+	t.Run("should normalize numbers of any type", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "int", Value: int64(1)},
+			{Key: "uint8", Value: int64(2)},
+			{Key: "float32", Value: 0.5},
+			{Key: "number", Value: int64(3)},
+			{Key: "decimal", Value: 1.25},
+			{Key: "large", Value: 1e19},
+			{Key: "huge", Value: json.Number("1e400")},
+			{Key: "infinite", Value: math.Inf(1)},
+			{Key: "string", Value: "1"},
+			{Key: "null", Value: nil},
+		}, JSONMapSlice{
+			{Key: "int", Value: 1},
+			{Key: "uint8", Value: uint8(2)},
+			{Key: "float32", Value: float32(0.5)},
+			{Key: "number", Value: json.Number("3.0")},
+			{Key: "decimal", Value: json.Number("1.25")},
+			{Key: "large", Value: uint64(1e19)},
+			{Key: "huge", Value: json.Number("1e400")},
+			{Key: "infinite", Value: math.Inf(1)},
+			{Key: "string", Value: "1"},
+			{Key: "null", Value: nil},
+		}.Normalize())
+	})
+
+	t.Run("should not mutate the receiver", func(t *testing.T) {
+		_ = right.Normalize()
+
+		v, ok := right.Get("a")
+		require.True(t, ok)
+		assert.Equal(t, 1.0, v) //nolint:testifylint // exact value is expected
+	})
+}
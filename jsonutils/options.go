@@ -0,0 +1,305 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+type (
+	// Option configures how a [JSONMapSlice] is marshaled to or unmarshaled from JSON.
+	Option func(*options)
+
+	// DuplicateKeyPolicy tells what to do when decoding a JSON object with duplicate keys.
+	DuplicateKeyPolicy uint8
+
+	// NumberOverflowPolicy tells what to do when decoding a JSON number which overflows an int64 or a float64.
+	NumberOverflowPolicy uint8
+
+	decodeOptions struct {
+		useNumber            bool
+		duplicateKeyPolicy   DuplicateKeyPolicy
+		numberOverflowPolicy NumberOverflowPolicy
+		allowTrailing        bool
+		allowTrailingCommas  bool
+		maxDepth             int
+		maxKeys              int
+		maxTotalNodes        int
+		lazyNested           bool
+		internKeys           bool
+	}
+
+	encodeOptions struct {
+		escapeHTML        bool
+		comments          bool
+		floatTrailingZero bool
+		floatAsInteger    bool
+		omitNull          bool
+	}
+
+	options struct {
+		decodeOptions
+		encodeOptions
+	}
+)
+
+// WithUseNumber preserves numbers as [json.Number] values when unmarshaling,
+// instead of converting them to int64 or float64.
+//
+// This retains the original text of the number, so there is no loss of precision
+// for large integers or high-precision decimals.
+//
+// The default is to convert numbers.
+func WithUseNumber(enabled bool) Option {
+	return func(o *options) {
+		o.useNumber = enabled
+	}
+}
+
+const (
+	// DuplicateKeyKeep keeps all occurrences of duplicate keys, in order. This is the default.
+	DuplicateKeyKeep DuplicateKeyPolicy = iota
+	// DuplicateKeyFirst keeps only the first occurrence of a duplicate key.
+	DuplicateKeyFirst
+	// DuplicateKeyLast keeps the value of the last occurrence of a duplicate key, at the position of the first one.
+	DuplicateKeyLast
+	// DuplicateKeyError rejects JSON objects with duplicate keys.
+	DuplicateKeyError
+)
+
+// WithDuplicateKeyPolicy sets the policy applied when decoding JSON objects with duplicate keys.
+//
+// The default is [DuplicateKeyKeep].
+func WithDuplicateKeyPolicy(policy DuplicateKeyPolicy) Option {
+	return func(o *options) {
+		o.duplicateKeyPolicy = policy
+	}
+}
+
+const (
+	// NumberOverflowFloat converts integers which overflow an int64 to a float64, possibly losing precision,
+	// and rejects numbers which overflow a float64, like [json.Unmarshal] does. This is the default.
+	NumberOverflowFloat NumberOverflowPolicy = iota
+	// NumberOverflowKeep preserves numbers which overflow an int64 or a float64 as [json.Number] values.
+	// Other numbers are converted as usual.
+	NumberOverflowKeep
+	// NumberOverflowError rejects integers which overflow an int64 and numbers which overflow a float64.
+	NumberOverflowError
+)
+
+// WithNumberOverflowPolicy sets the policy applied when decoding JSON numbers which are valid,
+// but overflow an int64 (e.g. a 40-digit integer) or a float64 (e.g. 1e400).
+//
+// Rejected numbers yield a [ParseError]. The policy has no effect with [WithUseNumber],
+// which preserves all numbers as [json.Number].
+//
+// The default is [NumberOverflowFloat].
+func WithNumberOverflowPolicy(policy NumberOverflowPolicy) Option {
+	return func(o *options) {
+		o.numberOverflowPolicy = policy
+	}
+}
+
+// WithAllowTrailingContent tells whether some content is allowed after the top-level JSON object
+// when unmarshaling.
+//
+// When enabled, decoding stops after the first JSON object and any subsequent content is ignored.
+// This is useful to parse the first of several concatenated JSON documents.
+//
+// The default is to reject trailing content.
+func WithAllowTrailingContent(enabled bool) Option {
+	return func(o *options) {
+		o.allowTrailing = enabled
+	}
+}
+
+// WithAllowTrailingCommas tolerates a trailing comma before the closing '}' or ']' of JSON objects and arrays
+// when unmarshaling, e.g. `{"a":[1,2,],}`, as found in some human-edited documents.
+//
+// Only trailing commas are relaxed: other extensions of JSON5 or JSONC, such as comments, are still rejected.
+// Comments may be removed beforehand with [StripJSONComments].
+// Commas which do not follow a value, like in `[,]`, are rejected too.
+//
+// The default is to reject trailing commas, like [json.Unmarshal] does.
+func WithAllowTrailingCommas(enabled bool) Option {
+	return func(o *options) {
+		o.allowTrailingCommas = enabled
+	}
+}
+
+// DefaultMaxDepth is the default maximum nesting depth of JSON objects and arrays accepted when unmarshaling.
+const DefaultMaxDepth = 10000
+
+// WithMaxDepth sets the maximum nesting depth of JSON objects and arrays accepted when unmarshaling.
+//
+// This protects against pathological inputs such as "[[[[...", which would otherwise cause unbounded recursion.
+// Exceeding this depth returns a [ParseError]. A value lower than or equal to 0 disables the limit.
+//
+// The default is [DefaultMaxDepth]. The top-level object counts as the first level.
+//
+// Notice that the underlying [json.Decoder] enforces its own limit of 10000 levels of nesting,
+// so this setting may only be used to lower the maximum depth.
+func WithMaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
+
+// WithMaxKeys sets the maximum number of keys accepted in any single JSON object when unmarshaling.
+//
+// This protects services accepting untrusted inputs against objects with an excessive number of keys.
+// Decoding stops as soon as the limit is exceeded, and a [ParseError] is returned.
+// Duplicate keys are counted, regardless of the [DuplicateKeyPolicy].
+//
+// The default is 0, meaning that there is no limit.
+func WithMaxKeys(keys int) Option {
+	return func(o *options) {
+		o.maxKeys = keys
+	}
+}
+
+// WithMaxTotalNodes sets the maximum number of values accepted in a JSON document when unmarshaling.
+//
+// Every object, array and scalar value counts as a node, including the top-level object.
+// Decoding stops as soon as the limit is exceeded, and a [ParseError] is returned.
+//
+// The default is 0, meaning that there is no limit.
+func WithMaxTotalNodes(nodes int) Option {
+	return func(o *options) {
+		o.maxTotalNodes = nodes
+	}
+}
+
+// WithLazyNested defers the decoding of nested objects and arrays when unmarshaling.
+//
+// When enabled, the value of a key which is an object or an array is captured as a [LazyValue],
+// holding its raw JSON. It is only decoded when accessed with [JSONMapSlice.Get] or other lookup methods,
+// so that reading a few keys of a large document does not pay for decoding all of it.
+// Scalar values are decoded as usual.
+//
+// This is mostly useful for selective reads. See [LazyValue] about how lazy values behave.
+//
+// The default is to decode nested values eagerly.
+func WithLazyNested(enabled bool) Option {
+	return func(o *options) {
+		o.lazyNested = enabled
+	}
+}
+
+// WithInternKeys shares the memory of identical keys when unmarshaling, e.g. for documents with many objects
+// sharing the same keys, such as a list of records.
+//
+// Every key is still decoded as a new string, but identical keys retained in the result point to the same one,
+// so that duplicates may be garbage-collected. This reduces the memory held by large decoded documents,
+// at the cost of a lookup for every key.
+//
+// Keys are interned within a single decoding, up to [MaxInternedKeys] distinct keys.
+//
+// The default is to retain every key separately.
+func WithInternKeys(enabled bool) Option {
+	return func(o *options) {
+		o.internKeys = enabled
+	}
+}
+
+// MaxInternedKeys is the maximum number of distinct keys interned with [WithInternKeys]: other keys are retained as usual.
+//
+// This bounds the memory used by documents with many distinct keys.
+const MaxInternedKeys = 4096
+
+// WithEscapeHTML tells whether the characters '<', '>' and '&' should be escaped in JSON strings
+// when marshaling, so the output may be safely embedded in HTML.
+//
+// The default is true, like with [json.Marshal].
+func WithEscapeHTML(enabled bool) Option {
+	return func(o *options) {
+		o.escapeHTML = enabled
+	}
+}
+
+// WithComments renders the [JSONMapItem.Comment] of items as trailing line comments ("// ...")
+// when marshaling with indentation, e.g. with [JSONMapSlice.MarshalJSONIndentWithOptions].
+//
+// The output is no longer strict JSON, but is understood by JSON5 or JSONC parsers.
+// Comments are never rendered by compact marshaling, such as [JSONMapSlice.MarshalJSON].
+//
+// The default is to omit comments.
+func WithComments(enabled bool) Option {
+	return func(o *options) {
+		o.comments = enabled
+	}
+}
+
+// WithFloatTrailingZero renders float64 values that are whole numbers with a trailing ".0" when marshaling,
+// e.g. 1.0 is rendered as "1.0" instead of "1".
+//
+// This preserves the distinction between integers and floats for consumers that care about it.
+// Numbers rendered with an exponent, such as "1e+21", and values of other types are not affected.
+//
+// The default is to render whole floats like integers, as [json.Marshal] does.
+func WithFloatTrailingZero(enabled bool) Option {
+	return func(o *options) {
+		o.floatTrailingZero = enabled
+	}
+}
+
+// WithFloatAsInteger renders float64 values that are whole numbers as integers when marshaling,
+// e.g. 3.0 is rendered as "3".
+//
+// Only whole numbers within the range of integers safely represented by a float64, i.e. [-2^53+1, 2^53-1],
+// are affected. Values are never rounded: other values, such as 2^53, 1.5, 3.0000000000001, NaN or infinite values,
+// are rendered as usual.
+//
+// This takes precedence over [WithFloatTrailingZero].
+//
+// The default is to render floats like [json.Marshal] does.
+func WithFloatAsInteger(enabled bool) Option {
+	return func(o *options) {
+		o.floatAsInteger = enabled
+	}
+}
+
+// WithOmitNull skips the keys of objects with a nil value when marshaling, instead of rendering them as null.
+//
+// This applies to objects at any depth. Only untyped nil values are omitted: typed nil values, such as a nil
+// [JSONMapSlice] or a nil pointer, are still rendered as null. Null elements of arrays are always rendered.
+//
+// The default is to render nil values as null.
+func WithOmitNull(enabled bool) Option {
+	return func(o *options) {
+		o.omitNull = enabled
+	}
+}
+
+func optionsWithDefaults(opts []Option) options {
+	o := options{
+		decodeOptions: defaultDecodeOptions(),
+		encodeOptions: defaultEncodeOptions(),
+	}
+
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	return o
+}
+
+func defaultDecodeOptions() decodeOptions {
+	return decodeOptions{
+		maxDepth: DefaultMaxDepth,
+	}
+}
+
+func defaultEncodeOptions() encodeOptions {
+	return encodeOptions{
+		escapeHTML: true,
+	}
+}
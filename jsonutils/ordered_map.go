@@ -16,49 +16,1022 @@ package jsonutils
 
 import (
 	"bytes"
+	"context"
+	"encoding"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"reflect"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // JSONMapSlice represents a JSON object, with the order of keys maintained.
+//
+// When unmarshaled, the values held by a JSONMapSlice are of the following go types, at any depth:
+//
+//   - JSON objects are [JSONMapSlice] values
+//   - JSON arrays are []any values
+//   - JSON strings are string values
+//   - JSON numbers are int64 values when they are integers which fit, and float64 values otherwise,
+//     or [json.Number] values with [WithUseNumber]. Numbers which overflow may be [json.Number] values,
+//     depending on the [NumberOverflowPolicy]
+//   - JSON booleans are bool values
+//   - JSON null is a nil any
+//
+// With [WithLazyNested], nested objects and arrays may also be [LazyValue] pointers, until they are accessed.
+//
+// Values set by callers may be of other types, which are rendered as documented by [WriteJSON].
 type JSONMapSlice []JSONMapItem
 
-// MarshalJSON renders a [JSONMapSlice] as JSON bytes, preserving the order of keys.
-func (s JSONMapSlice) MarshalJSON() ([]byte, error) {
-	w := &jsonBuffer{
-		buffer: make([]byte, 0),
+// Interface returns the receiver as an any value.
+//
+// This provides a view of the object consistent with its nested values, e.g. to process the whole document
+// with a function which switches on the types listed by [JSONMapSlice]. The receiver is not copied.
+func (s JSONMapSlice) Interface() any {
+	return s
+}
+
+// MarshalJSON renders a [JSONMapSlice] as JSON bytes, preserving the order of keys.
+func (s JSONMapSlice) MarshalJSON() ([]byte, error) {
+	return s.MarshalJSONWithOptions()
+}
+
+// MarshalJSONWithOptions renders a [JSONMapSlice] as JSON bytes, like [JSONMapSlice.MarshalJSON],
+// with some options to alter the default encoding behavior.
+func (s JSONMapSlice) MarshalJSONWithOptions(opts ...Option) ([]byte, error) {
+	w := poolOfJSONBuffers.BorrowJSONBuffer(optionsWithDefaults(opts).encodeOptions)
+	defer poolOfJSONBuffers.RedeemJSONBuffer(w)
+
+	w.grow(s.estimatedSize())
+	s.JSONmarshal(w)
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	return w.bytes(), nil
+}
+
+// MarshalJSONTo renders a [JSONMapSlice] as JSON, like [JSONMapSlice.MarshalJSON],
+// and appends the result to a caller-provided buffer.
+//
+// This is useful to compose a larger document from many fragments, reusing the same buffer.
+// The encoding is carried out directly in the spare capacity of the buffer, when there is some.
+//
+// If an error occurs, the buffer is left unchanged.
+func (s JSONMapSlice) MarshalJSONTo(buf *bytes.Buffer, opts ...Option) error {
+	buf.Grow(s.estimatedSize())
+	current := buf.Bytes()
+
+	w := &jsonBuffer{
+		buffer:        current[len(current):],
+		encodeOptions: optionsWithDefaults(opts).encodeOptions,
+	}
+	s.JSONmarshal(w)
+	if w.err != nil {
+		return w.err
+	}
+
+	_, _ = buf.Write(w.buffer) // the error is always nil
+
+	return nil
+}
+
+// MarshalJSONIndent renders a [JSONMapSlice] as indented JSON bytes, preserving the order of keys.
+//
+// Each JSON element begins on a new line beginning with prefix followed by one or more copies
+// of indent according to the nesting level, like [json.MarshalIndent].
+func (s JSONMapSlice) MarshalJSONIndent(prefix, indent string) ([]byte, error) {
+	return s.MarshalJSONIndentWithOptions(prefix, indent)
+}
+
+// MarshalJSONIndentWithOptions renders a [JSONMapSlice] as indented JSON bytes, like [JSONMapSlice.MarshalJSONIndent],
+// with some options to alter the default encoding behavior.
+func (s JSONMapSlice) MarshalJSONIndentWithOptions(prefix, indent string, opts ...Option) ([]byte, error) {
+	w := poolOfJSONBuffers.BorrowJSONBuffer(optionsWithDefaults(opts).encodeOptions)
+	defer poolOfJSONBuffers.RedeemJSONBuffer(w)
+
+	w.indented = true
+	w.prefix = prefix
+	w.indent = indent
+	w.grow(s.estimatedSize())
+	s.JSONmarshal(w)
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	return w.bytes(), nil
+}
+
+// EncodeTo renders a [JSONMapSlice] as JSON, preserving the order of keys,
+// and writes the result incrementally to the provided [io.Writer].
+//
+// The output is the same as [JSONMapSlice.MarshalJSON], but the whole output is never held in memory.
+// This is useful to stream large documents directly to a file or an HTTP response.
+func (s JSONMapSlice) EncodeTo(w io.Writer) error {
+	jw := newJSONWriter(w, defaultEncodeOptions())
+	s.JSONmarshal(jw)
+	jw.flush()
+
+	return jw.err
+}
+
+// Get returns the value associated to a key, and whether this key was found.
+//
+// If the key appears several times, the first occurrence is returned.
+// A [LazyValue] is decoded and replaced in place by its decoded value.
+func (s JSONMapSlice) Get(key string) (any, bool) {
+	if i := s.index(key); i >= 0 {
+		return s.valueAt(i), true
+	}
+
+	return nil, false
+}
+
+// GetFold returns the value associated to a key, like [JSONMapSlice.Get], but matches keys case-insensitively,
+// using Unicode case folding like [strings.EqualFold].
+//
+// If several keys differ only by case, e.g. "Content-Type" and "content-type", the first one in the order
+// of the object is returned, even if another key matches exactly. Use [JSONMapSlice.Get] first when an exact
+// match should take precedence.
+func (s JSONMapSlice) GetFold(key string) (any, bool) {
+	for i := range s {
+		if strings.EqualFold(s[i].Key, key) {
+			return s.valueAt(i), true
+		}
+	}
+
+	return nil, false
+}
+
+// Has indicates if a key is present.
+func (s JSONMapSlice) Has(key string) bool {
+	return s.index(key) >= 0
+}
+
+// Set the value of a key.
+//
+// If the key already exists, its value is updated in place and the key keeps its position.
+// Otherwise, a new key is appended.
+func (s *JSONMapSlice) Set(key string, value any) {
+	if i := s.index(key); i >= 0 {
+		(*s)[i].Value = value
+
+		return
+	}
+
+	*s = append(*s, JSONMapItem{Key: key, Value: value})
+}
+
+// Delete removes a key, preserving the order of the remaining keys.
+//
+// It returns true if the key was found.
+func (s *JSONMapSlice) Delete(key string) bool {
+	i := s.index(key)
+	if i < 0 {
+		return false
+	}
+
+	*s = append((*s)[:i], (*s)[i+1:]...)
+
+	return true
+}
+
+// InsertAt sets the value of a key at a given position, preserving the order of the other keys.
+//
+// The index is the position of the key in the result, and is clamped to the bounds of the object:
+// a negative index inserts the key first, and an index beyond the end appends it.
+//
+// If the key already exists, its value is updated and it is moved to this position.
+func (s *JSONMapSlice) InsertAt(index int, key string, value any) {
+	if i := s.index(key); i >= 0 {
+		(*s)[i].Value = value
+		s.moveFrom(i, index)
+
+		return
+	}
+
+	index = clampIndex(index, len(*s))
+	*s = append(*s, JSONMapItem{})
+	copy((*s)[index+1:], (*s)[index:])
+	(*s)[index] = JSONMapItem{Key: key, Value: value}
+}
+
+// MoveKey moves a key to a given position, preserving the order of the other keys.
+//
+// The index is the position of the key in the result, and is clamped to the bounds of the object.
+// If the key appears several times, the first occurrence is moved.
+//
+// It returns true if the key was found.
+func (s *JSONMapSlice) MoveKey(key string, toIndex int) bool {
+	i := s.index(key)
+	if i < 0 {
+		return false
+	}
+
+	s.moveFrom(i, toIndex)
+
+	return true
+}
+
+// moveFrom moves the item at position i to a new position, shifting the items in between.
+func (s JSONMapSlice) moveFrom(i, to int) {
+	to = clampIndex(to, len(s)-1)
+	item := s[i]
+
+	switch {
+	case to < i:
+		copy(s[to+1:i+1], s[to:i])
+	case to > i:
+		copy(s[i:to], s[i+1:to+1])
+	}
+
+	s[to] = item
+}
+
+func clampIndex(index, upper int) int {
+	if index < 0 {
+		return 0
+	}
+
+	if index > upper {
+		return upper
+	}
+
+	return index
+}
+
+// Len returns the number of keys in a [JSONMapSlice], including duplicate keys.
+func (s JSONMapSlice) Len() int {
+	return len(s)
+}
+
+// IsEmpty tells if a [JSONMapSlice] is nil or has no keys.
+func (s JSONMapSlice) IsEmpty() bool {
+	return len(s) == 0
+}
+
+// String renders a [JSONMapSlice] as compact JSON, which is convenient in log lines and test failures.
+//
+// String never panics: if the object cannot be marshaled, a marker describing the error is returned instead.
+func (s JSONMapSlice) String() (str string) {
+	defer func() {
+		if r := recover(); r != nil {
+			str = fmt.Sprintf("%%!(JSON_PANIC=%v)", r)
+		}
+	}()
+
+	jazon, err := s.MarshalJSON()
+	if err != nil {
+		return fmt.Sprintf("%%!(JSON_ERROR=%v)", err)
+	}
+
+	return string(jazon)
+}
+
+// Clone returns a deep copy of a [JSONMapSlice].
+//
+// Nested [JSONMapSlice] and []any values are copied recursively, so that mutating the clone never
+// affects the original. Other values are copied as-is.
+func (s JSONMapSlice) Clone() JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	c := make(JSONMapSlice, len(s))
+	for i, item := range s {
+		c[i] = JSONMapItem{Key: item.Key, Value: cloneValue(item.Value), Comment: item.Comment}
+	}
+
+	return c
+}
+
+func cloneValue(value any) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		return v.Clone()
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		c := make([]any, len(v))
+		for i, elem := range v {
+			c[i] = cloneValue(elem)
+		}
+
+		return c
+	default:
+		return value
+	}
+}
+
+func (s JSONMapSlice) index(key string) int {
+	for i := range s {
+		if s[i].Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// jsonBuffer accumulates JSON output.
+//
+// When backed by an [io.Writer], the buffer is periodically flushed to the writer.
+type jsonBuffer struct {
+	buffer []byte
+	err    error
+	w      io.Writer
+
+	encodeOptions
+
+	// indentation settings
+	indented bool
+	prefix   string
+	indent   string
+	depth    int
+}
+
+type jsonDecoder struct {
+	decoder      *json.Decoder
+	currentToken json.Token
+	err          error
+	ctx          context.Context   // optional, to cancel decoding
+	depth        int               // current nesting depth of objects and arrays
+	nodes        int               // number of values decoded so far
+	keys         map[string]string // interned keys, with the WithInternKeys option
+	sizeHint     int               // size of the input in bytes, if known, to preallocate the top-level object
+	reuse        JSONMapSlice      // a slice whose backing array is reused by the top-level object, if any
+
+	decodeOptions
+}
+
+func newJSONDecoder(r io.Reader, o decodeOptions) *jsonDecoder {
+	r = skipBOM(r)
+	if o.allowTrailingCommas {
+		r = skipTrailingCommas(r)
+	}
+
+	d := &jsonDecoder{
+		decoder:       json.NewDecoder(r),
+		decodeOptions: o,
+	}
+	d.decoder.UseNumber()
+
+	return d
+}
+
+// startObject consumes the opening token of a JSON object.
+//
+// It reports if the input is empty or a JSON null instead of an object.
+func (d *jsonDecoder) startObject() (isNull bool, err error) {
+	t, err := d.decoder.Token()
+	if err == io.EOF {
+		return true, nil
+	}
+	if err != nil {
+		return false, asParseError(d.decoder, err)
+	}
+	if t == nil {
+		return true, nil
+	}
+
+	if del, ok := t.(json.Delim); !ok || del != '{' {
+		return false, newParseError(d.decoder, "a JSON object", t)
+	}
+
+	if !d.countNode(t) || !d.enterNested(t) {
+		return false, d.err
+	}
+
+	return false, nil
+}
+
+// countNode accounts for a new value in the document.
+//
+// It reports false and records an error if the maximum number of nodes is exceeded.
+func (d *jsonDecoder) countNode(t json.Token) bool {
+	d.nodes++
+	if d.maxTotalNodes > 0 && d.nodes > d.maxTotalNodes {
+		d.err = newParseError(d.decoder, fmt.Sprintf("at most %d values in the JSON document", d.maxTotalNodes), t)
+
+		return false
+	}
+
+	return true
+}
+
+// enterNested accounts for a new level of nesting when an object or an array starts.
+//
+// It reports false and records an error if the maximum depth is exceeded.
+func (d *jsonDecoder) enterNested(t json.Token) bool {
+	d.depth++
+	if d.maxDepth > 0 && d.depth > d.maxDepth {
+		d.err = newParseError(d.decoder, fmt.Sprintf("at most %d levels of nesting", d.maxDepth), t)
+
+		return false
+	}
+
+	return true
+}
+
+// internKey returns a previously decoded key identical to this one, if any, with the option to intern keys.
+func (d *jsonDecoder) internKey(key string) string {
+	if !d.internKeys {
+		return key
+	}
+
+	if interned, ok := d.keys[key]; ok {
+		return interned
+	}
+
+	if d.keys == nil {
+		d.keys = make(map[string]string)
+	}
+	if len(d.keys) < MaxInternedKeys {
+		d.keys[key] = key
+	}
+
+	return key
+}
+
+// leaveNested accounts for the end of an object or an array.
+func (d *jsonDecoder) leaveNested() {
+	d.depth--
+}
+
+// nextToken reads the next token inside a JSON value.
+//
+// Any decoding error is recorded and reported as a failure: since the value is incomplete,
+// reaching the end of the input is reported as [io.ErrUnexpectedEOF].
+//
+// Decoding is aborted as soon as the context of the decoder, if any, is done.
+func (d *jsonDecoder) nextToken() (json.Token, bool) {
+	if !d.checkContext() {
+		return nil, false
+	}
+
+	t, err := d.decoder.Token()
+	if err != nil {
+		d.setReadError(err)
+
+		return nil, false
+	}
+
+	return t, true
+}
+
+// checkContext reports false and records the error of the context, if it is done.
+func (d *jsonDecoder) checkContext() bool {
+	if d.ctx == nil {
+		return true
+	}
+
+	if err := d.ctx.Err(); err != nil {
+		d.err = err
+
+		return false
+	}
+
+	return true
+}
+
+// setReadError records an error returned by the underlying [json.Decoder].
+//
+// Since a value is always expected when reading, the end of the input is reported as [io.ErrUnexpectedEOF].
+func (d *jsonDecoder) setReadError(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	d.err = asParseError(d.decoder, err)
+}
+
+// decodeDocument decodes a complete JSON document, which must be a JSON object or null.
+//
+// Unless trailing content is allowed, the document must be followed by the end of the input.
+func (d *jsonDecoder) decodeDocument() (JSONMapSlice, error) {
+	isNull, err := d.startObject()
+	if err != nil {
+		return nil, err
+	}
+
+	var result JSONMapSlice
+	if !isNull {
+		result.JSONunmarshal(d)
+		if d.err != nil {
+			return nil, d.err
+		}
+	}
+
+	if err := d.endDocument(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// endDocument checks that no content follows the top-level JSON value, unless trailing content is allowed.
+func (d *jsonDecoder) endDocument() error {
+	if d.allowTrailing {
+		return nil
+	}
+
+	t, err := d.decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return asParseError(d.decoder, err)
+	}
+
+	return newParseError(d.decoder, "the end of the input after the JSON value", t)
+}
+
+// decodeObject decodes the key-value pairs of a JSON object, up to its closing delimiter.
+//
+// The callback is invoked whenever a key-value pair is complete.
+// Decoding stops if the callback returns an error.
+func (d *jsonDecoder) decodeObject(fn func(JSONMapItem) error) {
+	for keys := 1; ; keys++ {
+		t, ok := d.nextToken()
+		if !ok {
+			return
+		}
+		if del, ok := t.(json.Delim); ok && del == '}' {
+			return
+		}
+		if d.maxKeys > 0 && keys > d.maxKeys {
+			d.err = newParseError(d.decoder, fmt.Sprintf("at most %d keys in a JSON object", d.maxKeys), t)
+			return
+		}
+		d.currentToken = t
+		var mi JSONMapItem
+		mi.UnmarshalCustomJSON(d)
+		if d.err != nil {
+			return
+		}
+
+		if err := fn(mi); err != nil {
+			d.err = err
+			return
+		}
+	}
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// flushThreshold is the size of the buffer beyond which a writer-backed [jsonBuffer] is flushed.
+const flushThreshold = 4096
+
+func newJSONWriter(w io.Writer, o encodeOptions) *jsonBuffer {
+	return &jsonBuffer{
+		buffer:        make([]byte, 0, flushThreshold),
+		w:             w,
+		encodeOptions: o,
+	}
+}
+
+// grow ensures that the buffer may receive at least n more bytes without another allocation.
+func (jb *jsonBuffer) grow(n int) {
+	if cap(jb.buffer)-len(jb.buffer) >= n {
+		return
+	}
+
+	buffer := make([]byte, len(jb.buffer), len(jb.buffer)+n)
+	copy(buffer, jb.buffer)
+	jb.buffer = buffer
+}
+
+// bytes returns a copy of the buffered output, which remains valid after the buffer is recycled.
+func (jb *jsonBuffer) bytes() []byte {
+	out := make([]byte, len(jb.buffer))
+	copy(out, jb.buffer)
+
+	return out
+}
+
+// flush writes the buffered output to the underlying writer, if any.
+func (jb *jsonBuffer) flush() {
+	if jb.w == nil || jb.err != nil || len(jb.buffer) == 0 {
+		return
+	}
+
+	_, jb.err = jb.w.Write(jb.buffer)
+	jb.buffer = jb.buffer[:0]
+}
+
+// flushIfFull flushes a writer-backed buffer when it grows beyond its threshold.
+func (jb *jsonBuffer) flushIfFull() {
+	if jb.w != nil && len(jb.buffer) >= flushThreshold {
+		jb.flush()
+	}
+}
+
+func (jb *jsonBuffer) appendRawByte(b byte) {
+	jb.buffer = append(jb.buffer, b)
+}
+
+func (jb *jsonBuffer) appendByteSlice(b []byte) {
+	jb.buffer = append(jb.buffer, b...)
+}
+
+// appendString writes a quoted JSON string, escaped like [json.Marshal] does.
+//
+// HTML-sensitive characters are only escaped if the escapeHTML option is enabled.
+//
+// Invalid UTF-8 sequences are replaced by the unicode replacement character U+FFFD.
+func (jb *jsonBuffer) appendString(str string) {
+	const hex = "0123456789abcdef"
+
+	jb.buffer = append(jb.buffer, '"')
+	start := 0
+	for i := 0; i < len(str); {
+		if b := str[i]; b < utf8.RuneSelf {
+			if b >= ' ' && b != '"' && b != '\\' && (!jb.escapeHTML || b != '<' && b != '>' && b != '&') {
+				i++
+				continue
+			}
+
+			jb.buffer = append(jb.buffer, str[start:i]...)
+			switch b {
+			case '\\', '"':
+				jb.buffer = append(jb.buffer, '\\', b)
+			case '\b':
+				jb.buffer = append(jb.buffer, '\\', 'b')
+			case '\f':
+				jb.buffer = append(jb.buffer, '\\', 'f')
+			case '\n':
+				jb.buffer = append(jb.buffer, '\\', 'n')
+			case '\r':
+				jb.buffer = append(jb.buffer, '\\', 'r')
+			case '\t':
+				jb.buffer = append(jb.buffer, '\\', 't')
+			default:
+				// control characters and HTML-sensitive characters
+				jb.buffer = append(jb.buffer, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(str[i:])
+		if r == utf8.RuneError && size == 1 {
+			jb.buffer = append(jb.buffer, str[start:i]...)
+			jb.buffer = utf8.AppendRune(jb.buffer, utf8.RuneError)
+			i += size
+			start = i
+
+			continue
+		}
+
+		// U+2028 and U+2029 are valid JSON, but not valid javascript
+		if r == '\u2028' || r == '\u2029' {
+			jb.buffer = append(jb.buffer, str[start:i]...)
+			jb.buffer = append(jb.buffer, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+
+			continue
+		}
+
+		i += size
+	}
+	jb.buffer = append(jb.buffer, str[start:]...)
+	jb.buffer = append(jb.buffer, '"')
+}
+
+// appendNewline starts a new indented line, when indentation is enabled.
+func (jb *jsonBuffer) appendNewline() {
+	if !jb.indented {
+		return
+	}
+
+	jb.buffer = append(jb.buffer, '\n')
+	jb.buffer = append(jb.buffer, jb.prefix...)
+	for i := 0; i < jb.depth; i++ {
+		jb.buffer = append(jb.buffer, jb.indent...)
+	}
+}
+
+// appendComment writes a trailing line comment, when comments are enabled with indentation.
+//
+// Line breaks in the comment are replaced by spaces, so the comment remains on a single line.
+func (jb *jsonBuffer) appendComment(comment string) {
+	if !jb.indented || !jb.comments || comment == "" {
+		return
+	}
+
+	jb.buffer = append(jb.buffer, " // "...)
+	jb.buffer = append(jb.buffer, strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(comment)...)
+}
+
+// appendColon writes the separator between a key and its value.
+func (jb *jsonBuffer) appendColon() {
+	jb.buffer = append(jb.buffer, ':')
+	if jb.indented {
+		jb.buffer = append(jb.buffer, ' ')
 	}
-	s.JSONmarshal(w)
+}
+
+// appendValue writes any value as JSON.
+//
+// Nested [JSONMapSlice] and []any values, or pointers to such values, are walked recursively,
+// [json.RawMessage] values are appended verbatim, [OrderedMarshaler] values render themselves,
+// other values are rendered with [WriteJSON].
+//
+// When the buffer is backed by a writer, slices and arrays of other types are walked too,
+// so large arrays are not held in memory all at once.
+func (jb *jsonBuffer) appendValue(value any) {
+	switch v := value.(type) {
+	case JSONMapSlice:
+		v.JSONmarshal(jb)
+	case string:
+		jb.appendString(v)
+	case json.Number:
+		jb.appendNumber(v)
+	case []any:
+		jb.appendArray(v)
+	case *JSONMapSlice:
+		if v == nil {
+			jb.appendByteSlice(nullJSON)
+
+			return
+		}
+		v.JSONmarshal(jb)
+	case *[]any:
+		if v == nil {
+			jb.appendByteSlice(nullJSON)
+
+			return
+		}
+		jb.appendArray(*v)
+	case json.RawMessage:
+		jb.appendRawMessage(v)
+	case *LazyValue:
+		resolved, err := v.Value()
+		if err != nil {
+			jb.err = err
+
+			return
+		}
+		jb.appendValue(resolved)
+	case float64:
+		jb.appendFloat(v)
+	case OrderedMarshaler:
+		jb.appendOrderedMarshaler(v)
+	default:
+		if jb.w != nil && jb.appendStreamedSlice(v) {
+			return
+		}
+
+		jsonRes, err := jb.marshalOpaque(v)
+		if err != nil {
+			jb.err = err
+
+			return
+		}
 
-	return w.buffer, w.err
+		jb.appendOpaque(jsonRes)
+	}
 }
 
-type jsonBuffer struct {
-	buffer []byte
-	err    error
+// appendNumber writes a [json.Number], which must be a valid JSON number literal like with [json.Marshal].
+func (jb *jsonBuffer) appendNumber(n json.Number) {
+	if n == "" {
+		// same as the standard library
+		jb.appendRawByte('0')
+
+		return
+	}
+
+	if !isValidNumber(string(n)) {
+		jb.err = fmt.Errorf("invalid number literal %q: %w", n, ErrJSON)
+
+		return
+	}
+
+	jb.appendByteSlice([]byte(n))
 }
 
-type jsonDecoder struct {
-	decoder      *json.Decoder
-	currentToken json.Token
-	err          error
+// isValidNumber reports whether a string is a valid JSON number literal.
+//
+// This follows the rule applied by the standard library to [json.Number] values.
+func isValidNumber(s string) bool {
+	// see https://tools.ietf.org/html/rfc7159#section-6
+	if s == "" {
+		return false
+	}
+
+	// optional -
+	if s[0] == '-' {
+		s = s[1:]
+		if s == "" {
+			return false
+		}
+	}
+
+	// digits
+	switch {
+	case s[0] == '0':
+		s = s[1:]
+	case '1' <= s[0] && s[0] <= '9':
+		s = s[1:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	default:
+		return false
+	}
+
+	// . followed by 1 or more digits
+	if len(s) >= 2 && s[0] == '.' && '0' <= s[1] && s[1] <= '9' {
+		s = s[2:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+
+	// e or E followed by an optional - or + and 1 or more digits
+	if len(s) >= 2 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s[0] == '+' || s[0] == '-' {
+			s = s[1:]
+			if s == "" {
+				return false
+			}
+		}
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+
+	// make sure we are at the end
+	return s == ""
 }
 
-func (jb *jsonBuffer) appendRawByte(b byte) {
-	jb.buffer = append(jb.buffer, b)
+// maxSafeInteger is the largest integer such that all integers up to it are exactly represented by a float64.
+const maxSafeInteger = float64(1<<53 - 1)
+
+// appendFloat writes a float64 like [json.Marshal] does.
+//
+// Whole numbers get a trailing ".0" when the option is enabled, and whole numbers within [-2^53+1, 2^53-1]
+// are written as integers with the corresponding option.
+func (jb *jsonBuffer) appendFloat(f float64) {
+	if jb.floatAsInteger && f == math.Trunc(f) && math.Abs(f) <= maxSafeInteger {
+		jb.appendByteSlice(strconv.AppendInt(nil, int64(f), 10))
+
+		return
+	}
+
+	jsonRes, err := jb.marshalOpaque(f)
+	if err != nil {
+		jb.err = err
+
+		return
+	}
+
+	jb.appendByteSlice(jsonRes)
+	if jb.floatTrailingZero && bytes.IndexAny(jsonRes, ".eE") < 0 {
+		jb.appendByteSlice([]byte(".0"))
+	}
 }
 
-func (jb *jsonBuffer) appendByteSlice(b []byte) {
-	jb.buffer = append(jb.buffer, b...)
+// appendOpaque appends some JSON rendered independently from the buffer.
+func (jb *jsonBuffer) appendOpaque(jsonRes []byte) {
+	if !jb.indented || len(jsonRes) == 0 || (jsonRes[0] != '{' && jsonRes[0] != '[') {
+		jb.appendByteSlice(jsonRes)
+
+		return
+	}
+
+	// indent opaque containers at the current nesting level
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, jsonRes, jb.prefix+strings.Repeat(jb.indent, jb.depth), jb.indent); err != nil {
+		jb.err = err
+
+		return
+	}
+	jb.appendByteSlice(buf.Bytes())
 }
 
-func (jb *jsonBuffer) appendString(b []byte) {
-	jb.buffer = append(jb.buffer, '"')
-	jb.buffer = append(jb.buffer, b...)
-	jb.buffer = append(jb.buffer, '"')
+// appendRawMessage appends a pre-serialized JSON value verbatim, without re-encoding it.
+//
+// The raw message is checked to be valid JSON. A nil raw message is rendered as null.
+// When the buffer is indented, the raw message is indented at the current nesting level.
+func (jb *jsonBuffer) appendRawMessage(raw json.RawMessage) {
+	if raw == nil {
+		jb.appendByteSlice(nullJSON)
+
+		return
+	}
+
+	if !json.Valid(raw) {
+		jb.err = fmt.Errorf("invalid raw JSON message %q: %w", raw, ErrJSON)
+
+		return
+	}
+
+	if jb.indented {
+		raw = bytes.TrimSpace(raw)
+	}
+
+	jb.appendOpaque(raw)
+}
+
+// marshalOpaque renders a value that the buffer doesn't know to walk.
+func (jb *jsonBuffer) marshalOpaque(value any) ([]byte, error) {
+	if jb.escapeHTML {
+		return WriteJSON(value)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+func (jb *jsonBuffer) appendArray(a []any) {
+	if a == nil {
+		jb.appendByteSlice(nullJSON)
+		return
+	}
+
+	jb.appendElements(len(a), func(i int) any { return a[i] })
+}
+
+// appendStreamedSlice writes a slice or array of any type one element at a time, so the output
+// may be flushed to the writer while encoding.
+//
+// It reports false if the value is not a slice or an array, or if it is rendered specifically by [json.Marshal],
+// e.g. []byte or types implementing [json.Marshaler].
+func (jb *jsonBuffer) appendStreamedSlice(value any) bool {
+	v := reflect.ValueOf(value)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return false
+	}
+
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		jb.appendByteSlice(nullJSON)
+
+		return true
+	}
+
+	// like json.Marshal, use the methods of pointer receivers on addressable elements
+	elemType := v.Type().Elem()
+	byAddr := v.Kind() == reflect.Slice && elemType.Kind() != reflect.Pointer &&
+		(reflect.PointerTo(elemType).Implements(jsonMarshalerType) || reflect.PointerTo(elemType).Implements(textMarshalerType))
+
+	jb.appendElements(v.Len(), func(i int) any {
+		if byAddr {
+			return v.Index(i).Addr().Interface()
+		}
+
+		return v.Index(i).Interface()
+	})
+
+	return true
+}
+
+// appendElements writes a JSON array with n elements.
+func (jb *jsonBuffer) appendElements(n int, elem func(int) any) {
+	jb.appendRawByte('[')
+
+	if n == 0 {
+		jb.appendRawByte(']')
+		return
+	}
+
+	jb.depth++
+	for i := 0; i < n && jb.err == nil; i++ {
+		if i > 0 {
+			jb.appendRawByte(',')
+		}
+		jb.appendNewline()
+		jb.appendValue(elem(i))
+		jb.flushIfFull()
+	}
+	jb.depth--
+	jb.appendNewline()
+
+	jb.appendRawByte(']')
 }
 
 func (s JSONMapSlice) JSONmarshal(w *jsonBuffer) {
@@ -69,17 +1042,34 @@ func (s JSONMapSlice) JSONmarshal(w *jsonBuffer) {
 
 	w.appendRawByte('{')
 
-	if len(s) == 0 {
+	last := len(s) - 1
+	if w.omitNull {
+		for last >= 0 && s[last].Value == nil {
+			last--
+		}
+	}
+
+	if last < 0 {
 		w.appendRawByte('}')
 		return
 	}
 
-	s[0].JSONmarshal(w)
+	w.depth++
+	for i := 0; i <= last && w.err == nil; i++ {
+		if w.omitNull && s[i].Value == nil {
+			continue
+		}
 
-	for i := 1; i < len(s); i++ {
-		w.appendRawByte(',')
+		w.appendNewline()
 		s[i].JSONmarshal(w)
+		if i < last {
+			w.appendRawByte(',')
+		}
+		w.appendComment(s[i].Comment)
+		w.flushIfFull()
 	}
+	w.depth--
+	w.appendNewline()
 
 	w.appendRawByte('}')
 }
@@ -87,47 +1077,216 @@ func (s JSONMapSlice) JSONmarshal(w *jsonBuffer) {
 // UnmarshalJSON builds a [JSONMapSlice] from JSON bytes, preserving the order of keys.
 //
 // Inner objects are unmarshaled as [JSONMapSlice] slices and not map[string]any.
+//
+// A leading UTF-8 byte order mark is ignored, as well as any whitespace around the object.
+// The offsets reported by a [ParseError] are then counted after the byte order mark.
 func (s *JSONMapSlice) UnmarshalJSON(data []byte) error {
-	d := &jsonDecoder{
-		decoder: json.NewDecoder(bytes.NewReader(data)),
+	return s.UnmarshalJSONWithOptions(data)
+}
+
+// UnmarshalJSONWithOptions builds a [JSONMapSlice] from JSON bytes, like [JSONMapSlice.UnmarshalJSON],
+// with some options to alter the default decoding behavior.
+func (s *JSONMapSlice) UnmarshalJSONWithOptions(data []byte, opts ...Option) error {
+	d := newJSONDecoder(bytes.NewReader(data), optionsWithDefaults(opts).decodeOptions)
+	d.sizeHint = len(data)
+	result, err := d.decodeDocument()
+	if err != nil {
+		return err
 	}
-	t, err := d.decoder.Token()
-	if err == io.EOF {
-		return nil
+
+	*s = result
+
+	return nil
+}
+
+// UnmarshalInto builds a [JSONMapSlice] from JSON bytes, like [JSONMapSlice.UnmarshalJSONWithOptions],
+// but reuses the backing array of the receiver to hold the keys of the top-level object.
+//
+// This reduces the pressure on the garbage collector in loops parsing many documents,
+// e.g. with slices recycled from a [sync.Pool]. Nested objects and arrays are allocated as usual.
+//
+// The previous content of the receiver is overwritten, so it should not be retained elsewhere:
+// unused entries of the backing array are cleared, and no key of a previous parse remains.
+// When the JSON document is null, the receiver is set to nil.
+//
+// If an error occurs, the receiver is emptied but retains its capacity.
+func (s *JSONMapSlice) UnmarshalInto(data []byte, opts ...Option) error {
+	d := newJSONDecoder(bytes.NewReader(data), optionsWithDefaults(opts).decodeOptions)
+	d.sizeHint = len(data)
+	d.reuse = *s
+	result, err := d.decodeDocument()
+	if err != nil {
+		clearItems((*s)[:cap(*s)])
+		*s = (*s)[:0]
+
+		return err
 	}
 
-	_, ok := t.(json.Delim)
+	*s = result
+
+	return nil
+}
+
+// Unmarshal builds a value from JSON bytes of any kind, preserving the order of keys in objects.
+//
+// Unlike [JSONMapSlice.UnmarshalJSON], the JSON document is not required to be an object:
+//
+//   - objects are unmarshaled as [JSONMapSlice]
+//   - arrays are unmarshaled as []any, with any inner objects as [JSONMapSlice]
+//   - scalars are returned directly, with numbers converted like for [JSONMapSlice]
+//   - null is returned as nil
+func Unmarshal(data []byte, opts ...Option) (any, error) {
+	d := newJSONDecoder(bytes.NewReader(data), optionsWithDefaults(opts).decodeOptions)
+
+	return d.decodeValue()
+}
+
+// decodeValue decodes a complete JSON document, with any root value.
+func (d *jsonDecoder) decodeValue() (any, error) {
+	t, ok := d.nextToken()
 	if !ok {
-		return fmt.Errorf("")
+		return nil, d.err
+	}
+
+	var mi JSONMapItem
+	d.currentToken = t
+	value := mi.asInterface(d)
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if err := d.endDocument(); err != nil {
+		return nil, err
 	}
 
-	s.JSONunmarshal(data, d)
-	return d.err
+	return value, nil
 }
 
-// JSONunmarshal builds a [JSONMapSlice] from JSON bytes, using CustomJSON
-func (s *JSONMapSlice) JSONunmarshal(data []byte, d *jsonDecoder) {
+// JSONunmarshal builds a [JSONMapSlice] from the tokens of a JSON object, using CustomJSON.
+//
+// The opening delimiter of the object must have been consumed already.
+//
+// When the size of the input is known, the top-level object takes a fast path: once its first keys are decoded,
+// the result is preallocated from an estimate of the number of keys, instead of growing one append at a time.
+//
+// The backing array of a slice passed to [JSONMapSlice.UnmarshalInto] is reused by the top-level object.
+func (s *JSONMapSlice) JSONunmarshal(d *jsonDecoder) {
+	size, reuse := d.sizeHint, d.reuse
+	d.sizeHint, d.reuse = 0, nil // nested objects don't know their size, and are allocated
+
+	reused := cap(reuse) > 0
+	var result JSONMapSlice
+	if reused {
+		result = reuse[:0]
+	} else {
+		result = make(JSONMapSlice, 0, initialKeys(size))
+	}
+	var seen map[string]int
+	if d.duplicateKeyPolicy != DuplicateKeyKeep {
+		seen = make(map[string]int)
+	}
 
-	result := make(JSONMapSlice, 0)
+	d.decodeObject(func(mi JSONMapItem) error {
+		if seen != nil {
+			if i, isDuplicate := seen[mi.Key]; isDuplicate {
+				switch d.duplicateKeyPolicy {
+				case DuplicateKeyFirst:
+					// skip this occurrence
+				case DuplicateKeyLast:
+					result[i].Value = mi.Value
+				default:
+					return fmt.Errorf("duplicate key %q in JSON object: %w", mi.Key, ErrJSON)
+				}
 
-	for {
-		t, err := d.decoder.Token()
-		if del, ok := t.(json.Delim); ok && del == '}' {
-			break
+				return nil
+			}
+			seen[mi.Key] = len(result)
 		}
-		if err == io.EOF {
-			break
+
+		if size > 0 && len(result) == cap(result) {
+			// the object is large: grow to the estimated number of keys
+			if estimate := d.estimatedKeys(len(result), size); estimate > cap(result) {
+				grown := make(JSONMapSlice, len(result), estimate)
+				copy(grown, result)
+				result = grown
+			}
 		}
-		d.currentToken = t
-		var mi JSONMapItem
-		mi.UnmarshalCustomJSON(d, data)
 
 		result = append(result, mi)
+
+		return nil
+	})
+	if d.err != nil {
+		return
+	}
+
+	switch {
+	case reused:
+		// release the values of a previous parse held by the backing array
+		clearItems(result[len(result):cap(result)])
+	case cap(result) > maxInitialKeys && cap(result) > 2*len(result):
+		// the estimate was too large: don't retain the unused capacity
+		result = append(make(JSONMapSlice, 0, len(result)), result...)
 	}
 
 	*s = result
 }
 
+// clearItems resets all items to their zero value.
+func clearItems(items JSONMapSlice) {
+	for i := range items {
+		items[i] = JSONMapItem{}
+	}
+}
+
+const (
+	// estimatedBytesPerKey is a low estimate of the size of a key and its value in a JSON object.
+	estimatedBytesPerKey = 32
+
+	// maxInitialKeys bounds the number of keys allocated before an object is known to be large.
+	maxInitialKeys = 16
+
+	// maxKeysGrowth bounds the growth of the top-level object, relative to the number of keys decoded so far.
+	maxKeysGrowth = 8
+)
+
+// initialKeys is the capacity allocated upfront for the top-level object, given the size of the input.
+//
+// Since the top-level object may hold a few keys with large values, only small objects are allocated upfront.
+func initialKeys(size int) int {
+	if keys := size / estimatedBytesPerKey; keys < maxInitialKeys {
+		return keys
+	}
+
+	return maxInitialKeys
+}
+
+// estimatedKeys extrapolates the number of keys of the top-level object from the number of keys decoded so far,
+// and the part of the input they span.
+//
+// Since a few small keys may be followed by a large value, the estimate is at most a small multiple of the keys
+// decoded so far, and it never exceeds the limits set with [WithMaxKeys] or [WithMaxTotalNodes].
+func (d *jsonDecoder) estimatedKeys(keys, size int) int {
+	offset := d.decoder.InputOffset()
+	if offset <= 0 {
+		return 0
+	}
+
+	estimate := int64(keys) * int64(size) / offset
+	if bound := int64(maxKeysGrowth) * int64(keys); estimate > bound {
+		estimate = bound
+	}
+	if d.maxKeys > 0 && estimate > int64(d.maxKeys) {
+		estimate = int64(d.maxKeys)
+	}
+	if remaining := int64(keys) + int64(d.maxTotalNodes-d.nodes); d.maxTotalNodes > 0 && estimate > remaining {
+		// each key to come holds one more value, at least
+		estimate = remaining
+	}
+
+	return int(estimate)
+}
+
 // JSONMapItem represents the value of a key in a JSON object held by [JSONMapSlice].
 //
 // Notice that JSONMapItem should not be marshaled to or unmarshaled from JSON directly,
@@ -135,85 +1294,136 @@ func (s *JSONMapSlice) JSONunmarshal(data []byte, d *jsonDecoder) {
 type JSONMapItem struct {
 	Key   string
 	Value any
+
+	// Comment is an optional annotation on this item, which is not part of the JSON data.
+	//
+	// It is ignored by all JSON marshaling and comparison methods, unless comments are explicitly
+	// enabled when rendering indented output (see [WithComments]).
+	Comment string
 }
 
 // MarshalCustomJSON renders a [JSONMapItem] as JSON bytes, using CustomJSON
 func (s JSONMapItem) JSONmarshal(jb *jsonBuffer) {
-	jb.appendString([]byte(s.Key))
-	jb.appendRawByte(':')
-	jsonRes, err := WriteJSON(s.Value)
-	if err != nil {
-		fmt.Println(s.Value)
-		jb.err = err
-	}
-	jb.appendByteSlice(jsonRes)
+	jb.appendString(s.Key)
+	jb.appendColon()
+	jb.appendValue(s.Value)
 }
 
-// UnmarshalCustomJSON builds a [JSONMapItem] from JSON bytes, using CustomJSON
-func (s *JSONMapItem) UnmarshalCustomJSON(d *jsonDecoder, data []byte) {
-	var key string
+// UnmarshalCustomJSON builds a [JSONMapItem] from the tokens of a JSON object, using CustomJSON.
+//
+// Inside an object, the decoder yields tokens alternating key, value, key, value:
+// the current token is the key and the next one starts the value.
+func (s *JSONMapItem) UnmarshalCustomJSON(d *jsonDecoder) {
 	var value any
-	offset := d.decoder.InputOffset()
-	if data[offset] == ':' {
-		key = d.currentToken.(string)
-	} else {
+	key, ok := d.currentToken.(string)
+	if !ok {
+		d.err = newParseError(d.decoder, "a JSON object key", d.currentToken)
 		return
 	}
-	t, err := d.decoder.Token()
-	if err != nil {
-		d.err = err
+	key = d.internKey(key)
+	if d.lazyNested {
+		s.Key = key
+		s.Value = d.lazyValue()
+
+		return
+	}
+
+	t, ok := d.nextToken()
+	if !ok {
 		return
 	}
 
 	d.currentToken = t
-	value = s.asInterface(d, data)
+	value = s.asInterface(d)
 
 	s.Key = key
 	s.Value = value
 }
 
-func (s *JSONMapItem) asInterface(d *jsonDecoder, data []byte) any {
+func (s *JSONMapItem) asInterface(d *jsonDecoder) any {
+	if !d.countNode(d.currentToken) {
+		return nil
+	}
+
 	switch n := d.currentToken.(type) {
 	case json.Delim:
+		if !d.enterNested(n) {
+			return nil
+		}
+		defer d.leaveNested()
+
 		converted := string(n)
 		if converted == "{" {
 			ret := make(JSONMapSlice, 0)
-			ret.JSONunmarshal(data, d)
+			ret.JSONunmarshal(d)
 			return ret
 		} else if converted == "[" {
 			ret := []any{}
 			for d.decoder.More() {
-				t, err := d.decoder.Token()
-				if err != nil {
-					d.err = err
+				t, ok := d.nextToken()
+				if !ok {
 					return nil
 				}
 				d.currentToken = t
-				ret = append(ret, s.asInterface(d, data))
+				ret = append(ret, s.asInterface(d))
+				if d.err != nil {
+					return nil
+				}
 			}
-			// advance
-			_, err := d.decoder.Token()
-			if err != nil {
-				d.err = err
+			// advance past the closing delimiter
+			if _, ok := d.nextToken(); !ok {
 				return nil
 			}
 			return ret
 		}
 	case string:
 		return n
-	case int64:
-		// determine if we may use an integer type
-		strN := strconv.FormatInt(n, 10)
-		if strings.ContainsRune(strN, '.') {
-			f, _ := strconv.ParseFloat(strN, 64)
-			return f
-		} else {
-			i, _ := strconv.ParseInt(strN, 10, 64)
-			return i
-		}
+	case json.Number:
+		return d.asNumber(n)
 	default:
 		return n
 	}
 
 	return nil
 }
+
+// asNumber determines if we may use an integer type to represent a JSON number.
+//
+// Numbers without a fractional part or exponent that fit into an int64 are returned as int64.
+// All other numbers are returned as float64, unless they overflow: this is handled by the [NumberOverflowPolicy].
+//
+// With [WithUseNumber], numbers are returned as [json.Number] values.
+func (d *jsonDecoder) asNumber(n json.Number) any {
+	if d.useNumber {
+		return n
+	}
+
+	if !strings.ContainsAny(n.String(), ".eE") {
+		if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+			return i
+		}
+
+		switch d.numberOverflowPolicy {
+		case NumberOverflowKeep:
+			return n
+		case NumberOverflowError:
+			d.err = newParseError(d.decoder, "an integer in the range of int64", n)
+
+			return nil
+		}
+	}
+
+	f, err := n.Float64() // the decoder guarantees a valid number literal, so this may only fail on range
+	if err != nil {
+		if d.numberOverflowPolicy == NumberOverflowKeep {
+			return n
+		}
+
+		// like json.Unmarshal does
+		d.err = newParseError(d.decoder, "a number in the range of float64", n)
+
+		return nil
+	}
+
+	return f
+}
@@ -0,0 +1,358 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func BenchmarkJSONMapSliceMarshal(b *testing.B) {
+	small := JSONMapSlice{
+		{Key: "name", Value: "a string value"},
+		{Key: "index", Value: int64(1)},
+		{Key: "values", Value: []any{true, "x", nil}},
+	}
+	large := makeLargeMapSlice(1000)
+
+	b.Run("small object", benchmarkMarshal(small))
+	b.Run("large object", benchmarkMarshal(large))
+}
+
+func benchmarkMarshal(data JSONMapSlice) func(*testing.B) {
+	return func(b *testing.B) {
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			buf, err := data.MarshalJSON()
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, _ = io.Discard.Write(buf)
+		}
+	}
+}
+
+func BenchmarkJSONMapSliceEncode(b *testing.B) {
+	data := makeLargeMapSlice(1000)
+
+	b.Run("MarshalJSON", func(b *testing.B) {
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			buf, err := data.MarshalJSON()
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, _ = io.Discard.Write(buf)
+		}
+	})
+
+	b.Run("EncodeTo", func(b *testing.B) {
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if err := data.EncodeTo(io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkJSONMapSliceMarshalSpec(b *testing.B) {
+	spec := makeSpecLikeMapSlice(1500) // about 1MB
+
+	b.Run("with pre-sized buffer", benchmarkMarshal(spec))
+
+	b.Run("without pre-sized buffer", func(b *testing.B) {
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			w := &jsonBuffer{encodeOptions: defaultEncodeOptions()}
+			spec.JSONmarshal(w)
+			if w.err != nil {
+				b.Fatal(w.err)
+			}
+			_, _ = io.Discard.Write(w.bytes())
+		}
+	})
+}
+
+func BenchmarkJSONMapSliceUnmarshalLazy(b *testing.B) {
+	spec, err := makeSpecLikeMapSlice(1500).MarshalJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, lazy := range []bool{false, true} {
+		b.Run("lazy="+strconv.FormatBool(lazy), func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				var data JSONMapSlice
+				if err := data.UnmarshalJSONWithOptions(spec, WithLazyNested(lazy)); err != nil {
+					b.Fatal(err)
+				}
+
+				if _, ok := data.GetString("swagger"); !ok {
+					b.Fatal("key not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkJSONMapSliceUnmarshalInternKeys(b *testing.B) {
+	records := make([]any, 0, 10000)
+	for i := 0; i < cap(records); i++ {
+		records = append(records, JSONMapSlice{
+			{Key: "identifier", Value: int64(i)},
+			{Key: "description", Value: "record"},
+			{Key: "created_at", Value: "2024-01-01"},
+			{Key: "attributes", Value: JSONMapSlice{{Key: "enabled", Value: true}, {Key: "weight", Value: 1.5}}},
+		})
+	}
+	doc, err := JSONMapSlice{{Key: "records", Value: records}}.MarshalJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, intern := range []bool{false, true} {
+		b.Run("intern="+strconv.FormatBool(intern), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var data JSONMapSlice
+			for i := 0; i < b.N; i++ {
+				data = nil
+				if err := data.UnmarshalJSONWithOptions(doc, WithInternKeys(intern)); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.StopTimer()
+			// report the memory retained by the last decoded document
+			data = nil
+			before := heapInUse()
+			if err := data.UnmarshalJSONWithOptions(doc, WithInternKeys(intern)); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(heapInUse()-before), "retained-B")
+			runtime.KeepAlive(data)
+		})
+	}
+}
+
+// BenchmarkCodec compares the ordered codec with [encoding/json] decoding into or encoding from a map,
+// for objects of different sizes.
+func BenchmarkCodec(b *testing.B) {
+	for _, size := range []struct {
+		name string
+		keys int
+	}{
+		{name: "small", keys: 8},
+		{name: "medium", keys: 100},
+		{name: "large", keys: 10000},
+	} {
+		data := makeLargeMapSlice(size.keys)
+		doc, err := data.MarshalJSON()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var stdData map[string]any
+		if err := json.Unmarshal(doc, &stdData); err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(size.name+"/Marshal/JSONMapSlice", func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := data.MarshalJSON(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(size.name+"/Marshal/encoding-json", func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := json.Marshal(stdData); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(size.name+"/Unmarshal/JSONMapSlice", func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				var result JSONMapSlice
+				if err := result.UnmarshalJSON(doc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(size.name+"/Unmarshal/encoding-json", func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				var result map[string]any
+				if err := json.Unmarshal(doc, &result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkJSONMapSliceUnmarshalInto(b *testing.B) {
+	flat := make(JSONMapSlice, 0, 100)
+	for i := 0; i < cap(flat); i++ {
+		flat = append(flat, JSONMapItem{Key: "key" + strconv.Itoa(i), Value: int64(i)})
+	}
+	doc, err := flat.MarshalJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("UnmarshalJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			var data JSONMapSlice
+			if err := data.UnmarshalJSON(doc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("UnmarshalInto", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		var data JSONMapSlice
+		for i := 0; i < b.N; i++ {
+			if err := data.UnmarshalInto(doc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func heapInUse() int64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return int64(stats.HeapAlloc)
+}
+
+// makeSpecLikeMapSlice builds an object which looks like an OpenAPI spec, with about 700 bytes per path.
+func makeSpecLikeMapSlice(paths int) JSONMapSlice {
+	pathItems := make(JSONMapSlice, 0, paths)
+	for i := 0; i < paths; i++ {
+		id := strconv.Itoa(i)
+		pathItems = append(pathItems, JSONMapItem{
+			Key: "/resources" + id + "/{id}",
+			Value: JSONMapSlice{
+				{Key: "get", Value: JSONMapSlice{
+					{Key: "operationId", Value: "getResource" + id},
+					{Key: "summary", Value: "Retrieves a single resource by its identifier"},
+					{Key: "parameters", Value: []any{
+						JSONMapSlice{
+							{Key: "name", Value: "id"},
+							{Key: "in", Value: "path"},
+							{Key: "required", Value: true},
+							{Key: "type", Value: "integer"},
+							{Key: "format", Value: "int64"},
+							{Key: "minimum", Value: int64(1)},
+						},
+					}},
+					{Key: "responses", Value: JSONMapSlice{
+						{Key: "200", Value: JSONMapSlice{
+							{Key: "description", Value: "the resource"},
+							{Key: "schema", Value: JSONMapSlice{{Key: "$ref", Value: "#/definitions/resource" + id}}},
+						}},
+						{Key: "default", Value: JSONMapSlice{
+							{Key: "description", Value: "unexpected error"},
+							{Key: "schema", Value: JSONMapSlice{{Key: "$ref", Value: "#/definitions/error"}}},
+						}},
+					}},
+					{Key: "tags", Value: []any{"resources", "read"}},
+					{Key: "x-rate-limit", Value: 10.5},
+				}},
+			},
+		})
+	}
+
+	return JSONMapSlice{
+		{Key: "swagger", Value: "2.0"},
+		{Key: "info", Value: JSONMapSlice{{Key: "title", Value: "a large API"}, {Key: "version", Value: "1.0.0"}}},
+		{Key: "paths", Value: pathItems},
+	}
+}
+
+func BenchmarkJSONMapSliceGet(b *testing.B) {
+	const size = 1000
+	data := makeLargeMapSlice(size)
+	keys := make([]string, size)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+
+	b.Run("linear", func(b *testing.B) {
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, ok := data.Get(keys[i%size]); !ok {
+				b.Fatal("key not found")
+			}
+		}
+	})
+
+	b.Run("indexed", func(b *testing.B) {
+		idx := data.Index()
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, ok := idx.Get(keys[i%size]); !ok {
+				b.Fatal("key not found")
+			}
+		}
+	})
+}
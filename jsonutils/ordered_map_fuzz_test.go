@@ -0,0 +1,79 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+)
+
+func FuzzRoundTrip(f *testing.F) {
+	for _, seed := range []string{
+		`{}`,
+		`null`,
+		`{"a":1,"b":{"c":[true,false,null,{"d":"x"}]},"e":[]}`,
+		`{"z":{"y":{"x":{"w":{"v":[[[[{}]]]]}}}}}`,
+		`{"clé":"välue","日本語":"テキスト","emoji 🎉":"🎉","":""}`,
+		`{"esc":"\"\\\/\b\f\n\r\t\u0000\u001f  <>&"}`,
+		`{"n":0,"neg":-0,"f":-0.0,"e":1e308,"small":5e-324,"exp":1.5E+10,"frac":0.1}`,
+		`{"inf":1e700}`,
+		`{"max":9223372036854775807,"min":-9223372036854775808,"over":9223372036854775808,"big":123456789012345678901234567890}`,
+		`{"a":1,"a":2,"b":3,"a":4}`,
+		"{\n  \"a\" :\t1 ,\r\n  \"b\":[ 1 , 2 ]\n}",
+		`{"invalid":"\xff\xfe"}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, opts := range [][]Option{
+			nil,
+			{WithUseNumber(true)},
+			{WithEscapeHTML(false)},
+		} {
+			var first JSONMapSlice
+			if err := first.UnmarshalJSONWithOptions(data, opts...); err != nil {
+				continue
+			}
+
+			jazon, err := first.MarshalJSONWithOptions(opts...)
+			if err != nil {
+				t.Fatalf("could not marshal %q after a successful parse: %v", data, err)
+			}
+
+			var second JSONMapSlice
+			if err := second.UnmarshalJSONWithOptions(jazon, opts...); err != nil {
+				t.Fatalf("could not parse %q, marshaled from %q: %v", jazon, data, err)
+			}
+
+			if !first.Equal(second) {
+				t.Fatalf("round trip mismatch for %q: got %q, marshaled as %q", data, second, jazon)
+			}
+
+			indented, err := first.MarshalJSONIndent("", "  ")
+			if err != nil {
+				t.Fatalf("could not marshal %q with indentation after a successful parse: %v", data, err)
+			}
+
+			var third JSONMapSlice
+			if err := third.UnmarshalJSONWithOptions(indented, opts...); err != nil {
+				t.Fatalf("could not parse %q, marshaled with indentation from %q: %v", indented, data, err)
+			}
+
+			if !first.Equal(third) {
+				t.Fatalf("round trip mismatch with indentation for %q: got %q, marshaled as %q", data, third, indented)
+			}
+		}
+	})
+}
@@ -15,8 +15,19 @@
 package jsonutils
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -124,32 +135,1686 @@ func TestJSONMapSlice(t *testing.T) {
 
 			assert.JSONEq(t, sd, string(jazon))
 		})
+
+		t.Run("should distinguish null from an empty object", func(t *testing.T) {
+			data := JSONMapSlice{{Key: "previous", Value: true}}
+			require.NoError(t, data.UnmarshalJSON([]byte(`null`)))
+			assert.Nil(t, data, "null should reset the receiver to nil")
+
+			jazon, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `null`, string(jazon))
+
+			data = JSONMapSlice{{Key: "previous", Value: true}}
+			require.NoError(t, data.UnmarshalJSON([]byte(` {} `)))
+			require.NotNil(t, data, "an empty object should yield a non-nil object")
+			assert.Empty(t, data)
+
+			jazon, err = data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `{}`, string(jazon))
+
+			t.Run("with nested values", func(t *testing.T) {
+				nested := JSONMapSlice{{Key: "a", Value: JSONMapSlice(nil)}, {Key: "b", Value: JSONMapSlice{}}}
+				jazon, err := nested.MarshalJSON()
+				require.NoError(t, err)
+				assert.Equal(t, `{"a":null,"b":{}}`, string(jazon))
+
+				var decoded JSONMapSlice
+				require.NoError(t, decoded.UnmarshalJSON(jazon))
+				assert.Equal(t, JSONMapSlice{{Key: "a", Value: nil}, {Key: "b", Value: JSONMapSlice{}}}, decoded,
+					"a nested null is decoded as an untyped nil",
+				)
+				assert.False(t, nested.Equal(decoded), "a nil object is not an untyped nil")
+
+				again, err := decoded.MarshalJSON()
+				require.NoError(t, err)
+				assert.Equal(t, string(jazon), string(again))
+			})
+		})
+	})
+
+	t.Run("should keep the order of keys", func(t *testing.T) {
+		const sd = `{"a":1,"b":2,"c":3,"d":4}`
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+		jazon, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		require.Equal(t, sd, string(jazon)) // specifically check the same order, not JSONEq()
+
+		t.Run("should Read/Write JSON using CustomJSON", func(t *testing.T) {
+			var obj interface{}
+			require.NoError(t, FromDynamicJSON(data, &obj))
+
+			asMap, ok := obj.(map[string]interface{})
+			require.True(t, ok)
+			assert.Len(t, asMap, 4) // 3 fields in struct
+
+			var target JSONMapSlice
+			require.NoError(t, FromDynamicJSON(obj, &target))
+			// the order of keys may be altered, since the intermediary representation is a map[string]interface{}
+		})
+	})
+
+	t.Run("should unmarshal pretty-printed input with extra whitespace", func(t *testing.T) {
+		const sd = "{\n  \"a\"  :   1 ,\n\t\"b\"\n:\n{ \"c\" :\t[ true ,\n \"x:y\" , { \"d\" :  null } ] } ,\r\n  \"e:\" :\"f\"  ,\n  \"g\":\n\n[\n]\n}\n"
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "a", Value: int64(1)},
+			{Key: "b", Value: JSONMapSlice{
+				{Key: "c", Value: []any{true, "x:y", JSONMapSlice{{Key: "d", Value: nil}}}},
+			}},
+			{Key: "e:", Value: "f"},
+			{Key: "g", Value: []any{}},
+		}, data)
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":{"c":[true,"x:y",{"d":null}]},"e:":"f","g":[]}`, string(jazon))
+	})
+
+	t.Run("should unmarshal input with a byte order mark or leading whitespace", func(t *testing.T) {
+		const bom = "\xEF\xBB\xBF"
+		expected := JSONMapSlice{{Key: "a", Value: int64(1)}, {Key: "b", Value: []any{"\uFEFF"}}}
+
+		for _, sd := range []string{
+			bom + `{"a":1,"b":["\uFEFF"]}`,
+			bom + " \n\t\r\n" + `{"a":1,"b":["` + bom + `"]}`,
+			"  \n\n\t" + `{"a":1,"b":["\ufeff"]}` + "\n",
+		} {
+			var data JSONMapSlice
+			require.NoErrorf(t, data.UnmarshalJSON([]byte(sd)), "expected %q to be accepted", sd)
+			assert.Equal(t, expected, data)
+
+			value, err := Unmarshal([]byte(sd))
+			require.NoError(t, err)
+			assert.Equal(t, expected, value)
+
+			for _, r := range []io.Reader{
+				strings.NewReader(sd),
+				iotest.OneByteReader(strings.NewReader(sd)),
+				iotest.DataErrReader(strings.NewReader(sd)),
+			} {
+				data, err := UnmarshalReader(r)
+				require.NoError(t, err)
+				assert.Equal(t, expected, data)
+			}
+		}
+
+		t.Run("should unmarshal empty input or null with a byte order mark", func(t *testing.T) {
+			for _, sd := range []string{bom, bom + "  ", bom + "null"} {
+				data := JSONMapSlice{{Key: "x", Value: 1}}
+				require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+				assert.Nil(t, data)
+			}
+		})
+
+		t.Run("should reject misplaced or truncated byte order marks", func(t *testing.T) {
+			for _, sd := range []string{
+				bom + bom + `{}`,
+				" " + bom + `{}`,
+				`{}` + bom,
+				"\xEF\xBB" + `{}`,
+				"\xEF",
+			} {
+				var data JSONMapSlice
+				require.ErrorIsf(t, data.UnmarshalJSON([]byte(sd)), ErrJSON, "expected %q to be rejected", sd)
+
+				_, err := UnmarshalReader(iotest.OneByteReader(strings.NewReader(sd)))
+				require.ErrorIs(t, err, ErrJSON)
+			}
+		})
+
+		t.Run("should return read errors while looking for a byte order mark", func(t *testing.T) {
+			_, err := UnmarshalReader(io.MultiReader(strings.NewReader(bom), iotest.ErrReader(errTestWriter)))
+			require.ErrorIs(t, err, errTestWriter)
+
+			_, err = UnmarshalReader(iotest.ErrReader(errTestWriter))
+			require.ErrorIs(t, err, errTestWriter)
+		})
+	})
+
+	t.Run("should unmarshal numbers", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Input    string
+			Expected any
+			Output   string
+		}{
+			{Title: "with integer", Input: `1`, Expected: int64(1), Output: `1`},
+			{Title: "with negative integer", Input: `-12`, Expected: int64(-12), Output: `-12`},
+			{Title: "with integral float", Input: `1.0`, Expected: float64(1), Output: `1`},
+			{Title: "with float", Input: `1.5`, Expected: float64(1.5), Output: `1.5`},
+			{Title: "with exponent", Input: `1e3`, Expected: float64(1000), Output: `1000`},
+			{Title: "with integer beyond 2^53", Input: `9007199254740993`, Expected: int64(9007199254740993), Output: `9007199254740993`},
+			{Title: "with max int64", Input: `9223372036854775807`, Expected: int64(9223372036854775807), Output: `9223372036854775807`},
+			{Title: "with integer beyond int64", Input: `9223372036854775808`, Expected: float64(9223372036854775808), Output: `9223372036854776000`},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				var data JSONMapSlice
+				require.NoError(t, json.Unmarshal([]byte(`{"a":`+fixture.Input+`}`), &data))
+
+				v, ok := data.Get("a")
+				require.True(t, ok)
+				require.IsType(t, fixture.Expected, v)
+				require.Equal(t, fixture.Expected, v)
+
+				jazon, err := json.Marshal(data)
+				require.NoError(t, err)
+				assert.Equal(t, `{"a":`+fixture.Output+`}`, string(jazon))
+			})
+		}
+
+		t.Run("with numbers out of range", func(t *testing.T) {
+			for _, sd := range []string{`{"a":1e700}`, `{"a":[-1e309]}`, `{"a":{"b":` + strings.Repeat("9", 400) + `}}`} {
+				var data JSONMapSlice
+				err := data.UnmarshalJSON([]byte(sd))
+				require.ErrorIsf(t, err, ErrJSON, "expected an error for %q", sd)
+
+				var parseErr *ParseError
+				require.ErrorAs(t, err, &parseErr)
+				assert.Equal(t, "a number in the range of float64", parseErr.Expected)
+
+				t.Run("should preserve numbers out of range with option WithUseNumber", func(t *testing.T) {
+					require.NoError(t, data.UnmarshalJSONWithOptions([]byte(sd), WithUseNumber(true)))
+				})
+			}
+		})
+
+		t.Run("with number overflow policies", func(t *testing.T) {
+			hugeInteger := "-" + strings.Repeat("9", 40)
+			sd := `{"int":` + hugeInteger + `,"exp":[1e400],"ok":[9223372036854775807,1.5]}`
+
+			t.Run("should convert huge integers to float64 by default", func(t *testing.T) {
+				for _, opts := range [][]Option{nil, {WithNumberOverflowPolicy(NumberOverflowFloat)}} {
+					data, err := ReadJSONMapSlice([]byte(`{"int":`+hugeInteger+`}`), opts...)
+					require.NoError(t, err)
+					assert.Equal(t, JSONMapSlice{{Key: "int", Value: float64(-1e40)}}, data)
+
+					_, err = ReadJSONMapSlice([]byte(sd), opts...)
+					require.ErrorIs(t, err, ErrJSON)
+
+					var parseErr *ParseError
+					require.ErrorAs(t, err, &parseErr)
+					assert.Equal(t, "a number in the range of float64", parseErr.Expected)
+					assert.Equal(t, "number 1e400", parseErr.Actual)
+				}
+			})
+
+			t.Run("should preserve numbers which overflow with NumberOverflowKeep", func(t *testing.T) {
+				data, err := ReadJSONMapSlice([]byte(sd), WithNumberOverflowPolicy(NumberOverflowKeep))
+				require.NoError(t, err)
+				assert.Equal(t, JSONMapSlice{
+					{Key: "int", Value: json.Number(hugeInteger)},
+					{Key: "exp", Value: []any{json.Number("1e400")}},
+					{Key: "ok", Value: []any{int64(math.MaxInt64), 1.5}},
+				}, data)
+
+				jazon, err := data.MarshalJSON()
+				require.NoError(t, err)
+				assert.Equal(t, sd, string(jazon), "preserved numbers should be rendered verbatim")
+			})
+
+			t.Run("should reject numbers which overflow with NumberOverflowError", func(t *testing.T) {
+				for _, fixture := range []struct {
+					Input    string
+					Expected string
+				}{
+					{Input: `{"int":` + hugeInteger + `}`, Expected: "an integer in the range of int64"},
+					{Input: `{"a":[9223372036854775808]}`, Expected: "an integer in the range of int64"},
+					{Input: `{"exp":1E400}`, Expected: "a number in the range of float64"},
+				} {
+					_, err := ReadJSONMapSlice([]byte(fixture.Input), WithNumberOverflowPolicy(NumberOverflowError))
+					require.ErrorIs(t, err, ErrJSON)
+
+					var parseErr *ParseError
+					require.ErrorAs(t, err, &parseErr)
+					assert.Equal(t, fixture.Expected, parseErr.Expected)
+				}
+
+				data, err := ReadJSONMapSlice([]byte(`{"ok":[9223372036854775807,-9223372036854775808,1e300]}`),
+					WithNumberOverflowPolicy(NumberOverflowError),
+				)
+				require.NoError(t, err)
+				assert.Equal(t, JSONMapSlice{{Key: "ok", Value: []any{int64(math.MaxInt64), int64(math.MinInt64), 1e300}}}, data)
+			})
+
+			t.Run("should preserve all numbers with WithUseNumber, regardless of the policy", func(t *testing.T) {
+				data, err := ReadJSONMapSlice([]byte(sd), WithUseNumber(true), WithNumberOverflowPolicy(NumberOverflowError))
+				require.NoError(t, err)
+				assert.Equal(t, json.Number("1.5"), data[2].Value.([]any)[1])
+			})
+
+			t.Run("should apply the policy to values of any kind", func(t *testing.T) {
+				v, err := Unmarshal([]byte(hugeInteger), WithNumberOverflowPolicy(NumberOverflowKeep))
+				require.NoError(t, err)
+				assert.Equal(t, json.Number(hugeInteger), v)
+
+				_, err = Unmarshal([]byte(hugeInteger), WithNumberOverflowPolicy(NumberOverflowError))
+				require.ErrorIs(t, err, ErrJSON)
+			})
+		})
+
+		t.Run("with nested numbers", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, json.Unmarshal([]byte(`{"a":[1,1.5,{"b":2}]}`), &data))
+
+			require.Equal(t, JSONMapSlice{
+				{Key: "a", Value: []any{int64(1), float64(1.5), JSONMapSlice{{Key: "b", Value: int64(2)}}}},
+			}, data)
+		})
+	})
+
+	t.Run("should preserve numbers with option WithUseNumber", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title string
+			Input string
+		}{
+			{Title: "with integer", Input: `1`},
+			{Title: "with integral float", Input: `1.0`},
+			{Title: "with integer beyond int64", Input: `9223372036854775808`},
+			{Title: "with big negative integer", Input: `-123456789012345678901234567890`},
+			{Title: "with high-precision decimal", Input: `3.14159265358979323846264338327950288`},
+			{Title: "with exponent", Input: `1.5E+400`},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				input := `{"a":` + fixture.Input + `,"b":[` + fixture.Input + `,{"c":` + fixture.Input + `}]}`
+
+				var data JSONMapSlice
+				require.NoError(t, data.UnmarshalJSONWithOptions([]byte(input), WithUseNumber(true)))
+
+				v, ok := data.Get("a")
+				require.True(t, ok)
+				require.Equal(t, json.Number(fixture.Input), v)
+
+				jazon, err := json.Marshal(data)
+				require.NoError(t, err)
+				assert.Equal(t, input, string(jazon))
+			})
+		}
+
+		t.Run("option disabled should convert numbers", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"a":1}`), WithUseNumber(false)))
+
+			require.Equal(t, JSONMapSlice{{Key: "a", Value: int64(1)}}, data)
+		})
+
+		t.Run("should reject invalid number literals when marshaling", func(t *testing.T) {
+			for _, literal := range []string{"abc", "-", "01", "1.", ".5", "1e", "1e+", "+1", "1 ", "0x10", "NaN"} {
+				_, err := JSONMapSlice{{Key: "n", Value: json.Number(literal)}}.MarshalJSON()
+				require.ErrorIsf(t, err, ErrJSON, "expected an error for %q", literal)
+
+				_, err = json.Marshal(json.Number(literal))
+				require.Errorf(t, err, "the standard library should reject %q too", literal)
+			}
+
+			jazon, err := JSONMapSlice{{Key: "n", Value: json.Number("")}}.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `{"n":0}`, string(jazon))
+		})
+	})
+
+	t.Run("should handle duplicate keys with option WithDuplicateKeyPolicy", func(t *testing.T) {
+		const sd = `{"a":1,"b":{"c":1,"c":2},"a":2}`
+
+		t.Run("with default policy, should keep all keys", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+			jazon, err := json.Marshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, sd, string(jazon))
+		})
+
+		for _, fixture := range []struct {
+			Title    string
+			Policy   DuplicateKeyPolicy
+			Expected string
+		}{
+			{Title: "with DuplicateKeyKeep", Policy: DuplicateKeyKeep, Expected: sd},
+			{Title: "with DuplicateKeyFirst", Policy: DuplicateKeyFirst, Expected: `{"a":1,"b":{"c":1}}`},
+			{Title: "with DuplicateKeyLast", Policy: DuplicateKeyLast, Expected: `{"a":2,"b":{"c":2}}`},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				var data JSONMapSlice
+				require.NoError(t, data.UnmarshalJSONWithOptions([]byte(sd), WithDuplicateKeyPolicy(fixture.Policy)))
+
+				jazon, err := json.Marshal(data)
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, string(jazon))
+			})
+		}
+
+		t.Run("with DuplicateKeyError", func(t *testing.T) {
+			var data JSONMapSlice
+			err := data.UnmarshalJSONWithOptions([]byte(`{"a":1,"a":2}`), WithDuplicateKeyPolicy(DuplicateKeyError))
+			require.ErrorIs(t, err, ErrJSON)
+			require.ErrorContains(t, err, `"a"`)
+
+			err = data.UnmarshalJSONWithOptions([]byte(`{"a":{"b":1,"b":2}}`), WithDuplicateKeyPolicy(DuplicateKeyError))
+			require.ErrorIs(t, err, ErrJSON)
+
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"a":{"a":1},"b":[{"a":1},{"a":2}]}`), WithDuplicateKeyPolicy(DuplicateKeyError)))
+		})
+	})
+
+	t.Run("should reject trailing content unless WithAllowTrailingContent", func(t *testing.T) {
+		t.Run("with trailing whitespace", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSON([]byte("{\"a\":1} \n\t")))
+			assert.Equal(t, JSONMapSlice{{Key: "a", Value: int64(1)}}, data)
+		})
+
+		for _, fixture := range []struct {
+			Title    string
+			Input    string
+			Expected JSONMapSlice
+		}{
+			{Title: "with trailing garbage", Input: `{"a":1}garbage`, Expected: JSONMapSlice{{Key: "a", Value: int64(1)}}},
+			{Title: "with concatenated objects", Input: `{"a":1}{"b":2}`, Expected: JSONMapSlice{{Key: "a", Value: int64(1)}}},
+			{Title: "with concatenated values", Input: `{"a":1} 2`, Expected: JSONMapSlice{{Key: "a", Value: int64(1)}}},
+			{Title: "with an extra closing brace", Input: `{"a":{"b":1}}}`, Expected: JSONMapSlice{{Key: "a", Value: JSONMapSlice{{Key: "b", Value: int64(1)}}}}},
+			{Title: "with trailing content after null", Input: `null {}`, Expected: nil},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				t.Run("should reject by default", func(t *testing.T) {
+					data := JSONMapSlice{{Key: "untouched", Value: true}}
+					err := data.UnmarshalJSON([]byte(fixture.Input))
+					require.Error(t, err)
+					assert.Equal(t, JSONMapSlice{{Key: "untouched", Value: true}}, data)
+				})
+
+				t.Run("should accept with option", func(t *testing.T) {
+					data := JSONMapSlice{{Key: "untouched", Value: true}}
+					require.NoError(t, data.UnmarshalJSONWithOptions([]byte(fixture.Input), WithAllowTrailingContent(true)))
+					assert.Equal(t, fixture.Expected, data)
+				})
+			})
+		}
+
+		t.Run("should report the trailing value", func(t *testing.T) {
+			var data JSONMapSlice
+			err := data.UnmarshalJSON([]byte(`{"a":1}{"b":2}`))
+			require.ErrorIs(t, err, ErrJSON)
+		})
+	})
+
+	t.Run("should reject trailing commas unless WithAllowTrailingCommas", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Input    string
+			Expected JSONMapSlice
+		}{
+			{Title: "in an object", Input: `{"a":1,"b":2,}`, Expected: JSONMapSlice{{Key: "a", Value: int64(1)}, {Key: "b", Value: int64(2)}}},
+			{Title: "in an array", Input: `{"a":[1,2,]}`, Expected: JSONMapSlice{{Key: "a", Value: []any{int64(1), int64(2)}}}},
+			{
+				Title:    "in nested objects and arrays",
+				Input:    `{"a":[{"b":true,},[null,],],"c":{"d":"e",},}`,
+				Expected: JSONMapSlice{{Key: "a", Value: []any{JSONMapSlice{{Key: "b", Value: true}}, []any{nil}}}, {Key: "c", Value: JSONMapSlice{{Key: "d", Value: "e"}}}},
+			},
+			{Title: "followed by white space", Input: "{\"a\":[1,\n\t],\n}", Expected: JSONMapSlice{{Key: "a", Value: []any{int64(1)}}}},
+			{Title: "after a string with commas", Input: `{"a":"x,}\",]",}`, Expected: JSONMapSlice{{Key: "a", Value: `x,}",]`}}},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				t.Run("should reject by default", func(t *testing.T) {
+					var data JSONMapSlice
+					require.ErrorIs(t, data.UnmarshalJSON([]byte(fixture.Input)), ErrJSON)
+				})
+
+				t.Run("should accept with option", func(t *testing.T) {
+					var data JSONMapSlice
+					require.NoError(t, data.UnmarshalJSONWithOptions([]byte(fixture.Input), WithAllowTrailingCommas(true)))
+					assert.Equal(t, fixture.Expected, data)
+				})
+
+				t.Run("should accept from a slow reader with option", func(t *testing.T) {
+					data, err := UnmarshalReader(iotest.OneByteReader(strings.NewReader(fixture.Input)), WithAllowTrailingCommas(true))
+					require.NoError(t, err)
+					assert.Equal(t, fixture.Expected, data)
+				})
+			})
+		}
+
+		t.Run("should accept lazy values with option", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"a":{"b":[1,],},}`), WithAllowTrailingCommas(true), WithLazyNested(true)))
+
+			value, err := data.AtPointer("/a/b")
+			require.NoError(t, err)
+			assert.Equal(t, []any{int64(1)}, value)
+		})
+
+		t.Run("should still reject other invalid commas and comments with option", func(t *testing.T) {
+			for _, input := range []string{
+				`{,}`,
+				`{"a":[,]}`,
+				`{"a":1,,}`,
+				`{"a":[1,,]}`,
+				`{"a":,}`,
+				`{"a":1,`,
+				"{\"a\":1, // comment\n}",
+			} {
+				var data JSONMapSlice
+				require.ErrorIsf(t, data.UnmarshalJSONWithOptions([]byte(input), WithAllowTrailingCommas(true)), ErrJSON,
+					"expected %q to be rejected", input,
+				)
+			}
+		})
+	})
+
+	t.Run("should preallocate large objects without retaining unused capacity", func(t *testing.T) {
+		large := makeLargeMapSlice(1000)
+		jazon, err := large.MarshalJSON()
+		require.NoError(t, err)
+
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSON(jazon))
+		assert.Equal(t, large, data)
+		assert.GreaterOrEqual(t, cap(data), len(data))
+		assert.LessOrEqual(t, cap(data), 2*len(data))
+
+		// the first keys are short, so the estimated number of keys is much too large
+		skewed := make(JSONMapSlice, 0, 40)
+		for i := 0; i < cap(skewed); i++ {
+			value := any(int64(i))
+			if i >= 20 {
+				value = strings.Repeat("x", 1000)
+			}
+			skewed = append(skewed, JSONMapItem{Key: "k" + strconv.Itoa(i), Value: value})
+		}
+		jazon, err = skewed.MarshalJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, data.UnmarshalJSON(jazon))
+		assert.Equal(t, skewed, data)
+		assert.LessOrEqual(t, cap(data), 2*len(data))
+
+		t.Run("should not overallocate keys when a large value comes last", func(t *testing.T) {
+			var input strings.Builder
+			input.WriteByte('{')
+			for i := 0; i < 2*maxInitialKeys; i++ {
+				fmt.Fprintf(&input, `"%d":0,`, i)
+			}
+			fmt.Fprintf(&input, `"large":%q}`, strings.Repeat("x", 4<<20))
+			jazon := []byte(input.String())
+
+			unsized := allocatedBytes(func() {
+				_, err := UnmarshalReader(bytes.NewReader(jazon))
+				require.NoError(t, err)
+			})
+
+			for _, opts := range [][]Option{nil, {WithMaxKeys(100)}} {
+				var data JSONMapSlice
+				sized := allocatedBytes(func() {
+					require.NoError(t, data.UnmarshalJSONWithOptions(jazon, opts...))
+				})
+				require.Len(t, data, 2*maxInitialKeys+1)
+				assert.LessOrEqual(t, sized, unsized+uint64(len(jazon))/4,
+					"preallocating keys should only cost a small fraction of the size of the input",
+				)
+			}
+		})
+	})
+
+	t.Run("should reuse the backing array with UnmarshalInto", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalInto([]byte(`{"a":1,"b":{"x":true},"c":"3","d":[4],"e":null}`)))
+		require.Len(t, data, 5)
+		backing := &data[:1][0]
+
+		t.Run("stale entries from a previous parse should not leak through", func(t *testing.T) {
+			require.NoError(t, data.UnmarshalInto([]byte(`{"z":"new","a":2}`)))
+			assert.Equal(t, JSONMapSlice{{Key: "z", Value: "new"}, {Key: "a", Value: int64(2)}}, data)
+			assert.Same(t, backing, &data[0])
+
+			for _, item := range data[len(data):cap(data)] {
+				assert.Equal(t, JSONMapItem{}, item)
+			}
+
+			jazon, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `{"z":"new","a":2}`, string(jazon))
+		})
+
+		t.Run("should apply options", func(t *testing.T) {
+			require.NoError(t, data.UnmarshalInto([]byte(`{"a":1,"a":2}`), WithDuplicateKeyPolicy(DuplicateKeyLast)))
+			assert.Equal(t, JSONMapSlice{{Key: "a", Value: int64(2)}}, data)
+			assert.Same(t, backing, &data[0])
+		})
+
+		t.Run("should grow beyond the capacity of the receiver", func(t *testing.T) {
+			large := makeLargeMapSlice(100)
+			jazon, err := large.MarshalJSON()
+			require.NoError(t, err)
+
+			reused := make(JSONMapSlice, 0, 2)
+			require.NoError(t, reused.UnmarshalInto(jazon))
+			assert.Equal(t, large, reused)
+		})
+
+		t.Run("should empty the receiver on error", func(t *testing.T) {
+			require.NoError(t, data.UnmarshalInto([]byte(`{"a":1,"b":2,"c":3}`)))
+			capacity := cap(data)
+
+			err := data.UnmarshalInto([]byte(`{"x":1,"y":2,`))
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Empty(t, data)
+			assert.Equal(t, capacity, cap(data))
+			for _, item := range data[:cap(data)] {
+				assert.Equal(t, JSONMapItem{}, item)
+			}
+		})
+
+		t.Run("should unmarshal null as nil", func(t *testing.T) {
+			require.NoError(t, data.UnmarshalInto([]byte(`{"a":1}`)))
+			require.NoError(t, data.UnmarshalInto([]byte(`null`)))
+			assert.Nil(t, data)
+		})
+	})
+
+	t.Run("should share identical keys with option WithInternKeys", func(t *testing.T) {
+		const input = `{"records":[{"name":"a","id":1},{"name":"b","id":2}],"name":"c"}`
+		keyData := func(data JSONMapSlice, pointer string, i int) *byte {
+			v, err := data.AtPointer(pointer)
+			require.NoError(t, err)
+
+			return unsafe.StringData(v.(JSONMapSlice)[i].Key)
+		}
+
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSONWithOptions([]byte(input), WithInternKeys(true)))
+		assert.Equal(t, `{"records":[{"name":"a","id":1},{"name":"b","id":2}],"name":"c"}`, data.String())
+		assert.Same(t, keyData(data, "/records/0", 0), keyData(data, "/records/1", 0))
+		assert.Same(t, keyData(data, "/records/0", 0), keyData(data, "", 1))
+		assert.Same(t, keyData(data, "/records/0", 1), keyData(data, "/records/1", 1))
+
+		var notInterned JSONMapSlice
+		require.NoError(t, notInterned.UnmarshalJSON([]byte(input)))
+		assert.NotSame(t, keyData(notInterned, "/records/0", 0), keyData(notInterned, "/records/1", 0))
+	})
+
+	t.Run("should limit nesting depth with option WithMaxDepth", func(t *testing.T) {
+		nested := func(depth int) string {
+			// an object holding arrays, nested to the given depth
+			return `{"a":` + strings.Repeat("[", depth-1) + strings.Repeat("]", depth-1) + `}`
+		}
+
+		t.Run("should accept input at the default limit", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSON([]byte(nested(DefaultMaxDepth))))
+		})
+
+		t.Run("should reject input past the default limit", func(t *testing.T) {
+			var data JSONMapSlice
+			err := data.UnmarshalJSON([]byte(nested(DefaultMaxDepth + 1)))
+			require.Error(t, err)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, int64(len(`{"a":`)+DefaultMaxDepth), parseErr.Offset)
+		})
+
+		t.Run("should reject pathological input without exhausting the stack", func(t *testing.T) {
+			var data JSONMapSlice
+			err := data.UnmarshalJSON([]byte(`{"a":` + strings.Repeat("[", 1_000_000)))
+			require.ErrorIs(t, err, ErrJSON)
+
+			_, err = Unmarshal([]byte(strings.Repeat(`{"a":`, 1_000_000)))
+			require.ErrorIs(t, err, ErrJSON)
+		})
+
+		t.Run("should apply a custom limit", func(t *testing.T) {
+			const sd = `{"a":{"b":[{"c":1}]}}`
+
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(sd), WithMaxDepth(4)))
+
+			err := data.UnmarshalJSONWithOptions([]byte(sd), WithMaxDepth(3))
+			require.ErrorIs(t, err, ErrJSON)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, "at most 3 levels of nesting", parseErr.Expected)
+			assert.Equal(t, "'{'", parseErr.Actual)
+			assert.Equal(t, int64(len(`{"a":{"b":[{`)), parseErr.Offset)
+
+			err = data.UnmarshalJSONWithOptions([]byte(`{}`), WithMaxDepth(1))
+			require.NoError(t, err)
+
+			_, err = Unmarshal([]byte(`[[1]]`), WithMaxDepth(1))
+			require.ErrorIs(t, err, ErrJSON)
+		})
+
+		t.Run("should disable the limit, but retain the limit of the standard library", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(nested(DefaultMaxDepth)), WithMaxDepth(0)))
+			require.ErrorIs(t, data.UnmarshalJSONWithOptions([]byte(nested(DefaultMaxDepth+1)), WithMaxDepth(0)), ErrJSON)
+		})
+	})
+
+	t.Run("should limit the number of keys with option WithMaxKeys", func(t *testing.T) {
+		const sd = `{"a":1,"b":{"c":2,"d":3,"e":4},"a":5}`
+
+		t.Run("should apply no limit by default", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(sd), WithMaxKeys(3)))
+		})
+
+		t.Run("should reject an object with too many keys", func(t *testing.T) {
+			var data JSONMapSlice
+			err := data.UnmarshalJSONWithOptions([]byte(sd), WithMaxKeys(2))
+			require.ErrorIs(t, err, ErrJSON)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, "at most 2 keys in a JSON object", parseErr.Expected)
+			assert.Equal(t, `string "e"`, parseErr.Actual)
+			assert.Equal(t, int64(len(`{"a":1,"b":{"c":2,"d":3,"e"`)), parseErr.Offset)
+		})
+
+		t.Run("should count duplicate keys", func(t *testing.T) {
+			var data JSONMapSlice
+			err := data.UnmarshalJSONWithOptions([]byte(`{"a":1,"a":2,"a":3}`),
+				WithMaxKeys(2), WithDuplicateKeyPolicy(DuplicateKeyLast),
+			)
+			require.ErrorIs(t, err, ErrJSON)
+		})
+
+		t.Run("should apply to objects nested in arrays", func(t *testing.T) {
+			_, err := Unmarshal([]byte(`[{"a":1,"b":2}]`), WithMaxKeys(1))
+			require.ErrorIs(t, err, ErrJSON)
+		})
+
+		t.Run("should stop decoding an endless stream of keys", func(t *testing.T) {
+			_, err := UnmarshalReader(&endlessObjectReader{}, WithMaxKeys(1000))
+			require.ErrorIs(t, err, ErrJSON)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, "at most 1000 keys in a JSON object", parseErr.Expected)
+		})
+	})
+
+	t.Run("should limit the number of values with option WithMaxTotalNodes", func(t *testing.T) {
+		const sd = `{"a":[1,2,{"b":null}],"c":"x"}` // 7 values, including the top-level object
+
+		t.Run("should accept a document at the limit", func(t *testing.T) {
+			var data JSONMapSlice
+			require.NoError(t, data.UnmarshalJSONWithOptions([]byte(sd), WithMaxTotalNodes(7)))
+
+			_, err := Unmarshal([]byte(`[1,2]`), WithMaxTotalNodes(3))
+			require.NoError(t, err)
+		})
+
+		t.Run("should reject a document with too many values", func(t *testing.T) {
+			var data JSONMapSlice
+			err := data.UnmarshalJSONWithOptions([]byte(sd), WithMaxTotalNodes(6))
+			require.ErrorIs(t, err, ErrJSON)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, "at most 6 values in the JSON document", parseErr.Expected)
+			assert.Equal(t, `string "x"`, parseErr.Actual)
+
+			_, err = Unmarshal([]byte(`[1,2]`), WithMaxTotalNodes(2))
+			require.ErrorIs(t, err, ErrJSON)
+
+			err = data.UnmarshalJSONWithOptions([]byte(`{}`), WithMaxTotalNodes(1))
+			require.NoError(t, err)
+		})
+
+		t.Run("should stop decoding an endless stream of values", func(t *testing.T) {
+			_, err := UnmarshalReader(&endlessObjectReader{}, WithMaxTotalNodes(1000))
+			require.ErrorIs(t, err, ErrJSON)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, "at most 1000 values in the JSON document", parseErr.Expected)
+		})
+	})
+
+	t.Run("should escape keys and string values", func(t *testing.T) {
+		for _, str := range []string{
+			`he said "hi"`,
+			`back\slash`,
+			"tab\tand\nnewline\r",
+			"control \x00\x01\x1f\b\f\x7f",
+			"emoji 😀 and ünicode 日本語",
+			"<script>alert('&')</script>",
+			"line\u2028and\u2029paragraph separators",
+			"invalid \xff\xfe utf8",
+			"",
+		} {
+			t.Run(str, func(t *testing.T) {
+				data := JSONMapSlice{
+					{Key: str, Value: str},
+					{Key: "nested", Value: []any{str, JSONMapSlice{{Key: str, Value: str}}}},
+				}
+
+				expected, err := json.Marshal(map[string]any{
+					"k": str,
+				})
+				require.NoError(t, err)
+
+				key, err := json.Marshal(str)
+				require.NoError(t, err)
+				require.Equal(t, `{"k":`+string(key)+`}`, string(expected))
+
+				jazon, err := data.MarshalJSON()
+				require.NoError(t, err)
+				assert.Equal(t, `{`+string(key)+`:`+string(key)+`,"nested":[`+string(key)+`,{`+string(key)+`:`+string(key)+`}]}`, string(jazon))
+				require.True(t, json.Valid(jazon))
+
+				t.Run("should unmarshal escaped strings back", func(t *testing.T) {
+					if !utf8.ValidString(str) {
+						t.Skip("invalid UTF-8 is replaced")
+					}
+
+					var target JSONMapSlice
+					require.NoError(t, json.Unmarshal(jazon, &target))
+					require.Equal(t, str, target[0].Key)
+					require.Equal(t, str, target[0].Value)
+				})
+			})
+		}
+	})
+
+	t.Run("should escape HTML with option WithEscapeHTML", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "<key>", Value: "<script>alert('a&b')</script>"},
+			{Key: "nested", Value: []any{"<b>", map[string]any{"<i>": "&"}}},
+		}
+
+		t.Run("should escape HTML by default", func(t *testing.T) {
+			const expected = `{"\u003ckey\u003e":"\u003cscript\u003ealert('a\u0026b')\u003c/script\u003e","nested":["\u003cb\u003e",{"\u003ci\u003e":"\u0026"}]}`
+
+			jazon, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(jazon))
+
+			jazon, err = data.MarshalJSONWithOptions(WithEscapeHTML(true))
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(jazon))
+		})
+
+		t.Run("should not escape HTML when disabled", func(t *testing.T) {
+			jazon, err := data.MarshalJSONWithOptions(WithEscapeHTML(false))
+			require.NoError(t, err)
+			assert.Equal(t, `{"<key>":"<script>alert('a&b')</script>","nested":["<b>",{"<i>":"&"}]}`, string(jazon))
+		})
+
+		t.Run("should still escape other characters when disabled", func(t *testing.T) {
+			jazon, err := JSONMapSlice{{Key: "a\"\n", Value: []any{"\t\u2028", make(chan int)}}}.MarshalJSONWithOptions(WithEscapeHTML(false))
+			require.Error(t, err)
+			assert.Nil(t, jazon)
+
+			jazon, err = JSONMapSlice{{Key: "a\"\n", Value: []any{"\t\u2028"}}}.MarshalJSONWithOptions(WithEscapeHTML(false))
+			require.NoError(t, err)
+			assert.Equal(t, `{"a\"\n":["\t\u2028"]}`, string(jazon))
+		})
+	})
+
+	t.Run("should omit null values with option WithOmitNull", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "a", Value: nil},
+			{Key: "b", Value: 1},
+			{Key: "c", Value: nil},
+			{Key: "d", Value: JSONMapSlice{{Key: "e", Value: nil}, {Key: "f", Value: []any{nil, JSONMapSlice{{Key: "g", Value: nil}}}}}},
+			{Key: "h", Value: JSONMapSlice(nil)},
+			{Key: "i", Value: (*int)(nil)},
+			{Key: "j", Value: nil},
+		}
+
+		t.Run("should render null values by default", func(t *testing.T) {
+			jazon, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `{"a":null,"b":1,"c":null,"d":{"e":null,"f":[null,{"g":null}]},"h":null,"i":null,"j":null}`, string(jazon))
+
+			jazon, err = data.MarshalJSONWithOptions(WithOmitNull(false))
+			require.NoError(t, err)
+			assert.Equal(t, `{"a":null,"b":1,"c":null,"d":{"e":null,"f":[null,{"g":null}]},"h":null,"i":null,"j":null}`, string(jazon))
+		})
+
+		t.Run("should omit null values when enabled", func(t *testing.T) {
+			jazon, err := data.MarshalJSONWithOptions(WithOmitNull(true))
+			require.NoError(t, err)
+			assert.Equal(t, `{"b":1,"d":{"f":[null,{}]},"h":null,"i":null}`, string(jazon))
+		})
+
+		t.Run("should omit null values with indentation and comments", func(t *testing.T) {
+			withComments := JSONMapSlice{
+				{Key: "a", Value: 1, Comment: "kept"},
+				{Key: "b", Value: nil, Comment: "omitted"},
+				{Key: "c", Value: 2},
+				{Key: "d", Value: nil},
+			}
+
+			jazon, err := withComments.MarshalJSONIndentWithOptions("", "  ", WithOmitNull(true), WithComments(true))
+			require.NoError(t, err)
+			assert.Equal(t, "{\n  \"a\": 1, // kept\n  \"c\": 2\n}", string(jazon))
+		})
+
+		t.Run("should render an object with only null values as empty", func(t *testing.T) {
+			for _, obj := range []JSONMapSlice{{{Key: "a", Value: nil}}, {}} {
+				jazon, err := obj.MarshalJSONWithOptions(WithOmitNull(true))
+				require.NoError(t, err)
+				assert.Equal(t, `{}`, string(jazon))
+
+				jazon, err = obj.MarshalJSONIndentWithOptions("", "  ", WithOmitNull(true))
+				require.NoError(t, err)
+				assert.Equal(t, `{}`, string(jazon))
+			}
+		})
+	})
+
+	t.Run("should format whole floats with option WithFloatTrailingZero", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "one", Value: 1.0},
+			{Key: "decimal", Value: 1.5},
+			{Key: "hundred", Value: float64(100)},
+			{Key: "int", Value: int64(100)},
+			{Key: "number", Value: json.Number("2")},
+			{Key: "large", Value: 1e21},
+			{Key: "negative", Value: -3.0},
+			{Key: "nested", Value: []any{0.0, JSONMapSlice{{Key: "a", Value: 7.0}}}},
+		}
+
+		t.Run("should render whole floats as integers by default", func(t *testing.T) {
+			jazon, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t,
+				`{"one":1,"decimal":1.5,"hundred":100,"int":100,"number":2,"large":1e+21,"negative":-3,"nested":[0,{"a":7}]}`,
+				string(jazon),
+			)
+		})
+
+		t.Run("should render whole floats with a trailing zero when enabled", func(t *testing.T) {
+			jazon, err := data.MarshalJSONWithOptions(WithFloatTrailingZero(true))
+			require.NoError(t, err)
+			assert.Equal(t,
+				`{"one":1.0,"decimal":1.5,"hundred":100.0,"int":100,"number":2,"large":1e+21,"negative":-3.0,"nested":[0.0,{"a":7.0}]}`,
+				string(jazon),
+			)
+		})
+
+		t.Run("should round-trip floats", func(t *testing.T) {
+			jazon, err := data.MarshalJSONWithOptions(WithFloatTrailingZero(true))
+			require.NoError(t, err)
+
+			var decoded JSONMapSlice
+			require.NoError(t, decoded.UnmarshalJSON(jazon))
+
+			hundred, ok := decoded.Get("hundred")
+			require.True(t, ok)
+			assert.Equal(t, float64(100), hundred)
+
+			integer, ok := decoded.Get("int")
+			require.True(t, ok)
+			assert.Equal(t, int64(100), integer)
+		})
+
+		t.Run("should still reject non-finite floats", func(t *testing.T) {
+			_, err := JSONMapSlice{{Key: "a", Value: math.Inf(1)}}.MarshalJSONWithOptions(WithFloatTrailingZero(true))
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("should format floats as integers with option WithFloatAsInteger", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "one", Value: 1.0},
+			{Key: "decimal", Value: 1.5},
+			{Key: "almost", Value: 4.0000000000001},
+			{Key: "negativeZero", Value: math.Copysign(0, -1)},
+			{Key: "maxSafe", Value: maxSafeInteger},
+			{Key: "minSafe", Value: -maxSafeInteger},
+			{Key: "unsafe", Value: maxSafeInteger + 1},
+			{Key: "large", Value: 1e21},
+			{Key: "nested", Value: []any{2.0, JSONMapSlice{{Key: "a", Value: 7.0}}}},
+		}
+
+		t.Run("should render JSON integers as integers when enabled", func(t *testing.T) {
+			jazon, err := data.MarshalJSONWithOptions(WithFloatAsInteger(true))
+			require.NoError(t, err)
+			assert.Equal(t,
+				`{"one":1,"decimal":1.5,"almost":4.0000000000001,"negativeZero":0,"maxSafe":9007199254740991,"minSafe":-9007199254740991,`+
+					`"unsafe":9007199254740992,"large":1e+21,"nested":[2,{"a":7}]}`,
+				string(jazon),
+			)
+		})
+
+		t.Run("should take precedence over WithFloatTrailingZero", func(t *testing.T) {
+			jazon, err := data.MarshalJSONWithOptions(WithFloatAsInteger(true), WithFloatTrailingZero(true))
+			require.NoError(t, err)
+			assert.Equal(t,
+				`{"one":1,"decimal":1.5,"almost":4.0000000000001,"negativeZero":0,"maxSafe":9007199254740991,"minSafe":-9007199254740991,`+
+					`"unsafe":9007199254740992.0,"large":1e+21,"nested":[2,{"a":7}]}`,
+				string(jazon),
+			)
+		})
+
+		t.Run("should not round values which are not whole numbers", func(t *testing.T) {
+			for _, fixture := range []struct {
+				Value    float64
+				Expected string
+			}{
+				{Value: 3.0000000000001, Expected: `3.0000000000001`},
+				{Value: 2.9999999999999, Expected: `2.9999999999999`},
+				{Value: 0.5, Expected: `0.5`},
+				{Value: -0.5, Expected: `-0.5`},
+				{Value: 1e15 + 0.5, Expected: `1000000000000000.5`},
+				{Value: -1e15 - 0.5, Expected: `-1000000000000000.5`},
+				{Value: 1<<51 + 0.5, Expected: `2251799813685248.5`},
+			} {
+				jazon, err := JSONMapSlice{{Key: "a", Value: fixture.Value}}.MarshalJSONWithOptions(WithFloatAsInteger(true))
+				require.NoError(t, err)
+				assert.Equal(t, `{"a":`+fixture.Expected+`}`, string(jazon))
+			}
+		})
+
+		t.Run("should render integers only within the range of safe integers", func(t *testing.T) {
+			for _, fixture := range []struct {
+				Value    float64
+				Expected string
+			}{
+				{Value: maxSafeInteger, Expected: `9007199254740991`},
+				{Value: -maxSafeInteger, Expected: `-9007199254740991`},
+				{Value: maxSafeInteger + 1, Expected: `9007199254740992.0`},
+				{Value: -maxSafeInteger - 1, Expected: `-9007199254740992.0`},
+			} {
+				jazon, err := JSONMapSlice{{Key: "a", Value: fixture.Value}}.MarshalJSONWithOptions(
+					WithFloatAsInteger(true), WithFloatTrailingZero(true),
+				)
+				require.NoError(t, err)
+				assert.Equal(t, `{"a":`+fixture.Expected+`}`, string(jazon))
+			}
+		})
+
+		t.Run("should render floats as usual by default", func(t *testing.T) {
+			jazon, err := JSONMapSlice{{Key: "almost", Value: 4.0000000000001}}.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `{"almost":4.0000000000001}`, string(jazon))
+		})
+
+		t.Run("should still reject non-finite floats", func(t *testing.T) {
+			for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+				_, err := JSONMapSlice{{Key: "a", Value: f}}.MarshalJSONWithOptions(WithFloatAsInteger(true))
+				require.Error(t, err)
+			}
+		})
+	})
+
+	t.Run("should marshal MapSlice with indentation", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title string
+			Input string
+		}{
+			{Title: "with object", Input: `{"1":"the int key value","name":"a string value","y":"some value"}`},
+			{Title: "with empty object", Input: `{}`},
+			{Title: "with empty array", Input: `{"a":[]}`},
+			{Title: "with empty nested object", Input: `{"a":{},"b":[{}]}`},
+			{Title: "with null", Input: `null`},
+			{
+				Title: "with deeply nested mixtures",
+				Input: `{"a":{"b":[1,[2,[]],{"c":{"d":[{},{"e":null,"f":[true,"x",10.35]}]}}],"g":{}},"h":[[],[[]]]}`,
+			},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				var data JSONMapSlice
+				require.NoError(t, json.Unmarshal([]byte(fixture.Input), &data))
+
+				compact, err := data.MarshalJSON()
+				require.NoError(t, err)
+
+				for _, indent := range []struct{ prefix, indent string }{
+					{"", "  "},
+					{"", "\t"},
+					{">", "  "},
+					{"", ""},
+				} {
+					var expected bytes.Buffer
+					require.NoError(t, json.Indent(&expected, compact, indent.prefix, indent.indent))
+
+					jazon, err := data.MarshalJSONIndent(indent.prefix, indent.indent)
+					require.NoError(t, err)
+					assert.Equal(t, expected.String(), string(jazon))
+				}
+			})
+		}
+
+		t.Run("with opaque values", func(t *testing.T) {
+			data := JSONMapSlice{
+				{Key: "a", Value: map[string]any{"b": []int{1, 2}, "c": map[string]any{}}},
+				{Key: "d", Value: []string{"x", "y"}},
+				{Key: "e", Value: []JSONMapSlice{{{Key: "f", Value: 1}}}},
+			}
+
+			compact, err := data.MarshalJSON()
+			require.NoError(t, err)
+
+			var expected bytes.Buffer
+			require.NoError(t, json.Indent(&expected, compact, "", "  "))
+
+			jazon, err := data.MarshalJSONIndent("", "  ")
+			require.NoError(t, err)
+			assert.Equal(t, expected.String(), string(jazon))
+		})
+
+		t.Run("with error", func(t *testing.T) {
+			data := JSONMapSlice{
+				{Key: "a", Value: []any{make(chan int)}},
+			}
+
+			_, err := data.MarshalJSONIndent("", "  ")
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("should marshal struct and map values", func(t *testing.T) {
+		inner := JSONMapSlice{{Key: "z", Value: 1}, {Key: "y", Value: 2}}
+		data := JSONMapSlice{
+			{Key: "struct", Value: AggregationObject{Count: 3, SharedCounters: SharedCounters{Counter1: 1}}},
+			{Key: "map", Value: map[string]any{"d": inner, "c": []any{inner}}},
+			{Key: "pointer", Value: &inner},
+			{Key: "nested", Value: []any{map[string]int{"b": 2, "a": 1}, &SharedCounters{Counter2: 2}}},
+		}
+
+		const expected = `{"struct":{"counter1":1,"count":3},"map":{"c":[{"z":1,"y":2}],"d":{"z":1,"y":2}},` +
+			`"pointer":{"z":1,"y":2},"nested":[{"a":1,"b":2},{"counter2:":2}]}`
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(jazon))
+
+		t.Run("should indent struct and map values", func(t *testing.T) {
+			jazon, err := data.MarshalJSONIndent("", "  ")
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, json.Indent(&buf, []byte(expected), "", "  "))
+			assert.Equal(t, buf.String(), string(jazon))
+		})
+	})
+
+	t.Run("should marshal raw JSON messages verbatim", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "object", Value: json.RawMessage(`{"z": 1,  "a": {"b":"<x>"}}`)},
+			{Key: "array", Value: json.RawMessage(`[1, 2,"\u00e9"]`)},
+			{Key: "nested", Value: []any{json.RawMessage(`true`), JSONMapSlice{{Key: "c", Value: json.RawMessage(`"s"`)}}}},
+			{Key: "nil", Value: json.RawMessage(nil)},
+		}
+
+		t.Run("should preserve the formatting of fragments", func(t *testing.T) {
+			jazon, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t,
+				`{"object":{"z": 1,  "a": {"b":"<x>"}},"array":[1, 2,"\u00e9"],"nested":[true,{"c":"s"}],"nil":null}`,
+				string(jazon),
+			)
+			assert.True(t, json.Valid(jazon))
+		})
+
+		t.Run("should indent fragments", func(t *testing.T) {
+			jazon, err := data.MarshalJSONIndent("", "  ")
+			require.NoError(t, err)
+
+			compact, err := data.MarshalJSON()
+			require.NoError(t, err)
+
+			var expected bytes.Buffer
+			require.NoError(t, json.Indent(&expected, compact, "", "  "))
+			assert.Equal(t, expected.String(), string(jazon))
+		})
+
+		t.Run("should reject invalid fragments", func(t *testing.T) {
+			for _, raw := range []string{``, `{"a":`, `[1,]`, `1 2`, `nul`} {
+				invalid := JSONMapSlice{{Key: "a", Value: json.RawMessage(raw)}}
+				_, err := invalid.MarshalJSON()
+				require.ErrorIsf(t, err, ErrJSON, "expected an error for %q", raw)
+
+				_, err = invalid.MarshalJSONIndent("", "  ")
+				require.ErrorIsf(t, err, ErrJSON, "expected an error for %q", raw)
+			}
+		})
 	})
 
-	t.Run("should keep the order of keys", func(t *testing.T) {
-		const sd = `{"a":1,"b":2,"c":3,"d":4}`
+	t.Run("should render comments with option WithComments", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "openapi", Value: "3.1.0", Comment: "spec version"},
+			{Key: "info", Value: JSONMapSlice{
+				{Key: "title", Value: "API"},
+				{Key: "x-internal", Value: true, Comment: "tooling\nmetadata"},
+			}, Comment: "about"},
+			{Key: "tags", Value: []any{"a"}},
+			{Key: "paths", Value: JSONMapSlice{}, Comment: "last"},
+		}
+
+		t.Run("should emit trailing comments in indented mode", func(t *testing.T) {
+			jazon, err := data.MarshalJSONIndentWithOptions("", "  ", WithComments(true))
+			require.NoError(t, err)
+			assert.Equal(t, `{
+  "openapi": "3.1.0", // spec version
+  "info": {
+    "title": "API",
+    "x-internal": true // tooling metadata
+  }, // about
+  "tags": [
+    "a"
+  ],
+  "paths": {} // last
+}`, string(jazon))
+		})
+
+		t.Run("should omit comments by default", func(t *testing.T) {
+			jazon, err := data.MarshalJSONIndent("", "  ")
+			require.NoError(t, err)
+
+			expected, err := json.MarshalIndent(data, "", "  ")
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(jazon))
+			assert.NotContains(t, string(jazon), "//")
+		})
+
+		t.Run("should omit comments with strict JSON marshaling", func(t *testing.T) {
+			withoutComments := JSONMapSlice{
+				{Key: "openapi", Value: "3.1.0"},
+				{Key: "info", Value: JSONMapSlice{{Key: "title", Value: "API"}, {Key: "x-internal", Value: true}}},
+				{Key: "tags", Value: []any{"a"}},
+				{Key: "paths", Value: JSONMapSlice{}},
+			}
+			expected, err := withoutComments.MarshalJSON()
+			require.NoError(t, err)
+
+			jazon, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(jazon))
+
+			jazon, err = data.MarshalJSONWithOptions(WithComments(true))
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(jazon))
+
+			assert.True(t, data.Equal(withoutComments))
+		})
+
+		t.Run("should retain comments in copies", func(t *testing.T) {
+			assert.Equal(t, data, data.Clone())
+			assert.Equal(t, "spec version", data.SortKeysRecursive()[1].Comment)
+		})
+	})
+
+	t.Run("should encode MapSlice to a writer", func(t *testing.T) {
+		t.Run("with small object", func(t *testing.T) {
+			const sd = `{"a":1,"b":[true,"x",{"c":null}],"d":{}}`
+			var data JSONMapSlice
+			require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+			var buf bytes.Buffer
+			require.NoError(t, data.EncodeTo(&buf))
+			assert.Equal(t, sd, buf.String())
+		})
+
+		t.Run("with null", func(t *testing.T) {
+			var data JSONMapSlice
+
+			var buf bytes.Buffer
+			require.NoError(t, data.EncodeTo(&buf))
+			assert.Equal(t, `null`, buf.String())
+		})
+
+		t.Run("with large object", func(t *testing.T) {
+			data := makeLargeMapSlice(100)
+
+			expected, err := data.MarshalJSON()
+			require.NoError(t, err)
+			require.Greater(t, len(expected), flushThreshold)
+
+			w := &countingWriter{}
+			require.NoError(t, data.EncodeTo(w))
+			assert.Equal(t, string(expected), w.String())
+			assert.Greater(t, w.writes, 1)
+		})
+
+		t.Run("with large array values", func(t *testing.T) {
+			const size = 10000
+			generic := make([]any, size)
+			strs := make([]string, size)
+			objects := make([]JSONMapSlice, size)
+			for i := 0; i < size; i++ {
+				generic[i] = int64(i)
+				strs[i] = "<value " + strconv.Itoa(i) + ">"
+				objects[i] = JSONMapSlice{{Key: "i", Value: i}, {Key: "f", Value: float64(i) / 2}}
+			}
+
+			for _, value := range []any{generic, strs, objects} {
+				data := JSONMapSlice{{Key: "array", Value: value}}
+
+				expected, err := data.MarshalJSON()
+				require.NoError(t, err)
+				require.Greater(t, len(expected), flushThreshold)
+
+				w := &countingWriter{}
+				require.NoError(t, data.EncodeTo(w))
+				assert.Equal(t, string(expected), w.String())
+				assert.Greaterf(t, w.writes, 1, "expected a %T array to be streamed", value)
+			}
+		})
+
+		t.Run("with arrays rendered specifically", func(t *testing.T) {
+			data := JSONMapSlice{
+				{Key: "bytes", Value: []byte("abc")},
+				{Key: "marshaler", Value: customMarshalerSlice{1, 2}},
+				{Key: "nil", Value: []string(nil)},
+				{Key: "empty", Value: []int{}},
+				{Key: "array", Value: [3]int{1, 2, 3}},
+				{Key: "structs", Value: []struct{ A string }{{A: "x"}}},
+				{Key: "pointers", Value: []*int{nil}},
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, data.EncodeTo(&buf))
+			assert.Equal(t,
+				`{"bytes":"YWJj","marshaler":"2 elements","nil":null,"empty":[],"array":[1,2,3],"structs":[{"A":"x"}],"pointers":[null]}`,
+				buf.String(),
+			)
+
+			expected, err := data.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), buf.String())
+		})
+
+		t.Run("with error from an array element", func(t *testing.T) {
+			data := JSONMapSlice{{Key: "a", Value: []any{1, make(chan int)}}}
+
+			var buf bytes.Buffer
+			require.Error(t, data.EncodeTo(&buf))
+
+			data = JSONMapSlice{{Key: "a", Value: []chan int{make(chan int)}}}
+			require.Error(t, data.EncodeTo(&buf))
+		})
+
+		t.Run("with error from value", func(t *testing.T) {
+			data := JSONMapSlice{{Key: "a", Value: make(chan int)}}
+
+			var buf bytes.Buffer
+			require.Error(t, data.EncodeTo(&buf))
+		})
+
+		t.Run("with error from writer", func(t *testing.T) {
+			data := makeLargeMapSlice(100)
+
+			require.ErrorIs(t, data.EncodeTo(failingWriter{}), errTestWriter)
+		})
+	})
+
+	t.Run("should marshal MapSlice into a caller-provided buffer", func(t *testing.T) {
+		t.Run("should append several fragments", func(t *testing.T) {
+			first := JSONMapSlice{{Key: "a", Value: 1}, {Key: "b", Value: []any{"x", nil}}}
+			second := JSONMapSlice{{Key: "c", Value: JSONMapSlice{{Key: "d", Value: "<&>"}}}}
+
+			var buf bytes.Buffer
+			buf.WriteString(`[`)
+			require.NoError(t, first.MarshalJSONTo(&buf))
+			buf.WriteString(`,`)
+			require.NoError(t, second.MarshalJSONTo(&buf))
+			buf.WriteString(`]`)
+
+			assert.Equal(t, `[{"a":1,"b":["x",null]},{"c":{"d":"\u003c\u0026\u003e"}}]`, buf.String())
+		})
+
+		t.Run("should produce the same output as MarshalJSON", func(t *testing.T) {
+			data := makeLargeMapSlice(100)
+			expected, err := data.MarshalJSON()
+			require.NoError(t, err)
+
+			buf := bytes.NewBufferString("prefix")
+			require.NoError(t, data.MarshalJSONTo(buf))
+			assert.Equal(t, "prefix"+string(expected), buf.String())
+		})
+
+		t.Run("should apply options", func(t *testing.T) {
+			data := JSONMapSlice{{Key: "a", Value: "<&>"}}
+
+			var buf bytes.Buffer
+			require.NoError(t, data.MarshalJSONTo(&buf, WithEscapeHTML(false)))
+			assert.Equal(t, `{"a":"<&>"}`, buf.String())
+		})
+
+		t.Run("with null", func(t *testing.T) {
+			var data JSONMapSlice
+
+			var buf bytes.Buffer
+			require.NoError(t, data.MarshalJSONTo(&buf))
+			assert.Equal(t, `null`, buf.String())
+		})
+
+		t.Run("should leave the buffer unchanged on error", func(t *testing.T) {
+			data := JSONMapSlice{{Key: "a", Value: 1}, {Key: "b", Value: make(chan int)}}
+
+			buf := bytes.NewBufferString("prefix")
+			require.Error(t, data.MarshalJSONTo(buf))
+			assert.Equal(t, "prefix", buf.String())
+		})
+	})
+
+	t.Run("should access keys", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "a", Value: 1},
+			{Key: "b", Value: "x"},
+			{Key: "c", Value: nil},
+		}
+
+		t.Run("with Get", func(t *testing.T) {
+			v, ok := data.Get("b")
+			require.True(t, ok)
+			assert.Equal(t, "x", v)
+
+			v, ok = data.Get("c")
+			require.True(t, ok)
+			assert.Nil(t, v)
+
+			_, ok = data.Get("z")
+			require.False(t, ok)
+
+			var empty JSONMapSlice
+			_, ok = empty.Get("a")
+			require.False(t, ok)
+		})
+
+		t.Run("with GetFold", func(t *testing.T) {
+			headers := JSONMapSlice{
+				{Key: "Accept", Value: "*/*"},
+				{Key: "Content-Type", Value: "application/json"},
+				{Key: "content-type", Value: "text/plain"},
+				{Key: "Straße", Value: "unicode"},
+			}
+
+			for _, key := range []string{"Content-Type", "content-type", "CONTENT-TYPE"} {
+				v, ok := headers.GetFold(key)
+				require.Truef(t, ok, "expected to find %q", key)
+				assert.Equalf(t, "application/json", v, "expected the first match for %q", key)
+			}
+
+			v, ok := headers.Get("content-type")
+			require.True(t, ok)
+			assert.Equal(t, "text/plain", v, "Get should still match exactly")
+
+			v, ok = headers.GetFold("STRASSE")
+			require.False(t, ok, "case folding does not expand characters")
+			assert.Nil(t, v)
+
+			v, ok = headers.GetFold("STRAßE")
+			require.True(t, ok)
+			assert.Equal(t, "unicode", v)
+
+			_, ok = headers.GetFold("content")
+			require.False(t, ok)
+
+			var empty JSONMapSlice
+			_, ok = empty.GetFold("a")
+			require.False(t, ok)
+		})
+
+		t.Run("with Has", func(t *testing.T) {
+			assert.True(t, data.Has("a"))
+			assert.True(t, data.Has("c"))
+			assert.False(t, data.Has("z"))
+		})
+
+		t.Run("with Set", func(t *testing.T) {
+			cp := append(JSONMapSlice{}, data...)
+
+			cp.Set("a", 10)
+			cp.Set("d", true)
+			require.Equal(t, JSONMapSlice{
+				{Key: "a", Value: 10},
+				{Key: "b", Value: "x"},
+				{Key: "c", Value: nil},
+				{Key: "d", Value: true},
+			}, cp)
+
+			var empty JSONMapSlice
+			empty.Set("a", 1)
+			require.Equal(t, JSONMapSlice{{Key: "a", Value: 1}}, empty)
+		})
+
+		t.Run("with Set on duplicate keys, should update the first occurrence", func(t *testing.T) {
+			dup := JSONMapSlice{
+				{Key: "a", Value: 1},
+				{Key: "b", Value: 2},
+				{Key: "a", Value: 3},
+			}
+
+			dup.Set("a", 4)
+			require.Equal(t, JSONMapSlice{
+				{Key: "a", Value: 4},
+				{Key: "b", Value: 2},
+				{Key: "a", Value: 3},
+			}, dup)
+		})
+
+		t.Run("with Delete", func(t *testing.T) {
+			cp := append(JSONMapSlice{}, data...)
+
+			require.True(t, cp.Delete("b"))
+			require.False(t, cp.Delete("b"))
+			require.Equal(t, JSONMapSlice{
+				{Key: "a", Value: 1},
+				{Key: "c", Value: nil},
+			}, cp)
+
+			jazon, err := json.Marshal(cp)
+			require.NoError(t, err)
+			require.Equal(t, `{"a":1,"c":null}`, string(jazon))
+		})
+
+		t.Run("with InsertAt", func(t *testing.T) {
+			keys := func(s JSONMapSlice) []string {
+				k := make([]string, 0, len(s))
+				for _, item := range s {
+					k = append(k, item.Key)
+				}
+
+				return k
+			}
+
+			cp := append(JSONMapSlice{}, data...)
+			cp.InsertAt(1, "m", true)
+			require.Equal(t, JSONMapSlice{
+				{Key: "a", Value: 1},
+				{Key: "m", Value: true},
+				{Key: "b", Value: "x"},
+				{Key: "c", Value: nil},
+			}, cp)
+
+			cp.InsertAt(0, "first", 0)
+			cp.InsertAt(cp.Len(), "last", 9)
+			assert.Equal(t, []string{"first", "a", "m", "b", "c", "last"}, keys(cp))
+
+			t.Run("should clamp out-of-range indices", func(t *testing.T) {
+				cp := append(JSONMapSlice{}, data...)
+				cp.InsertAt(-5, "front", 1)
+				cp.InsertAt(100, "back", 2)
+				assert.Equal(t, []string{"front", "a", "b", "c", "back"}, keys(cp))
+
+				var empty JSONMapSlice
+				empty.InsertAt(3, "a", 1)
+				assert.Equal(t, JSONMapSlice{{Key: "a", Value: 1}}, empty)
+			})
+
+			t.Run("should move an existing key", func(t *testing.T) {
+				cp := append(JSONMapSlice{}, data...)
+				cp.InsertAt(0, "c", "updated")
+				assert.Equal(t, JSONMapSlice{
+					{Key: "c", Value: "updated"},
+					{Key: "a", Value: 1},
+					{Key: "b", Value: "x"},
+				}, cp)
+			})
+		})
+
+		t.Run("with MoveKey", func(t *testing.T) {
+			spec := JSONMapSlice{
+				{Key: "info", Value: 1},
+				{Key: "paths", Value: 2},
+				{Key: "openapi", Value: "3.1.0"},
+				{Key: "servers", Value: 3},
+			}
+
+			require.True(t, spec.MoveKey("openapi", 0))
+			assert.Equal(t, JSONMapSlice{
+				{Key: "openapi", Value: "3.1.0"},
+				{Key: "info", Value: 1},
+				{Key: "paths", Value: 2},
+				{Key: "servers", Value: 3},
+			}, spec)
+
+			require.True(t, spec.MoveKey("info", 2))
+			assert.Equal(t, JSONMapSlice{
+				{Key: "openapi", Value: "3.1.0"},
+				{Key: "paths", Value: 2},
+				{Key: "info", Value: 1},
+				{Key: "servers", Value: 3},
+			}, spec)
+
+			require.True(t, spec.MoveKey("openapi", 100))
+			require.True(t, spec.MoveKey("servers", -1))
+			require.True(t, spec.MoveKey("paths", 1))
+			assert.Equal(t, JSONMapSlice{
+				{Key: "servers", Value: 3},
+				{Key: "paths", Value: 2},
+				{Key: "info", Value: 1},
+				{Key: "openapi", Value: "3.1.0"},
+			}, spec)
+
+			require.False(t, spec.MoveKey("missing", 0))
+			assert.Len(t, spec, 4)
+
+			var empty JSONMapSlice
+			require.False(t, empty.MoveKey("a", 0))
+		})
+	})
+
+	t.Run("should report length and emptiness", func(t *testing.T) {
+		var empty JSONMapSlice
+		assert.Equal(t, 0, empty.Len())
+		assert.True(t, empty.IsEmpty())
+
+		assert.Equal(t, 0, JSONMapSlice{}.Len())
+		assert.True(t, JSONMapSlice{}.IsEmpty())
+
+		data := JSONMapSlice{{Key: "a", Value: 1}, {Key: "a", Value: 2}}
+		assert.Equal(t, 2, data.Len())
+		assert.False(t, data.IsEmpty())
+	})
+
+	t.Run("should render as a string", func(t *testing.T) {
+		var empty JSONMapSlice
+		assert.Equal(t, "null", empty.String())
+		assert.Equal(t, "{}", JSONMapSlice{}.String())
+
+		data := JSONMapSlice{{Key: "b", Value: 1}, {Key: "a", Value: []any{"x", JSONMapSlice{{Key: "c", Value: nil}}}}}
+		assert.Equal(t, `{"b":1,"a":["x",{"c":null}]}`, data.String())
+		assert.Equal(t, `{"b":1,"a":["x",{"c":null}]}`, fmt.Sprintf("%v", data))
+
+		var _ fmt.Stringer = data
+
+		t.Run("should not panic on values that cannot be marshaled", func(t *testing.T) {
+			invalid := JSONMapSlice{{Key: "a", Value: func() {}}}
+			require.NotPanics(t, func() {
+				assert.Contains(t, invalid.String(), "%!(JSON_ERROR=")
+			})
+
+			panicking := JSONMapSlice{{Key: "a", Value: panickingMarshaler{}}}
+			require.NotPanics(t, func() {
+				assert.Contains(t, panicking.String(), "%!(JSON_PANIC=")
+			})
+		})
+	})
+
+	t.Run("should Clone", func(t *testing.T) {
+		const sd = `{"a":1,"b":{"c":[1,{"d":{"e":"x"}},[2,3]]},"f":null,"g":[]}`
 		var data JSONMapSlice
 		require.NoError(t, json.Unmarshal([]byte(sd), &data))
-		jazon, err := json.Marshal(data)
-		require.NoError(t, err)
 
-		require.Equal(t, sd, string(jazon)) // specifically check the same order, not JSONEq()
+		clone := data.Clone()
+		require.Equal(t, data, clone)
 
-		t.Run("should Read/Write JSON using CustomJSON", func(t *testing.T) {
-			var obj interface{}
-			require.NoError(t, FromDynamicJSON(data, &obj))
+		t.Run("mutating the clone should not affect the original", func(t *testing.T) {
+			clone.Set("a", 2)
+			require.True(t, clone.Delete("f"))
 
-			asMap, ok := obj.(map[string]interface{})
-			require.True(t, ok)
-			assert.Len(t, asMap, 4) // 3 fields in struct
+			b, _ := clone.Get("b")
+			nestedObject := b.(JSONMapSlice)
+			c, _ := nestedObject.Get("c")
+			nestedArray := c.([]any)
+			nestedArray[0] = "changed"
+			nestedArray[2].([]any)[1] = "changed"
+			d, _ := nestedArray[1].(JSONMapSlice).Get("d")
+			deepest := d.(JSONMapSlice)
+			deepest.Set("e", "changed")
+			require.True(t, nestedObject.Delete("c"))
 
-			var target JSONMapSlice
-			require.NoError(t, FromDynamicJSON(obj, &target))
-			// the order of keys may be altered, since the intermediary representation is a map[string]interface{}
+			jazon, err := json.Marshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, sd, string(jazon))
+
+			jazon, err = json.Marshal(nestedArray)
+			require.NoError(t, err)
+			assert.Equal(t, `["changed",{"d":{"e":"changed"}},[2,"changed"]]`, string(jazon))
+		})
+
+		t.Run("with nil and empty slices", func(t *testing.T) {
+			var empty JSONMapSlice
+			assert.Nil(t, empty.Clone())
+
+			empty = JSONMapSlice{{Key: "a", Value: []any(nil)}}
+			clone := empty.Clone()
+			assert.NotNil(t, clone)
+			assert.Equal(t, empty, clone)
 		})
 	})
 
-	// TODO: write tests on error cases
+	t.Run("should not share marshaled bytes across calls", func(t *testing.T) {
+		first, err := JSONMapSlice{{Key: "a", Value: 1}}.MarshalJSON()
+		require.NoError(t, err)
+
+		second, err := JSONMapSlice{{Key: "b", Value: 2}}.MarshalJSON()
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"a":1}`, string(first))
+		assert.Equal(t, `{"b":2}`, string(second))
+	})
+
+	t.Run("MarshalJSON with error cases", func(t *testing.T) {
+		t.Run("should return an error on unsupported value", func(t *testing.T) {
+			data := JSONMapSlice{
+				{Key: "a", Value: 1},
+				{Key: "b", Value: make(chan int)},
+				{Key: "c", Value: 3},
+			}
+
+			jazon, err := data.MarshalJSON()
+			require.Error(t, err)
+			assert.Nil(t, jazon)
+		})
+
+		t.Run("should return an error on unsupported nested value", func(t *testing.T) {
+			data := JSONMapSlice{
+				{Key: "a", Value: JSONMapSlice{
+					{Key: "b", Value: []any{
+						JSONMapSlice{{Key: "c", Value: make(chan int)}},
+					}},
+				}},
+			}
+
+			_, err := json.Marshal(data)
+			require.Error(t, err)
+		})
+	})
 
 	t.Run("UnmarshalJSON with error cases", func(t *testing.T) {
 		// test directly this endpoint, as the json standard library
@@ -200,5 +1865,330 @@ func TestJSONMapSlice(t *testing.T) {
 			err := data.UnmarshalJSON([]byte(sd))
 			require.Error(t, err)
 		})
+		t.Run("on malformed input", func(t *testing.T) {
+			for _, fixture := range []struct {
+				Title string
+				Input string
+			}{
+				{Title: "truncated object after colon", Input: `{"a":`},
+				{Title: "truncated object after key", Input: `{"a"`},
+				{Title: "truncated object after comma", Input: `{"a":1,`},
+				{Title: "truncated nested object", Input: `{"a":{"b":`},
+				{Title: "truncated array", Input: `{"a":[1,2`},
+				{Title: "truncated array in array", Input: `{"a":[[1],[2`},
+				{Title: "truncated string", Input: `{"a":"abc`},
+				{Title: "garbage after value", Input: `{"a":1 garbage}`},
+				{Title: "garbage after nested object", Input: `{"a":{"b":1}x,"c":2}`},
+				{Title: "garbage in array", Input: `{"a":[1,{"b":2}x]}`},
+				{Title: "unbalanced closing bracket", Input: `{"a":"b"]`},
+				{Title: "unbalanced nested braces", Input: `{"a":[{"b":1]}`},
+				{Title: "unbalanced nested object", Input: `{"a":{"b":{"c":1}}`},
+			} {
+				t.Run(fixture.Title, func(t *testing.T) {
+					data := JSONMapSlice{{Key: "untouched", Value: true}}
+					err := data.UnmarshalJSON([]byte(fixture.Input))
+					require.Error(t, err)
+					assert.Equal(t, JSONMapSlice{{Key: "untouched", Value: true}}, data)
+				})
+			}
+		})
+
+		t.Run("on crafted input", func(t *testing.T) {
+			for _, fixture := range []struct {
+				Title string
+				Input string
+			}{
+				{Title: "NaN value", Input: `{"a":NaN}`},
+				{Title: "infinite value", Input: `{"a":Infinity}`},
+				{Title: "negative infinite value", Input: `{"a":[-Infinity]}`},
+				{Title: "number key", Input: `{1:"a"}`},
+				{Title: "null key", Input: `{"a":{null:1}}`},
+				{Title: "boolean key", Input: `{true:1}`},
+				{Title: "object key", Input: `{{}:1}`},
+				{Title: "array key", Input: `{"a":[{[]:1}]}`},
+			} {
+				t.Run(fixture.Title, func(t *testing.T) {
+					require.NotPanics(t, func() {
+						var data JSONMapSlice
+						require.ErrorIs(t, data.UnmarshalJSON([]byte(fixture.Input)), ErrJSON)
+
+						_, err := Unmarshal([]byte(fixture.Input))
+						require.ErrorIs(t, err, ErrJSON)
+
+						_, err = UnmarshalReader(strings.NewReader(fixture.Input))
+						require.ErrorIs(t, err, ErrJSON)
+					})
+				})
+			}
+		})
+
+		t.Run("on a non-string key token", func(t *testing.T) {
+			// the standard decoder never yields such tokens in key position, but the guard must not panic
+			for _, token := range []json.Token{json.Number("1"), json.Delim('['), true, nil} {
+				d := newJSONDecoder(strings.NewReader(`1}`), defaultDecodeOptions())
+				d.currentToken = token
+
+				var mi JSONMapItem
+				require.NotPanics(t, func() { mi.UnmarshalCustomJSON(d) })
+				require.ErrorIs(t, d.err, ErrJSON)
+
+				var parseErr *ParseError
+				require.ErrorAs(t, d.err, &parseErr)
+				assert.Equal(t, "a JSON object key", parseErr.Expected)
+			}
+		})
+
+		t.Run("on numbers out of the range of float64", func(t *testing.T) {
+			for _, sd := range []string{`[1e400]`, `-1e400`, `{"a":[{"b":1e309}]}`} {
+				_, err := Unmarshal([]byte(sd))
+				require.ErrorIsf(t, err, ErrJSON, "expected an error for %q", sd)
+			}
+		})
+	})
+}
+
+func TestJSONMapSliceInterface(t *testing.T) {
+	const sd = `{"o":{"a":[1,-2.5,"s",true,null,{"b":[[]]}],"n":12345678901234567890},"e":{},"f":false,"z":null}`
+
+	// checkTypes asserts that all nested values are of the types documented by JSONMapSlice
+	var checkTypes func(t *testing.T, value any, useNumber bool)
+	checkTypes = func(t *testing.T, value any, useNumber bool) {
+		switch v := value.(type) {
+		case JSONMapSlice:
+			assert.NotNil(t, v)
+			for _, item := range v {
+				checkTypes(t, item.Value, useNumber)
+			}
+		case []any:
+			assert.NotNil(t, v)
+			for _, elem := range v {
+				checkTypes(t, elem, useNumber)
+			}
+		case json.Number:
+			assert.True(t, useNumber, "unexpected json.Number")
+		case int64, float64:
+			assert.False(t, useNumber, "unexpected %T", v)
+		case string, bool, nil:
+		default:
+			assert.Failf(t, "unexpected type", "%T", v)
+		}
+	}
+
+	t.Run("should return the receiver", func(t *testing.T) {
+		data := JSONMapSlice{{Key: "a", Value: 1}}
+		value := data.Interface()
+		require.IsType(t, JSONMapSlice{}, value)
+		assert.Equal(t, data, value)
+
+		value.(JSONMapSlice)[0].Value = 2
+		assert.Equal(t, 2, data[0].Value, "the receiver should not be copied")
+
+		assert.Equal(t, JSONMapSlice(nil), JSONMapSlice(nil).Interface())
+	})
+
+	t.Run("should hold values of the documented types after a parse", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+		checkTypes(t, data.Interface(), false)
+
+		n, ok := data[0].Value.(JSONMapSlice).Get("n")
+		require.True(t, ok)
+		assert.IsType(t, float64(0), n, "integers overflowing int64 should be float64")
+
+		require.NoError(t, data.UnmarshalJSONWithOptions([]byte(sd), WithUseNumber(true)))
+		checkTypes(t, data.Interface(), true)
+
+		root, err := Unmarshal([]byte(`[` + sd + `,1,[]]`))
+		require.NoError(t, err)
+		checkTypes(t, root, false)
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("should unmarshal any JSON root", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Input    string
+			Expected any
+		}{
+			{Title: "with array", Input: `[1,2,3]`, Expected: []any{int64(1), int64(2), int64(3)}},
+			{Title: "with empty array", Input: `[]`, Expected: []any{}},
+			{Title: "with array of objects", Input: `[{"b":1,"a":2},[{"c":true}]]`, Expected: []any{
+				JSONMapSlice{{Key: "b", Value: int64(1)}, {Key: "a", Value: int64(2)}},
+				[]any{JSONMapSlice{{Key: "c", Value: true}}},
+			}},
+			{Title: "with object", Input: `{"b":1,"a":[2]}`, Expected: JSONMapSlice{{Key: "b", Value: int64(1)}, {Key: "a", Value: []any{int64(2)}}}},
+			{Title: "with empty object", Input: `{}`, Expected: JSONMapSlice{}},
+			{Title: "with string", Input: `"hello"`, Expected: "hello"},
+			{Title: "with integer", Input: `42`, Expected: int64(42)},
+			{Title: "with float", Input: ` 4.2 `, Expected: 4.2},
+			{Title: "with boolean", Input: `false`, Expected: false},
+			{Title: "with null", Input: `null`, Expected: nil},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				value, err := Unmarshal([]byte(fixture.Input))
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, value)
+			})
+		}
+	})
+
+	t.Run("should unmarshal with options", func(t *testing.T) {
+		value, err := Unmarshal([]byte(`[42,{"a":1,"a":2}] []`),
+			WithUseNumber(true), WithDuplicateKeyPolicy(DuplicateKeyLast), WithAllowTrailingContent(true),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []any{json.Number("42"), JSONMapSlice{{Key: "a", Value: json.Number("2")}}}, value)
+	})
+
+	t.Run("should fail on invalid input", func(t *testing.T) {
+		for _, sd := range []string{``, ` `, `[1,2`, `[1,]`, `"hello`, `42}`, `[{"a":1]`, `nul`, `1 2`, `[] {}`} {
+			value, err := Unmarshal([]byte(sd))
+			require.Errorf(t, err, "expected an error for %q", sd)
+			assert.Nil(t, value)
+		}
+	})
+}
+
+func TestParseError(t *testing.T) {
+	t.Run("should report the position of the offending token", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Input    string
+			Offset   int64
+			Expected string
+			Actual   string
+		}{
+			{Title: "with array root", Input: `[1,2]`, Offset: 1, Expected: "a JSON object", Actual: "'['"},
+			{Title: "with string root", Input: `  "x"`, Offset: 5, Expected: "a JSON object", Actual: `string "x"`},
+			{Title: "with number root", Input: "\n42", Offset: 3, Expected: "a JSON object", Actual: "number 42"},
+			{Title: "with trailing object", Input: `{"a":1}{"b":2}`, Offset: 8, Expected: "the end of the input after the JSON value", Actual: "'{'"},
+			{Title: "with trailing value", Input: `{"a":{"b":1}} true`, Offset: 18, Expected: "the end of the input after the JSON value", Actual: "true"},
+			{Title: "with invalid character", Input: `{"a":1 x}`, Offset: 8},
+			{Title: "with invalid literal", Input: `{"a":[tru]}`, Offset: 10},
+			{Title: "with trailing garbage", Input: `{"a":1}  x`, Offset: 10},
+			{Title: "with truncated object", Input: `{"a":`, Offset: 5},
+			{Title: "with truncated array", Input: `{"a":[1,2`, Offset: 9},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				var data JSONMapSlice
+				err := data.UnmarshalJSON([]byte(fixture.Input))
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrJSON)
+
+				var parseErr *ParseError
+				require.ErrorAs(t, err, &parseErr)
+				assert.Equal(t, fixture.Offset, parseErr.Offset)
+				assert.Equal(t, fixture.Expected, parseErr.Expected)
+				assert.Equal(t, fixture.Actual, parseErr.Actual)
+				assert.Contains(t, err.Error(), "offset "+strconv.FormatInt(fixture.Offset, 10))
+			})
+		}
+	})
+
+	t.Run("should wrap syntax errors", func(t *testing.T) {
+		var data JSONMapSlice
+		err := data.UnmarshalJSON([]byte(`{"a":1 x}`))
+
+		var syntaxErr *json.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		assert.Equal(t, syntaxErr.Offset, err.(*ParseError).Offset)
+	})
+
+	t.Run("should wrap unexpected EOF", func(t *testing.T) {
+		var data JSONMapSlice
+		err := data.UnmarshalJSON([]byte(`{"a":1,`))
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
 	})
+
+	t.Run("should not wrap reader errors", func(t *testing.T) {
+		_, err := UnmarshalReader(iotest.ErrReader(errTestWriter))
+		require.ErrorIs(t, err, errTestWriter)
+
+		var parseErr *ParseError
+		require.False(t, errors.As(err, &parseErr))
+	})
+}
+
+var errTestWriter = errors.New("test writer error")
+
+type panickingMarshaler struct{}
+
+func (panickingMarshaler) MarshalJSON() ([]byte, error) {
+	panic("test marshaler panic")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, errTestWriter
+}
+
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+
+	return w.Buffer.Write(p)
+}
+
+// allocatedBytes returns the number of bytes allocated on the heap while running a function.
+func allocatedBytes(fn func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func makeLargeMapSlice(size int) JSONMapSlice {
+	data := make(JSONMapSlice, 0, size)
+	for i := 0; i < size; i++ {
+		key := "key" + strconv.Itoa(i)
+		data = append(data, JSONMapItem{
+			Key: key,
+			Value: JSONMapSlice{
+				{Key: "name", Value: "a string value for " + key},
+				{Key: "index", Value: int64(i)},
+				{Key: "values", Value: []any{true, 10.35, nil, JSONMapSlice{{Key: "x", Value: key}}}},
+			},
+		})
+	}
+
+	return data
+}
+
+// endlessObjectReader produces a JSON object with an infinite number of keys.
+type endlessObjectReader struct {
+	buf []byte
+	n   int
+}
+
+func (r *endlessObjectReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if r.n == 0 {
+			r.buf = append(r.buf, '{')
+		} else {
+			r.buf = append(r.buf, ',')
+		}
+		r.buf = fmt.Appendf(r.buf, `"k%d":%d`, r.n, r.n)
+		r.n++
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+// customMarshalerSlice is a slice type with a custom JSON rendering.
+type customMarshalerSlice []int
+
+func (c customMarshalerSlice) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.Itoa(len(c)) + ` elements"`), nil
 }
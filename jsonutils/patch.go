@@ -0,0 +1,306 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JSON Patch operations
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+// PatchOp is a single operation of a JSON Patch (RFC 6902) document.
+//
+// A JSON Patch document may be unmarshaled as a []PatchOp. Values are unmarshaled
+// as ordered [JSONMapSlice] objects.
+//
+// See https://www.rfc-editor.org/rfc/rfc6902
+type PatchOp struct {
+	Op    string
+	Path  string
+	From  string
+	Value any
+}
+
+// MarshalJSON renders a [PatchOp] as a JSON Patch operation object.
+func (p PatchOp) MarshalJSON() ([]byte, error) {
+	obj := JSONMapSlice{
+		{Key: "op", Value: p.Op},
+		{Key: "path", Value: p.Path},
+	}
+
+	switch p.Op {
+	case PatchOpMove, PatchOpCopy:
+		obj.Set("from", p.From)
+	case PatchOpRemove:
+		// no value
+	default:
+		obj.Set("value", p.Value)
+	}
+
+	return obj.MarshalJSON()
+}
+
+// UnmarshalJSON builds a [PatchOp] from a JSON Patch operation object.
+func (p *PatchOp) UnmarshalJSON(data []byte) error {
+	var obj JSONMapSlice
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	var op PatchOp
+	for _, item := range obj {
+		switch item.Key {
+		case "op", "path", "from":
+			str, ok := item.Value.(string)
+			if !ok {
+				return fmt.Errorf("invalid JSON patch operation: %q must be a string, but got %T: %w", item.Key, item.Value, ErrJSON)
+			}
+
+			switch item.Key {
+			case "op":
+				op.Op = str
+			case "path":
+				op.Path = str
+			default:
+				op.From = str
+			}
+		case "value":
+			op.Value = item.Value
+		}
+	}
+
+	*p = op
+
+	return nil
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to a copy of a [JSONMapSlice].
+//
+// Supported operations are "add", "remove", "replace", "move", "copy" and "test".
+//
+// The order of keys is preserved for untouched parts of the object. Adding a new key to an object
+// appends it at the end of that object. Adding to an array inserts the value at the given index.
+//
+// The receiver is never mutated. The patch is applied atomically: if any operation fails,
+// an error is returned and no result is produced.
+func (s JSONMapSlice) ApplyPatch(patch []PatchOp) (JSONMapSlice, error) {
+	var doc any = s.Clone()
+
+	for i, op := range patch {
+		var err error
+		doc, err = applyPatchOp(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("JSON patch operation %d (%s %q) failed: %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	result, ok := doc.(JSONMapSlice)
+	if !ok && doc != nil {
+		return nil, fmt.Errorf("JSON patch result is not an object, but %T: %w", doc, ErrJSON)
+	}
+
+	return result, nil
+}
+
+func applyPatchOp(doc any, op PatchOp) (any, error) {
+	tokens, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case PatchOpAdd:
+		return patchAdd(doc, tokens, cloneValue(op.Value))
+	case PatchOpRemove:
+		doc, _, err = patchRemove(doc, tokens)
+
+		return doc, err
+	case PatchOpReplace:
+		return patchReplace(doc, tokens, cloneValue(op.Value))
+	case PatchOpMove:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.From == op.Path {
+			if _, err = resolveTokens(doc, from); err != nil {
+				return nil, err
+			}
+
+			return doc, nil
+		}
+
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %q into one of its children %q: %w", op.From, op.Path, ErrJSON)
+		}
+
+		doc, value, err := patchRemove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return patchAdd(doc, tokens, value)
+	case PatchOpCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := resolveTokens(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return patchAdd(doc, tokens, cloneValue(value))
+	case PatchOpTest:
+		value, err := resolveTokens(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equalValues(value, op.Value, false) {
+			return nil, fmt.Errorf("test failed: value at %q is not equal to the expected value: %w", op.Path, ErrJSON)
+		}
+
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON patch operation %q: %w", op.Op, ErrJSON)
+	}
+}
+
+// updateAt navigates to the container designated by all tokens but the last one,
+// then replaces this container by the result of the update function.
+//
+// It returns the updated document.
+func updateAt(doc any, tokens []string, depth int, update func(container any, token, at string) (any, error)) (any, error) {
+	at := pointerPrefix(tokens[:depth])
+	if depth == len(tokens)-1 {
+		return update(doc, tokens[depth], at)
+	}
+
+	child, err := pointerChild(doc, tokens[depth], at)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := updateAt(child, tokens, depth+1, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return replaceChild(doc, tokens[depth], updated), nil
+}
+
+// replaceChild sets the value of an existing key or index.
+func replaceChild(container any, token string, value any) any {
+	switch v := container.(type) {
+	case JSONMapSlice:
+		v[v.index(token)].Value = value
+
+		return v
+	case []any:
+		idx, _ := pointerIndex(token, len(v), "") // already resolved
+		v[idx] = value
+
+		return v
+	default:
+		return container
+	}
+}
+
+func patchAdd(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return updateAt(doc, tokens, 0, func(container any, token, at string) (any, error) {
+		switch v := container.(type) {
+		case JSONMapSlice:
+			v.Set(token, value)
+
+			return v, nil
+		case []any:
+			idx, err := pointerIndex(token, len(v), at)
+			if err != nil {
+				return nil, err
+			}
+			if idx > len(v) {
+				return nil, fmt.Errorf("index %d out of range [0:%d] at %q: %w", idx, len(v), at, ErrJSON)
+			}
+
+			v = append(v, nil)
+			copy(v[idx+1:], v[idx:])
+			v[idx] = value
+
+			return v, nil
+		default:
+			return nil, fmt.Errorf("cannot add %q at %q: value of type %T is not an object or an array: %w", token, at, container, ErrJSON)
+		}
+	})
+}
+
+func patchRemove(doc any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document: %w", ErrJSON)
+	}
+
+	var removed any
+	doc, err := updateAt(doc, tokens, 0, func(container any, token, at string) (any, error) {
+		var err error
+		removed, err = pointerChild(container, token, at)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := container.(type) {
+		case JSONMapSlice:
+			v.Delete(token)
+
+			return v, nil
+		case []any:
+			idx, _ := pointerIndex(token, len(v), at) // already resolved
+
+			return append(v[:idx], v[idx+1:]...), nil
+		default:
+			return container, nil
+		}
+	})
+
+	return doc, removed, err
+}
+
+func patchReplace(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return updateAt(doc, tokens, 0, func(container any, token, at string) (any, error) {
+		if _, err := pointerChild(container, token, at); err != nil {
+			return nil, err
+		}
+
+		return replaceChild(container, token, value), nil
+	})
+}
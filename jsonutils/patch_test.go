@@ -0,0 +1,136 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceApplyPatch(t *testing.T) {
+	const sd = `{"a":1,"b":{"c":"x","d":[1,2,3]},"e":null}`
+
+	parse := func(t *testing.T) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		return data
+	}
+
+	t.Run("should apply operations", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Patch    string
+			Expected string
+		}{
+			{Title: "add a new key", Patch: `[{"op":"add","path":"/f","value":{"z":1,"y":2}}]`, Expected: `{"a":1,"b":{"c":"x","d":[1,2,3]},"e":null,"f":{"z":1,"y":2}}`},
+			{Title: "add an existing key", Patch: `[{"op":"add","path":"/a","value":[true]}]`, Expected: `{"a":[true],"b":{"c":"x","d":[1,2,3]},"e":null}`},
+			{Title: "add into an array", Patch: `[{"op":"add","path":"/b/d/1","value":"new"}]`, Expected: `{"a":1,"b":{"c":"x","d":[1,"new",2,3]},"e":null}`},
+			{Title: "add at the end of an array", Patch: `[{"op":"add","path":"/b/d/-","value":4},{"op":"add","path":"/b/d/4","value":5}]`, Expected: `{"a":1,"b":{"c":"x","d":[1,2,3,4,5]},"e":null}`},
+			{Title: "add root", Patch: `[{"op":"add","path":"","value":{"z":1}}]`, Expected: `{"z":1}`},
+			{Title: "remove a key", Patch: `[{"op":"remove","path":"/b/c"}]`, Expected: `{"a":1,"b":{"d":[1,2,3]},"e":null}`},
+			{Title: "remove from an array", Patch: `[{"op":"remove","path":"/b/d/0"}]`, Expected: `{"a":1,"b":{"c":"x","d":[2,3]},"e":null}`},
+			{Title: "replace a key", Patch: `[{"op":"replace","path":"/a","value":"y"}]`, Expected: `{"a":"y","b":{"c":"x","d":[1,2,3]},"e":null}`},
+			{Title: "replace in an array", Patch: `[{"op":"replace","path":"/b/d/2","value":{"k":false}}]`, Expected: `{"a":1,"b":{"c":"x","d":[1,2,{"k":false}]},"e":null}`},
+			{Title: "move a key", Patch: `[{"op":"move","from":"/a","path":"/b/a"}]`, Expected: `{"b":{"c":"x","d":[1,2,3],"a":1},"e":null}`},
+			{Title: "move in an array", Patch: `[{"op":"move","from":"/b/d/0","path":"/b/d/-"}]`, Expected: `{"a":1,"b":{"c":"x","d":[2,3,1]},"e":null}`},
+			{Title: "move to itself", Patch: `[{"op":"move","from":"/b","path":"/b"}]`, Expected: sd},
+			{Title: "copy a key", Patch: `[{"op":"copy","from":"/b","path":"/f"},{"op":"add","path":"/f/d/-","value":4}]`, Expected: `{"a":1,"b":{"c":"x","d":[1,2,3]},"e":null,"f":{"c":"x","d":[1,2,3,4]}}`},
+			{Title: "test a value", Patch: `[{"op":"test","path":"/b","value":{"d":[1,2,3.0],"c":"x"}},{"op":"test","path":"/e","value":null}]`, Expected: sd},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				var patch []PatchOp
+				require.NoError(t, json.Unmarshal([]byte(fixture.Patch), &patch))
+
+				data := parse(t)
+				patched, err := data.ApplyPatch(patch)
+				require.NoError(t, err)
+
+				jazon, err := json.Marshal(patched)
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, string(jazon))
+
+				t.Run("should not mutate the original", func(t *testing.T) {
+					jazon, err := json.Marshal(data)
+					require.NoError(t, err)
+					assert.Equal(t, sd, string(jazon))
+				})
+			})
+		}
+	})
+
+	t.Run("should fail atomically", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title string
+			Patch string
+			Error string
+		}{
+			{Title: "failed test", Patch: `[{"op":"remove","path":"/a"},{"op":"test","path":"/b/c","value":"y"}]`, Error: "test failed"},
+			{Title: "remove missing key", Patch: `[{"op":"add","path":"/z","value":1},{"op":"remove","path":"/b/z"}]`, Error: `key "z" not found at "/b"`},
+			{Title: "remove root", Patch: `[{"op":"remove","path":""}]`, Error: "cannot remove the whole document"},
+			{Title: "replace missing key", Patch: `[{"op":"replace","path":"/z","value":1}]`, Error: `key "z" not found`},
+			{Title: "add out of range", Patch: `[{"op":"add","path":"/b/d/4","value":1}]`, Error: "out of range"},
+			{Title: "add to a scalar", Patch: `[{"op":"add","path":"/a/x","value":1}]`, Error: "not an object or an array"},
+			{Title: "add to a missing parent", Patch: `[{"op":"add","path":"/x/y","value":1}]`, Error: `key "x" not found`},
+			{Title: "move into a child", Patch: `[{"op":"move","from":"/b","path":"/b/f"}]`, Error: "into one of its children"},
+			{Title: "copy missing key", Patch: `[{"op":"copy","from":"/z","path":"/f"}]`, Error: `key "z" not found`},
+			{Title: "invalid pointer", Patch: `[{"op":"add","path":"a","value":1}]`, Error: "invalid JSON pointer"},
+			{Title: "unsupported operation", Patch: `[{"op":"merge","path":"/a","value":1}]`, Error: "unsupported JSON patch operation"},
+			{Title: "replace root with non-object", Patch: `[{"op":"replace","path":"","value":[1]}]`, Error: "not an object"},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				var patch []PatchOp
+				require.NoError(t, json.Unmarshal([]byte(fixture.Patch), &patch))
+
+				data := parse(t)
+				patched, err := data.ApplyPatch(patch)
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrJSON)
+				assert.ErrorContains(t, err, fixture.Error)
+				assert.Nil(t, patched)
+
+				jazon, err := json.Marshal(data)
+				require.NoError(t, err)
+				assert.Equal(t, sd, string(jazon))
+			})
+		}
+	})
+
+	t.Run("should marshal and unmarshal patch operations", func(t *testing.T) {
+		const patchJSON = `[{"op":"add","path":"/a","value":{"y":1,"x":2}},{"op":"remove","path":"/b"},{"op":"move","path":"/c","from":"/d"},{"op":"test","path":"/e","value":null}]`
+
+		var patch []PatchOp
+		require.NoError(t, json.Unmarshal([]byte(patchJSON), &patch))
+		require.Equal(t, []PatchOp{
+			{Op: PatchOpAdd, Path: "/a", Value: JSONMapSlice{{Key: "y", Value: int64(1)}, {Key: "x", Value: int64(2)}}},
+			{Op: PatchOpRemove, Path: "/b"},
+			{Op: PatchOpMove, Path: "/c", From: "/d"},
+			{Op: PatchOpTest, Path: "/e"},
+		}, patch)
+
+		jazon, err := json.Marshal(patch)
+		require.NoError(t, err)
+		assert.Equal(t, patchJSON, string(jazon))
+
+		t.Run("with invalid operation", func(t *testing.T) {
+			require.Error(t, json.Unmarshal([]byte(`[{"op":1,"path":"/a"}]`), &patch))
+		})
+	})
+}
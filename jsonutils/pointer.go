@@ -0,0 +1,303 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AtPointer resolves a JSON Pointer (RFC 6901) against a [JSONMapSlice].
+//
+// The pointer navigates through nested [JSONMapSlice] objects by key and through []any arrays by index.
+// The empty pointer "" refers to the whole object.
+//
+// Escaped tokens are supported: "~1" stands for "/" and "~0" stands for "~".
+//
+// See https://www.rfc-editor.org/rfc/rfc6901
+func (s JSONMapSlice) AtPointer(pointer string) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveTokens(s, tokens)
+}
+
+// Subtree resolves a JSON Pointer (RFC 6901) like [JSONMapSlice.AtPointer], and returns the nested
+// object it refers to as a standalone [JSONMapSlice].
+//
+// This is useful to split a large document into smaller ones, e.g. an OpenAPI spec into one document per path.
+//
+// The result is a deep copy: mutating it never affects the receiver. An error is returned if the pointer
+// does not resolve, or if it refers to a value which is not an object, such as an array, a scalar or null.
+func (s JSONMapSlice) Subtree(pointer string) (JSONMapSlice, error) {
+	value, err := s.AtPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := value.(JSONMapSlice)
+	if !ok || object == nil {
+		return nil, fmt.Errorf("value at %q is not a JSON object: %w", pointer, ErrJSON)
+	}
+
+	return object.Clone(), nil
+}
+
+// SetPath sets the value at a JSON Pointer (RFC 6901), creating missing intermediate objects as needed,
+// like "mkdir -p" does for directories.
+//
+// Existing keys keep their position and new keys are appended, like with [JSONMapSlice.Set].
+// Arrays are navigated by index: the last token of the pointer may be "-" or the length of the array
+// to append an element, but missing elements are never created along the way.
+//
+// An error is returned if the pointer is empty or invalid, or if a token collides with a value which is
+// not an object or an array, such as a scalar or null. The receiver is left unchanged in that case.
+func (s *JSONMapSlice) SetPath(pointer string, value any) error {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return fmt.Errorf("cannot set the whole document: %w", ErrJSON)
+	}
+
+	updated, err := setTokens(*s, tokens, 0, value)
+	if err != nil {
+		return err
+	}
+
+	*s = updated.(JSONMapSlice)
+
+	return nil
+}
+
+// DeletePath removes the key or array element at a JSON Pointer (RFC 6901), like the "remove" operation
+// of [JSONMapSlice.ApplyPatch], but in place.
+//
+// The order of the remaining keys is preserved, and the elements which follow a removed array element
+// are shifted down by one.
+//
+// An error is returned if the pointer is empty or invalid, or if it does not resolve, e.g. when a key
+// is not found or an index is out of range. The receiver is left unchanged in that case.
+func (s *JSONMapSlice) DeletePath(pointer string) error {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return err
+	}
+
+	updated, _, err := patchRemove(*s, tokens)
+	if err != nil {
+		return err
+	}
+
+	*s = updated.(JSONMapSlice)
+
+	return nil
+}
+
+// setTokens sets the value designated by tokens[depth:] in a container, and returns the updated container.
+//
+// Containers are only updated once the value at the end of the path is successfully set.
+func setTokens(container any, tokens []string, depth int, value any) (any, error) {
+	token, at := tokens[depth], pointerPrefix(tokens[:depth])
+	last := depth == len(tokens)-1
+
+	switch v := container.(type) {
+	case JSONMapSlice:
+		if last {
+			v.Set(token, value)
+
+			return v, nil
+		}
+
+		child, ok := v.Get(token)
+		if !ok {
+			child = JSONMapSlice{}
+		}
+
+		updated, err := setTokens(child, tokens, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		v.Set(token, updated)
+
+		return v, nil
+	case []any:
+		idx, err := pointerIndex(token, len(v), at)
+		if err != nil {
+			return nil, err
+		}
+
+		if last && idx == len(v) {
+			return append(v, value), nil
+		}
+
+		if idx >= len(v) {
+			return nil, fmt.Errorf("index %d out of range [0:%d] at %q: %w", idx, len(v), at, ErrJSON)
+		}
+
+		if last {
+			v[idx] = value
+
+			return v, nil
+		}
+
+		updated, err := setTokens(v[idx], tokens, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		v[idx] = updated
+
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q at %q: value of type %T is not an object or an array: %w", token, at, container, ErrJSON)
+	}
+}
+
+// resolveTokens navigates a document through a list of reference tokens.
+func resolveTokens(doc any, tokens []string) (any, error) {
+	current := doc
+	for i, token := range tokens {
+		var err error
+		current, err = pointerChild(current, token, pointerPrefix(tokens[:i]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return current, nil
+}
+
+// parsePointer splits a JSON pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/': %w", pointer, ErrJSON)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		unescaped, err := unescapePointerToken(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON pointer %q: %w", pointer, err)
+		}
+		tokens[i] = unescaped
+	}
+
+	return tokens, nil
+}
+
+func unescapePointerToken(token string) (string, error) {
+	if !strings.Contains(token, "~") {
+		return token, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(token))
+	for i := 0; i < len(token); i++ {
+		if token[i] != '~' {
+			b.WriteByte(token[i])
+
+			continue
+		}
+
+		if i+1 >= len(token) {
+			return "", fmt.Errorf("invalid escape sequence in token %q: %w", token, ErrJSON)
+		}
+
+		i++
+		switch token[i] {
+		case '0':
+			b.WriteByte('~')
+		case '1':
+			b.WriteByte('/')
+		default:
+			return "", fmt.Errorf("invalid escape sequence in token %q: %w", token, ErrJSON)
+		}
+	}
+
+	return b.String(), nil
+}
+
+func escapePointerToken(token string) string {
+	if !strings.ContainsAny(token, "~/") {
+		return token
+	}
+
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// pointerPrefix renders a list of reference tokens as a JSON pointer.
+func pointerPrefix(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escapePointerToken(token))
+	}
+
+	return b.String()
+}
+
+// pointerChild resolves a single reference token against a value.
+func pointerChild(value any, token, at string) (any, error) {
+	switch v := value.(type) {
+	case JSONMapSlice:
+		child, ok := v.Get(token)
+		if !ok {
+			return nil, fmt.Errorf("key %q not found at %q: %w", token, at, ErrJSON)
+		}
+
+		return child, nil
+	case []any:
+		idx, err := pointerIndex(token, len(v), at)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= len(v) {
+			return nil, fmt.Errorf("index %d out of range [0:%d] at %q: %w", idx, len(v), at, ErrJSON)
+		}
+
+		return v[idx], nil
+	default:
+		return nil, fmt.Errorf("cannot resolve token %q at %q: value of type %T is not an object or an array: %w", token, at, value, ErrJSON)
+	}
+}
+
+// pointerIndex parses an array index in a JSON pointer.
+//
+// The returned index may be out of range.
+func pointerIndex(token string, length int, at string) (int, error) {
+	if token == "-" {
+		return length, nil
+	}
+
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q at %q: %w", token, at, ErrJSON)
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q at %q: %w: %w", token, at, err, ErrJSON)
+	}
+
+	return idx, nil
+}
@@ -0,0 +1,286 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceAtPointer(t *testing.T) {
+	const sd = `{
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {"description": "ok"}
+        }
+      }
+    }
+  },
+  "a~b": 1,
+  "": "empty key",
+  "tags": [{"name": "x"}, {"name": "y"}, [10, 20]]
+}`
+
+	var data JSONMapSlice
+	require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+	t.Run("should resolve the root pointer", func(t *testing.T) {
+		v, err := data.AtPointer("")
+		require.NoError(t, err)
+		assert.Equal(t, data, v)
+	})
+
+	t.Run("should resolve pointers", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Pointer  string
+			Expected any
+		}{
+			{Pointer: "/paths/~1pets/get/responses/200/description", Expected: "ok"},
+			{Pointer: "/paths/~1pets/get/responses/200", Expected: JSONMapSlice{{Key: "description", Value: "ok"}}},
+			{Pointer: "/a~0b", Expected: int64(1)},
+			{Pointer: "/", Expected: "empty key"},
+			{Pointer: "/tags/0/name", Expected: "x"},
+			{Pointer: "/tags/1/name", Expected: "y"},
+			{Pointer: "/tags/2/1", Expected: int64(20)},
+		} {
+			t.Run(fixture.Pointer, func(t *testing.T) {
+				v, err := data.AtPointer(fixture.Pointer)
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, v)
+			})
+		}
+	})
+
+	t.Run("should fail to resolve pointers", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Pointer string
+			Error   string
+		}{
+			{Pointer: "paths", Error: "must be empty or start with '/'"},
+			{Pointer: "/paths/~2pets", Error: "invalid escape sequence"},
+			{Pointer: "/paths/pets~", Error: "invalid escape sequence"},
+			{Pointer: "/paths/pets", Error: `key "pets" not found at "/paths"`},
+			{Pointer: "/tags/3", Error: `index 3 out of range [0:3] at "/tags"`},
+			{Pointer: "/tags/-", Error: "out of range"},
+			{Pointer: "/tags/01", Error: `invalid array index "01"`},
+			{Pointer: "/tags/-1", Error: `invalid array index "-1"`},
+			{Pointer: "/tags/x", Error: `invalid array index "x"`},
+			{Pointer: "/tags/99999999999999999999999", Error: "invalid array index"},
+			{Pointer: "/a~0b/c", Error: `cannot resolve token "c" at "/a~0b"`},
+		} {
+			t.Run(fixture.Pointer, func(t *testing.T) {
+				_, err := data.AtPointer(fixture.Pointer)
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrJSON)
+				assert.ErrorContains(t, err, fixture.Error)
+			})
+		}
+	})
+}
+
+func TestJSONMapSliceSubtree(t *testing.T) {
+	const sd = `{
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listPets", "tags": ["pets"]}
+    },
+    "/users": null
+  },
+  "tags": [{"name": "x"}]
+}`
+
+	var data JSONMapSlice
+	require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+	t.Run("should extract a nested object", func(t *testing.T) {
+		sub, err := data.Subtree("/paths/~1pets")
+		require.NoError(t, err)
+
+		jazon, err := sub.MarshalJSON()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"get": {"operationId": "listPets", "tags": ["pets"]}}`, string(jazon))
+	})
+
+	t.Run("should extract an object in an array", func(t *testing.T) {
+		sub, err := data.Subtree("/tags/0")
+		require.NoError(t, err)
+		assert.Equal(t, JSONMapSlice{{Key: "name", Value: "x"}}, sub)
+	})
+
+	t.Run("should extract the whole object", func(t *testing.T) {
+		sub, err := data.Subtree("")
+		require.NoError(t, err)
+		assert.Equal(t, data, sub)
+	})
+
+	t.Run("should return a standalone copy", func(t *testing.T) {
+		sub, err := data.Subtree("/paths/~1pets/get")
+		require.NoError(t, err)
+		sub[0].Value = "changed"
+		sub[1].Value.([]any)[0] = "changed"
+
+		v, err := data.AtPointer("/paths/~1pets/get/operationId")
+		require.NoError(t, err)
+		assert.Equal(t, "listPets", v)
+
+		v, err = data.AtPointer("/paths/~1pets/get/tags/0")
+		require.NoError(t, err)
+		assert.Equal(t, "pets", v)
+	})
+
+	t.Run("should fail if the value is not an object", func(t *testing.T) {
+		for _, pointer := range []string{"/tags", "/paths/~1pets/get/operationId", "/paths/~1users"} {
+			_, err := data.Subtree(pointer)
+			require.ErrorIs(t, err, ErrJSON)
+			assert.ErrorContains(t, err, "is not a JSON object")
+		}
+	})
+
+	t.Run("should fail if the pointer does not resolve", func(t *testing.T) {
+		_, err := data.Subtree("/paths/~1orders")
+		require.ErrorIs(t, err, ErrJSON)
+		assert.ErrorContains(t, err, "not found")
+	})
+}
+
+func TestJSONMapSliceSetPath(t *testing.T) {
+	t.Run("should create a deep path from an empty object", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, data.SetPath("/info/contact/email", "a@example.com"))
+		require.NoError(t, data.SetPath("/info/contact/name", "a"))
+		require.NoError(t, data.SetPath("/info/x~1y", true))
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"info":{"contact":{"email":"a@example.com","name":"a"},"x/y":true}}`, string(jazon))
+	})
+
+	t.Run("should overwrite an existing leaf in place", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(`{"a":{"b":1,"c":2},"d":3}`), &data))
+
+		require.NoError(t, data.SetPath("/a/b", "x"))
+		require.NoError(t, data.SetPath("/a/e", 4))
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":{"b":"x","c":2,"e":4},"d":3}`, string(jazon))
+	})
+
+	t.Run("should navigate and append to arrays", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(`{"tags":[{"name":"x"}]}`), &data))
+
+		require.NoError(t, data.SetPath("/tags/0/meta/order", 1))
+		require.NoError(t, data.SetPath("/tags/-", "y"))
+		require.NoError(t, data.SetPath("/tags/2", "z"))
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"tags":[{"name":"x","meta":{"order":1}},"y","z"]}`, string(jazon))
+	})
+
+	t.Run("should fail when a token collides with a scalar", func(t *testing.T) {
+		const input = `{"a":{"b":1,"n":null},"tags":[1]}`
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(input), &data))
+
+		for _, pointer := range []string{"/a/b/c", "/a/n/c", "/a/b/c/d", "/tags/0/x"} {
+			err := data.SetPath(pointer, "value")
+			require.ErrorIs(t, err, ErrJSON)
+			assert.ErrorContains(t, err, "is not an object or an array")
+		}
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, input, string(jazon))
+	})
+
+	t.Run("should fail on invalid paths", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(`{"tags":[1]}`), &data))
+
+		for _, pointer := range []string{"", "tags", "/a/~2", "/tags/2", "/tags/-/x", "/tags/01"} {
+			require.ErrorIs(t, data.SetPath(pointer, "value"), ErrJSON)
+		}
+		assert.Equal(t, JSONMapSlice{{Key: "tags", Value: []any{int64(1)}}}, data)
+	})
+}
+
+func TestJSONMapSliceDeletePath(t *testing.T) {
+	const input = `{"info":{"title":"api","contact":{"email":"a@example.com","name":"a"},"version":"1"},"tags":["x","y","z"]}`
+
+	t.Run("should delete a nested object key", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(input), &data))
+
+		require.NoError(t, data.DeletePath("/info/contact/email"))
+		require.NoError(t, data.DeletePath("/info/title"))
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"info":{"contact":{"name":"a"},"version":"1"},"tags":["x","y","z"]}`, string(jazon))
+	})
+
+	t.Run("should delete an array element", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(input), &data))
+
+		require.NoError(t, data.DeletePath("/tags/0"))
+
+		v, err := data.AtPointer("/tags/0")
+		require.NoError(t, err)
+		assert.Equal(t, "y", v)
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"info":{"title":"api","contact":{"email":"a@example.com","name":"a"},"version":"1"},"tags":["y","z"]}`, string(jazon))
+	})
+
+	t.Run("should delete a top-level key", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(input), &data))
+
+		require.NoError(t, data.DeletePath("/info"))
+		assert.False(t, data.Has("info"))
+		assert.Equal(t, 1, len(data))
+	})
+
+	t.Run("should fail on missing paths", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(input), &data))
+
+		for _, pointer := range []string{"/info/license", "/nope/x", "/tags/3", "/tags/-"} {
+			require.ErrorIs(t, data.DeletePath(pointer), ErrJSON)
+		}
+
+		err := data.DeletePath("/info/contact/url")
+		require.ErrorIs(t, err, ErrJSON)
+		assert.ErrorContains(t, err, "not found")
+
+		for _, pointer := range []string{"", "info", "/info/title/x", "/tags/01"} {
+			require.ErrorIs(t, data.DeletePath(pointer), ErrJSON)
+		}
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, input, string(jazon))
+	})
+}
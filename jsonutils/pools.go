@@ -0,0 +1,68 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"sync"
+)
+
+const (
+	// minPooledBufferSize is the initial capacity of pooled JSON buffers.
+	minPooledBufferSize = 512
+
+	// maxPooledBufferSize is the capacity beyond which a JSON buffer is not recycled,
+	// so oversized buffers are not retained forever.
+	maxPooledBufferSize = 1024 * 1024
+)
+
+type (
+	// memory pools of temporary objects.
+	//
+	// These are used to recycle temporarily allocated objects
+	// and relieve the GC from undue pressure.
+
+	jsonBuffersPool struct {
+		*sync.Pool
+	}
+)
+
+// poolOfJSONBuffers holds temporary buffers for recycling when marshaling JSON
+var poolOfJSONBuffers = jsonBuffersPool{
+	Pool: &sync.Pool{
+		New: func() any {
+			return &jsonBuffer{
+				buffer: make([]byte, 0, minPooledBufferSize),
+			}
+		},
+	},
+}
+
+func (p jsonBuffersPool) BorrowJSONBuffer(o encodeOptions) *jsonBuffer {
+	jb := p.Get().(*jsonBuffer)
+	*jb = jsonBuffer{
+		buffer:        jb.buffer[:0], // reset slice, keep allocated capacity
+		encodeOptions: o,
+	}
+
+	return jb
+}
+
+func (p jsonBuffersPool) RedeemJSONBuffer(jb *jsonBuffer) {
+	if cap(jb.buffer) > maxPooledBufferSize {
+		return
+	}
+
+	p.Put(jb)
+}
@@ -0,0 +1,108 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import "strings"
+
+// DefaultRedaction is the replacement used by [JSONMapSlice.Redact] when none is specified.
+const DefaultRedaction = "***"
+
+// DefaultSensitiveKeys returns the keys redacted by [JSONMapSlice.Redact] when none are specified,
+// i.e. keys which usually hold credentials, such as "authorization", "password" or "token".
+func DefaultSensitiveKeys() []string {
+	return []string{
+		"access_token",
+		"api_key",
+		"apikey",
+		"authorization",
+		"client_secret",
+		"cookie",
+		"password",
+		"private_key",
+		"refresh_token",
+		"secret",
+		"set-cookie",
+		"token",
+		"x-api-key",
+	}
+}
+
+// Redact returns a copy of this [JSONMapSlice] where the values of sensitive keys are replaced,
+// e.g. to safely log payloads derived from a spec.
+//
+// Keys are matched case-insensitively, at any depth, including in objects found inside arrays.
+// The whole value of a matching key is replaced, and the order of keys is preserved.
+//
+// When keys is nil, [DefaultSensitiveKeys] are redacted. When the replacement is nil, values are
+// replaced by [DefaultRedaction].
+//
+// The receiver is not mutated.
+func (s JSONMapSlice) Redact(keys []string, replacement any) JSONMapSlice {
+	if keys == nil {
+		keys = DefaultSensitiveKeys()
+	}
+	if replacement == nil {
+		replacement = DefaultRedaction
+	}
+
+	sensitive := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		sensitive[strings.ToLower(key)] = struct{}{}
+	}
+
+	return s.redact(sensitive, replacement)
+}
+
+func (s JSONMapSlice) redact(sensitive map[string]struct{}, replacement any) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	for i := range s {
+		result[i] = JSONMapItem{Key: s[i].Key, Comment: s[i].Comment}
+		if _, found := sensitive[strings.ToLower(s[i].Key)]; found {
+			result[i].Value = replacement
+
+			continue
+		}
+
+		result[i].Value = redactValue(s[i].Value, sensitive, replacement)
+	}
+
+	return result
+}
+
+func redactValue(value any, sensitive map[string]struct{}, replacement any) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		return v.redact(sensitive, replacement)
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = redactValue(elem, sensitive, replacement)
+		}
+
+		return result
+	default:
+		return value
+	}
+}
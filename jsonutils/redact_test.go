@@ -0,0 +1,95 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceRedact(t *testing.T) {
+	const sd = `{
+  "user": "me",
+  "request": {
+    "headers": {
+      "Authorization": "Bearer abc",
+      "Accept": "application/json"
+    },
+    "body": {
+      "items": [
+        {"id": 1, "token": "xyz"},
+        {"id": 2, "nested": {"TOKEN": {"value": "deep"}}}
+      ]
+    }
+  },
+  "token": null
+}`
+
+	var data JSONMapSlice
+	require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+
+	t.Run("should redact keys at any depth", func(t *testing.T) {
+		redacted := data.Redact([]string{"token", "authorization"}, nil)
+
+		jazon, err := redacted.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t,
+			`{"user":"me","request":{"headers":{"Authorization":"***","Accept":"application/json"},`+
+				`"body":{"items":[{"id":1,"token":"***"},{"id":2,"nested":{"TOKEN":"***"}}]}},"token":"***"}`,
+			string(jazon),
+		)
+	})
+
+	t.Run("should redact with a custom replacement", func(t *testing.T) {
+		redacted := data.Redact([]string{"Accept"}, int64(0))
+
+		v, err := redacted.AtPointer("/request/headers/Accept")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), v)
+
+		v, err = redacted.AtPointer("/request/headers/Authorization")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer abc", v)
+	})
+
+	t.Run("should redact default sensitive keys", func(t *testing.T) {
+		redacted := JSONMapSlice{
+			{Key: "Password", Value: "secret"},
+			{Key: "name", Value: "x"},
+			{Key: "list", Value: []any{JSONMapSlice{{Key: "api_key", Value: "k"}}}},
+		}.Redact(nil, nil)
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "Password", Value: DefaultRedaction},
+			{Key: "name", Value: "x"},
+			{Key: "list", Value: []any{JSONMapSlice{{Key: "api_key", Value: DefaultRedaction}}}},
+		}, redacted)
+	})
+
+	t.Run("should not mutate the receiver", func(t *testing.T) {
+		_ = data.Redact([]string{"token", "authorization"}, nil)
+
+		v, err := data.AtPointer("/request/body/items/0/token")
+		require.NoError(t, err)
+		assert.Equal(t, "xyz", v)
+	})
+
+	t.Run("should not match array indices", func(t *testing.T) {
+		redacted := JSONMapSlice{{Key: "a", Value: []any{"x", "y"}}}.Redact([]string{"0"}, nil)
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: []any{"x", "y"}}}, redacted)
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// RefResolver resolves an external JSON reference, such as "definitions.json#/pet", into a [JSONMapSlice].
+type RefResolver func(ref string) (JSONMapSlice, error)
+
+// ResolveRefs returns a copy of this [JSONMapSlice] with all JSON references replaced by the values they refer to.
+//
+// A JSON reference is an object with a "$ref" key holding a string, e.g. {"$ref": "#/components/schemas/pet"}.
+// Like with JSON Reference and OpenAPI, any other key of such an object is ignored.
+//
+// References starting with "#" are JSON pointers resolved against this document, such as with [JSONMapSlice.AtPointer].
+// They may be percent-encoded, as in url fragments. All other references are passed as is to the resolver.
+// A nil resolver may be used for documents which have only internal references.
+//
+// Resolved values are in turn searched for references, which are resolved the same way. Internal references
+// found in an external fragment refer to the external document: they are passed to the resolver prefixed with
+// this document, e.g. "#/tag" found in the fragment of "definitions.json#/pet" is resolved as "definitions.json#/tag".
+// An error is returned for references which can't be resolved, and for cyclic references, which can't be inlined.
+//
+// Every reference is replaced by a copy of the value it refers to, so objects and arrays are not shared
+// within the result. The receiver is not mutated, except that lazy values along internal references are resolved in place.
+func (s JSONMapSlice) ResolveRefs(resolver RefResolver) (JSONMapSlice, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	r := refResolver{
+		root:     s,
+		resolver: resolver,
+		visiting: make(map[string]struct{}),
+	}
+
+	v, err := r.resolveObject(s, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := v.(JSONMapSlice)
+	if !ok {
+		// the root document is a reference to a value which is not an object
+		return nil, fmt.Errorf("cannot resolve the root document: it refers to a value of type %T: %w", v, ErrJSON)
+	}
+
+	return result, nil
+}
+
+type refResolver struct {
+	root     JSONMapSlice
+	base     string // the external document holding the values being resolved, if any
+	resolver RefResolver
+	visiting map[string]struct{}
+}
+
+func (r refResolver) resolveValue(value any, path string) (any, error) {
+	value, ok := resolveLazy(value)
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve refs at %q: invalid lazy value: %w", path, ErrJSON)
+	}
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		if v == nil {
+			return v, nil
+		}
+
+		return r.resolveObject(v, path)
+	case []any:
+		if v == nil {
+			return v, nil
+		}
+
+		result := make([]any, len(v))
+		for i, elem := range v {
+			resolved, err := r.resolveValue(elem, path+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+func (r refResolver) resolveObject(s JSONMapSlice, path string) (any, error) {
+	if ref, isRef := refOf(s); isRef {
+		return r.resolveRef(ref, path)
+	}
+
+	result := make(JSONMapSlice, len(s))
+	for i := range s {
+		childPath := path + "/" + escapePointerToken(s[i].Key)
+		resolved, err := r.resolveValue(s[i].Value, childPath)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = JSONMapItem{Key: s[i].Key, Value: resolved, Comment: s[i].Comment}
+	}
+
+	return result, nil
+}
+
+func (r refResolver) resolveRef(ref, path string) (any, error) {
+	if r.base != "" && strings.HasPrefix(ref, "#") {
+		ref = r.base + ref
+	}
+
+	if _, cyclic := r.visiting[ref]; cyclic {
+		return nil, fmt.Errorf("cannot resolve $ref %q at %q: cyclic reference: %w", ref, path, ErrJSON)
+	}
+
+	var (
+		target any
+		err    error
+	)
+
+	if fragment, isInternal := strings.CutPrefix(ref, "#"); isInternal {
+		target, err = r.resolveInternal(fragment)
+	} else {
+		target, err = r.resolveExternal(ref)
+		r.base, _, _ = strings.Cut(ref, "#")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve $ref %q at %q: %w", ref, path, err)
+	}
+
+	r.visiting[ref] = struct{}{}
+	defer delete(r.visiting, ref)
+
+	return r.resolveValue(target, path)
+}
+
+func (r refResolver) resolveInternal(fragment string) (any, error) {
+	pointer, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url fragment: %w: %w", err, ErrJSON)
+	}
+
+	return r.root.AtPointer(pointer)
+}
+
+func (r refResolver) resolveExternal(ref string) (any, error) {
+	if r.resolver == nil {
+		return nil, fmt.Errorf("external references are not supported without a resolver: %w", ErrJSON)
+	}
+
+	fragment, err := r.resolver(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return fragment, nil
+}
+
+// refOf tells if an object is a JSON reference, and returns this reference.
+func refOf(s JSONMapSlice) (string, bool) {
+	for i := range s {
+		if s[i].Key == "$ref" {
+			ref, ok := s[i].Value.(string)
+
+			return ref, ok
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,222 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceResolveRefs(t *testing.T) {
+	parse := func(t *testing.T, sd string) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		return data
+	}
+
+	marshal := func(t *testing.T, data JSONMapSlice) string {
+		t.Helper()
+
+		jazon, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		return string(jazon)
+	}
+
+	t.Run("should resolve local refs against the root document", func(t *testing.T) {
+		const sd = `{"paths":{"/pets":{"get":{"responses":{"200":{"schema":{"type":"array","items":{"$ref":"#/components/schemas/pet"}}},` +
+			`"default":{"$ref":"#/components/responses/error","description":"ignored"}}}}},` +
+			`"components":{"schemas":{"pet":{"type":"object","properties":{"tag":{"$ref":"#/components/schemas/a~1tag%20name"}}},` +
+			`"a/tag name":{"type":"string"}},"responses":{"error":{"description":"error"}}}}`
+		data := parse(t, sd)
+
+		resolved, err := data.ResolveRefs(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			`{"paths":{"/pets":{"get":{"responses":{"200":{"schema":{"type":"array","items":`+
+				`{"type":"object","properties":{"tag":{"type":"string"}}}}},"default":{"description":"error"}}}}},`+
+				`"components":{"schemas":{"pet":{"type":"object","properties":{"tag":{"type":"string"}}},`+
+				`"a/tag name":{"type":"string"}},"responses":{"error":{"description":"error"}}}}`,
+			marshal(t, resolved),
+		)
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			assert.Equal(t, sd, marshal(t, data))
+		})
+
+		t.Run("should not share resolved fragments", func(t *testing.T) {
+			tag, err := resolved.AtPointer("/components/schemas/pet/properties/tag")
+			require.NoError(t, err)
+			tag.(JSONMapSlice)[0].Value = "integer"
+
+			other, err := resolved.AtPointer("/components/schemas/a~1tag name/type")
+			require.NoError(t, err)
+			assert.Equal(t, "string", other)
+		})
+	})
+
+	t.Run("should resolve external refs with the resolver", func(t *testing.T) {
+		data := parse(t, `{"definitions":{"pet":{"$ref":"pet.json"},"list":[{"$ref":"common.json#/error"},{"$ref":"#/definitions/pet"}]}}`)
+		external := map[string]JSONMapSlice{
+			"pet.json":           parse(t, `{"type":"object","properties":{"owner":{"$ref":"owner.json"}}}`),
+			"owner.json":         parse(t, `{"type":"string"}`),
+			"common.json#/error": parse(t, `{"type":"object","description":"an error"}`),
+		}
+
+		var calls []string
+		resolved, err := data.ResolveRefs(func(ref string) (JSONMapSlice, error) {
+			calls = append(calls, ref)
+			fragment, ok := external[ref]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+
+			return fragment, nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			`{"definitions":{"pet":{"type":"object","properties":{"owner":{"type":"string"}}},`+
+				`"list":[{"type":"object","description":"an error"},{"type":"object","properties":{"owner":{"type":"string"}}}]}}`,
+			marshal(t, resolved),
+		)
+		assert.Equal(t, []string{"pet.json", "owner.json", "common.json#/error", "pet.json", "owner.json"}, calls)
+
+		t.Run("should resolve local refs in external fragments against their own document", func(t *testing.T) {
+			data := parse(t, `{"pet":{"$ref":"definitions.json#/definitions/pet"},"definitions":{"tag":{"type":"integer"}}}`)
+			external := map[string]JSONMapSlice{
+				"definitions.json#/definitions/pet": parse(t, `{"type":"object","properties":{"tag":{"$ref":"#/definitions/tag"}}}`),
+				"definitions.json#/definitions/tag": parse(t, `{"type":"string","example":{"$ref":"#/examples/tag"}}`),
+				"definitions.json#/examples/tag":    parse(t, `{"value":"dog"}`),
+			}
+
+			var calls []string
+			resolved, err := data.ResolveRefs(func(ref string) (JSONMapSlice, error) {
+				calls = append(calls, ref)
+				fragment, ok := external[ref]
+				if !ok {
+					return nil, errors.New("not found")
+				}
+
+				return fragment, nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t,
+				`{"pet":{"type":"object","properties":{"tag":{"type":"string","example":{"value":"dog"}}}},`+
+					`"definitions":{"tag":{"type":"integer"}}}`,
+				marshal(t, resolved),
+			)
+			assert.Equal(t, []string{
+				"definitions.json#/definitions/pet", "definitions.json#/definitions/tag", "definitions.json#/examples/tag",
+			}, calls)
+
+			t.Run("should fail on cyclic local refs in external fragments", func(t *testing.T) {
+				_, err := data.ResolveRefs(func(string) (JSONMapSlice, error) {
+					return parse(t, `{"next":{"$ref":"#/definitions/pet"}}`), nil
+				})
+				require.ErrorIs(t, err, ErrJSON)
+				assert.Contains(t, err.Error(), "cyclic reference")
+			})
+		})
+
+		t.Run("should fail when the resolver fails", func(t *testing.T) {
+			_, err := parse(t, `{"a":[{"$ref":"unknown.json"}]}`).ResolveRefs(func(string) (JSONMapSlice, error) {
+				return nil, errors.New("not found")
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `cannot resolve $ref "unknown.json" at "/a/0": not found`)
+		})
+	})
+
+	t.Run("should fail on cyclic refs", func(t *testing.T) {
+		for _, sd := range []string{
+			`{"a":{"$ref":"#/a"}}`,
+			`{"a":{"b":{"$ref":"#/c"}},"c":{"d":[{"$ref":"#/a"}]}}`,
+			`{"node":{"type":"object","properties":{"next":{"$ref":"#/node"}}}}`,
+		} {
+			_, err := parse(t, sd).ResolveRefs(nil)
+			require.Errorf(t, err, "expected an error for %s", sd)
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Contains(t, err.Error(), "cyclic reference")
+		}
+
+		t.Run("should fail on external cyclic refs", func(t *testing.T) {
+			_, err := parse(t, `{"a":{"$ref":"a.json"}}`).ResolveRefs(func(string) (JSONMapSlice, error) {
+				return JSONMapSlice{{Key: "b", Value: JSONMapSlice{{Key: "$ref", Value: "a.json"}}}}, nil
+			})
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Contains(t, err.Error(), "cyclic reference")
+		})
+
+		t.Run("should resolve the same ref several times when not cyclic", func(t *testing.T) {
+			resolved, err := parse(t, `{"a":{"$ref":"#/c"},"b":[{"$ref":"#/c"},{"$ref":"#/a"}],"c":1}`).ResolveRefs(nil)
+			require.NoError(t, err)
+			assert.Equal(t, `{"a":1,"b":[1,1],"c":1}`, marshal(t, resolved))
+		})
+	})
+
+	t.Run("should fail on unresolved refs", func(t *testing.T) {
+		for _, sd := range []string{
+			`{"a":{"$ref":"#/missing"}}`,
+			`{"a":{"$ref":"#invalid"}}`,
+			`{"a":{"$ref":"#/%zz"}}`,
+			`{"a":{"$ref":"external.json"}}`,
+			`{"$ref":"#/a","a":1}`,
+		} {
+			resolved, err := parse(t, sd).ResolveRefs(nil)
+			require.Errorf(t, err, "expected an error for %s", sd)
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Nil(t, resolved)
+		}
+	})
+
+	t.Run("should ignore keys named $ref which are not references", func(t *testing.T) {
+		const sd = `{"properties":{"$ref":{"type":"string"}},"lazy":{"x":[{"$ref":"#/properties"}]}}`
+
+		resolved, err := parse(t, sd).ResolveRefs(nil)
+		require.NoError(t, err)
+		assert.Equal(t, `{"properties":{"$ref":{"type":"string"}},"lazy":{"x":[{"$ref":{"type":"string"}}]}}`, marshal(t, resolved))
+
+		t.Run("with lazy values", func(t *testing.T) {
+			var lazy JSONMapSlice
+			require.NoError(t, lazy.UnmarshalJSONWithOptions([]byte(sd), WithLazyNested(true)))
+
+			resolvedLazy, err := lazy.ResolveRefs(nil)
+			require.NoError(t, err)
+			assert.Equal(t, resolved, resolvedLazy)
+		})
+	})
+
+	t.Run("should resolve nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		resolved, err := empty.ResolveRefs(nil)
+		require.NoError(t, err)
+		assert.Nil(t, resolved)
+
+		resolved, err = JSONMapSlice{}.ResolveRefs(nil)
+		require.NoError(t, err)
+		assert.Equal(t, JSONMapSlice{}, resolved)
+	})
+}
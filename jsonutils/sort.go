@@ -0,0 +1,142 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import "sort"
+
+// SortKeys returns a copy of this [JSONMapSlice] with its keys sorted in lexicographic order.
+//
+// Only top-level keys are sorted: values are shared with the receiver, which is not mutated.
+// Duplicate keys retain their relative order.
+func (s JSONMapSlice) SortKeys() JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	copy(result, s)
+	result.sortKeys()
+
+	return result
+}
+
+// SortBy returns a copy of this [JSONMapSlice] with its items sorted by a custom comparison function.
+//
+// The function reports whether item a should come before item b. The sort is stable, so items which
+// compare as equal retain their relative order. This generalizes [JSONMapSlice.SortKeys].
+//
+// Only top-level items are sorted: values are shared with the receiver, which is not mutated.
+func (s JSONMapSlice) SortBy(less func(a, b JSONMapItem) bool) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	copy(result, s)
+	sort.SliceStable(result, func(i, j int) bool {
+		return less(result[i], result[j])
+	})
+
+	return result
+}
+
+// SortKeysRecursive returns a deep copy of this [JSONMapSlice] with the keys of all objects
+// sorted in lexicographic order, which produces a canonical form of the JSON object.
+//
+// Nested objects are sorted too, including objects found inside arrays.
+// The order of array elements is left unchanged.
+//
+// The receiver is not mutated.
+func (s JSONMapSlice) SortKeysRecursive() JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	for i, item := range s {
+		result[i] = JSONMapItem{Key: item.Key, Value: sortValue(item.Value), Comment: item.Comment}
+	}
+	result.sortKeys()
+
+	return result
+}
+
+// ReorderByTemplate returns a copy of this [JSONMapSlice] with its keys ordered like in a template.
+//
+// Keys listed in the template come first, in the order of the template. Keys which are not
+// listed come afterwards, in their original relative order. Keys of the template which are
+// absent from the object are ignored.
+//
+// This is useful to render documents with a conventional order of keys, e.g. "openapi", "info", "paths"
+// for OpenAPI specs.
+//
+// Only top-level keys are reordered: values are shared with the receiver, which is not mutated.
+// Duplicate keys retain their relative order.
+func (s JSONMapSlice) ReorderByTemplate(order []string) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		if _, found := rank[key]; !found {
+			rank[key] = i
+		}
+	}
+
+	rankOf := func(key string) int {
+		if r, found := rank[key]; found {
+			return r
+		}
+
+		return len(order)
+	}
+
+	result := make(JSONMapSlice, len(s))
+	copy(result, s)
+	sort.SliceStable(result, func(i, j int) bool {
+		return rankOf(result[i].Key) < rankOf(result[j].Key)
+	})
+
+	return result
+}
+
+func (s JSONMapSlice) sortKeys() {
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].Key < s[j].Key
+	})
+}
+
+func sortValue(value any) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		return v.SortKeysRecursive()
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = sortValue(elem)
+		}
+
+		return result
+	default:
+		return cloneValue(value)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceSortKeys(t *testing.T) {
+	const sd = `{"b":{"z":1,"y":[3,1,{"q":1,"p":2}]},"c":2,"a":[{"k":1,"j":2},"x"],"B":true,"a":"dup"}`
+
+	parse := func(t *testing.T, sd string) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		return data
+	}
+
+	marshal := func(t *testing.T, data JSONMapSlice) string {
+		t.Helper()
+
+		jazon, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		return string(jazon)
+	}
+
+	t.Run("should sort top-level keys", func(t *testing.T) {
+		data := parse(t, sd)
+
+		sorted := data.SortKeys()
+		assert.Equal(t, `{"B":true,"a":[{"k":1,"j":2},"x"],"a":"dup","b":{"z":1,"y":[3,1,{"q":1,"p":2}]},"c":2}`, marshal(t, sorted))
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			assert.Equal(t, sd, marshal(t, data))
+		})
+	})
+
+	t.Run("should sort keys recursively", func(t *testing.T) {
+		data := parse(t, sd)
+
+		sorted := data.SortKeysRecursive()
+		assert.Equal(t, `{"B":true,"a":[{"j":2,"k":1},"x"],"a":"dup","b":{"y":[3,1,{"p":2,"q":1}],"z":1},"c":2}`, marshal(t, sorted))
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			assert.Equal(t, sd, marshal(t, data))
+
+			b, _ := sorted.Get("b")
+			b.(JSONMapSlice)[0].Value = "changed"
+			assert.Equal(t, sd, marshal(t, data))
+		})
+	})
+
+	t.Run("should be deterministic", func(t *testing.T) {
+		l := parse(t, `{"c":{"e":1,"d":2},"a":1,"b":[2,1]}`)
+		r := parse(t, `{"b":[2,1],"c":{"d":2,"e":1},"a":1}`)
+
+		assert.Equal(t, marshal(t, l.SortKeysRecursive()), marshal(t, r.SortKeysRecursive()))
+		assert.Equal(t, `{"a":1,"b":[2,1],"c":{"d":2,"e":1}}`, marshal(t, l.SortKeysRecursive()))
+		assert.Equal(t, marshal(t, l.SortKeysRecursive()), marshal(t, l.SortKeysRecursive().SortKeysRecursive()))
+	})
+
+	t.Run("should sort nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		assert.Nil(t, empty.SortKeys())
+		assert.Nil(t, empty.SortKeysRecursive())
+		assert.Equal(t, JSONMapSlice{}, JSONMapSlice{}.SortKeys())
+		assert.Equal(t, JSONMapSlice{}, JSONMapSlice{}.SortKeysRecursive())
+	})
+}
+
+func TestJSONMapSliceReorderByTemplate(t *testing.T) {
+	const sd = `{"x-custom":true,"paths":{"/b":{},"/a":{}},"info":{"title":"t"},"components":{},"openapi":"3.1.0","x-other":1,"info":"dup"}`
+	template := []string{"openapi", "info", "servers", "paths", "components"}
+
+	var data JSONMapSlice
+	require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+	t.Run("should put keys in the order of the template, then other keys in their original order", func(t *testing.T) {
+		reordered := data.ReorderByTemplate(template)
+
+		jazon, err := json.Marshal(reordered)
+		require.NoError(t, err)
+		assert.Equal(t,
+			`{"openapi":"3.1.0","info":{"title":"t"},"info":"dup","paths":{"/b":{},"/a":{}},"components":{},"x-custom":true,"x-other":1}`,
+			string(jazon),
+			"only top-level keys should be reordered, and duplicate keys should retain their relative order",
+		)
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			jazon, err := json.Marshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, sd, string(jazon))
+		})
+	})
+
+	t.Run("with keys absent from the template", func(t *testing.T) {
+		reordered := data.ReorderByTemplate([]string{"none", "paths", "paths"})
+		keys := make([]string, 0, len(reordered))
+		for _, item := range reordered {
+			keys = append(keys, item.Key)
+		}
+		assert.Equal(t, []string{"paths", "x-custom", "info", "components", "openapi", "x-other", "info"}, keys)
+
+		assert.Equal(t, data, data.ReorderByTemplate(nil))
+	})
+
+	t.Run("should reorder nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		assert.Nil(t, empty.ReorderByTemplate(template))
+		assert.Equal(t, JSONMapSlice{}, JSONMapSlice{}.ReorderByTemplate(template))
+	})
+}
+
+func TestJSONMapSliceSortBy(t *testing.T) {
+	const sd = `{"b":{"x":1,"y":2},"c":[1],"a":{"z":1},"d":"s","a":{}}`
+
+	var data JSONMapSlice
+	require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+	marshal := func(t *testing.T, data JSONMapSlice) string {
+		t.Helper()
+
+		jazon, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		return string(jazon)
+	}
+
+	t.Run("should sort by key descending", func(t *testing.T) {
+		sorted := data.SortBy(func(a, b JSONMapItem) bool { return a.Key > b.Key })
+		assert.Equal(t, `{"d":"s","c":[1],"b":{"x":1,"y":2},"a":{"z":1},"a":{}}`, marshal(t, sorted),
+			"duplicate keys should retain their relative order",
+		)
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			assert.Equal(t, sd, marshal(t, data))
+		})
+	})
+
+	t.Run("should sort by a value-derived criterion", func(t *testing.T) {
+		size := func(item JSONMapItem) int {
+			switch v := item.Value.(type) {
+			case JSONMapSlice:
+				return len(v)
+			case []any:
+				return len(v)
+			default:
+				return -1
+			}
+		}
+
+		sorted := data.SortBy(func(a, b JSONMapItem) bool { return size(a) < size(b) })
+		assert.Equal(t, `{"d":"s","a":{},"c":[1],"a":{"z":1},"b":{"x":1,"y":2}}`, marshal(t, sorted),
+			"items of equal size should retain their relative order",
+		)
+	})
+
+	t.Run("should generalize SortKeys", func(t *testing.T) {
+		assert.Equal(t, data.SortKeys(), data.SortBy(func(a, b JSONMapItem) bool { return a.Key < b.Key }))
+	})
+
+	t.Run("should sort nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+		never := func(_, _ JSONMapItem) bool { return false }
+
+		assert.Nil(t, empty.SortBy(never))
+		assert.Equal(t, JSONMapSlice{}, JSONMapSlice{}.SortBy(never))
+	})
+}
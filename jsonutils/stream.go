@@ -0,0 +1,184 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// UnmarshalReader builds a [JSONMapSlice] from a stream of JSON bytes, preserving the order of keys.
+//
+// It behaves like [JSONMapSlice.UnmarshalJSONWithOptions], but reads directly from the reader,
+// without buffering the whole input first.
+func UnmarshalReader(r io.Reader, opts ...Option) (JSONMapSlice, error) {
+	d := newJSONDecoder(r, optionsWithDefaults(opts).decodeOptions)
+
+	return d.decodeDocument()
+}
+
+// UnmarshalContext builds a [JSONMapSlice] from a stream of JSON bytes, like [UnmarshalReader],
+// and aborts decoding when the context is done.
+//
+// The context is checked before reading every token, so decoding a large or slow stream stops promptly
+// after the context is cancelled. The error returned is then the error of the context.
+func UnmarshalContext(ctx context.Context, r io.Reader, opts ...Option) (JSONMapSlice, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d := newJSONDecoder(r, optionsWithDefaults(opts).decodeOptions)
+	d.ctx = ctx
+
+	return d.decodeDocument()
+}
+
+// DecodeStream decodes a JSON object from a reader and invokes a callback for every top-level key,
+// in the order of the document.
+//
+// The callback is called as soon as each key-value pair is parsed: the whole object is never
+// held in memory. Inner objects are unmarshaled as [JSONMapSlice] slices, like with [JSONMapSlice.UnmarshalJSON].
+//
+// Decoding stops as soon as the callback returns an error, and this error is returned.
+func DecodeStream(r io.Reader, fn func(key string, value any) error) error {
+	d := newJSONDecoder(r, defaultDecodeOptions())
+	isNull, err := d.startObject()
+	if err != nil || isNull {
+		return err
+	}
+
+	d.decodeObject(func(mi JSONMapItem) error {
+		return fn(mi.Key, mi.Value)
+	})
+
+	return d.err
+}
+
+// DecodeAll reads a sequence of concatenated [JSONMapSlice] objects from a reader, such as a log stream of JSON objects.
+//
+// Objects may be separated by white space, or not separated at all, e.g. `{"a":1}{"b":2}`.
+// Every object is decoded like with [UnmarshalReader]: a null value yields a nil object,
+// and limits such as [WithMaxTotalNodes] apply to every object separately.
+//
+// Decoding stops cleanly at the end of the input. An incomplete last object, or a value which is not
+// an object, yields an error wrapping a [ParseError], which reports the position of the object in the stream,
+// starting at 1.
+// The objects decoded before an error are not returned.
+func DecodeAll(r io.Reader, opts ...Option) ([]JSONMapSlice, error) {
+	d := newJSONDecoder(r, optionsWithDefaults(opts).decodeOptions)
+
+	var items []JSONMapSlice
+	for {
+		item, done, err := d.decodeNext()
+		if err != nil {
+			return nil, fmt.Errorf("object %d: %w", len(items)+1, err)
+		}
+		if done {
+			return items, nil
+		}
+
+		items = append(items, item)
+	}
+}
+
+// decodeNext decodes the next JSON object of a stream, or null, and reports whether the end of the input is reached.
+func (d *jsonDecoder) decodeNext() (JSONMapSlice, bool, error) {
+	d.depth, d.nodes = 0, 0
+
+	t, err := d.decoder.Token()
+	if err == io.EOF {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, asParseError(d.decoder, err)
+	}
+	if t == nil {
+		return nil, false, nil
+	}
+
+	if del, ok := t.(json.Delim); !ok || del != '{' {
+		return nil, false, newParseError(d.decoder, "a JSON object", t)
+	}
+	if !d.countNode(t) || !d.enterNested(t) {
+		return nil, false, d.err
+	}
+
+	var item JSONMapSlice
+	item.JSONunmarshal(d)
+	if d.err != nil {
+		return nil, false, d.err
+	}
+
+	return item, false, nil
+}
+
+// EncodeNDJSON writes a sequence of [JSONMapSlice] objects as newline-delimited JSON (NDJSON):
+// every object is rendered as compact JSON on its own line, followed by a newline.
+//
+// Objects are written incrementally to the writer, like with [JSONMapSlice.EncodeTo].
+// A nil object is rendered as a null line.
+func EncodeNDJSON(w io.Writer, items []JSONMapSlice, opts ...Option) error {
+	jw := newJSONWriter(w, optionsWithDefaults(opts).encodeOptions)
+
+	for _, item := range items {
+		item.JSONmarshal(jw)
+		if jw.err != nil {
+			return jw.err
+		}
+
+		jw.appendRawByte('\n')
+		jw.flushIfFull()
+	}
+
+	jw.flush()
+
+	return jw.err
+}
+
+// DecodeNDJSON reads a sequence of [JSONMapSlice] objects from newline-delimited JSON (NDJSON),
+// where every line holds one JSON object.
+//
+// Blank lines are skipped, and the last line may or may not end with a newline.
+// Every line is decoded like with [JSONMapSlice.UnmarshalJSONWithOptions]: a null line yields a nil object.
+//
+// Errors report the line number, starting at 1. The objects decoded before an error are not returned.
+func DecodeNDJSON(r io.Reader, opts ...Option) ([]JSONMapSlice, error) {
+	br := bufio.NewReader(r)
+
+	var items []JSONMapSlice
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var item JSONMapSlice
+			if decodeErr := item.UnmarshalJSONWithOptions(trimmed, opts...); decodeErr != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, decodeErr)
+			}
+
+			items = append(items, item)
+		}
+
+		if err == io.EOF {
+			return items, nil
+		}
+	}
+}
@@ -0,0 +1,436 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalReader(t *testing.T) {
+	const sd = `{"a":1,"b":{"c":[true,"x",{"d":null}]},"e":"a longer string value with \"escaped\" characters"}`
+
+	t.Run("should unmarshal from a reader", func(t *testing.T) {
+		var expected JSONMapSlice
+		require.NoError(t, expected.UnmarshalJSON([]byte(sd)))
+
+		for _, fixture := range []struct {
+			Title  string
+			Reader io.Reader
+		}{
+			{Title: "with plain reader", Reader: strings.NewReader(sd)},
+			{Title: "with one byte chunks", Reader: iotest.OneByteReader(strings.NewReader(sd))},
+			{Title: "with half chunks", Reader: iotest.HalfReader(strings.NewReader(sd))},
+			{Title: "with small chunks", Reader: &chunkedReader{data: []byte(sd), size: 3}},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				data, err := UnmarshalReader(fixture.Reader)
+				require.NoError(t, err)
+				assert.Equal(t, expected, data)
+
+				jazon, err := data.MarshalJSON()
+				require.NoError(t, err)
+				assert.Equal(t, sd, string(jazon))
+			})
+		}
+	})
+
+	t.Run("should unmarshal from a reader with options", func(t *testing.T) {
+		data, err := UnmarshalReader(
+			iotest.OneByteReader(strings.NewReader(`{"a":1.0,"a":2}`)),
+			WithUseNumber(true), WithDuplicateKeyPolicy(DuplicateKeyFirst),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: json.Number("1.0")}}, data)
+	})
+
+	t.Run("should unmarshal null or empty input", func(t *testing.T) {
+		for _, sd := range []string{``, `null`} {
+			data, err := UnmarshalReader(strings.NewReader(sd))
+			require.NoError(t, err)
+			assert.Nil(t, data)
+		}
+	})
+
+	t.Run("should fail on invalid input", func(t *testing.T) {
+		for _, sd := range []string{`[1,2]`, `{"a":1`, `{"a":|}`} {
+			data, err := UnmarshalReader(&chunkedReader{data: []byte(sd), size: 2})
+			require.Error(t, err)
+			assert.Nil(t, data)
+		}
+	})
+
+	t.Run("should reject trailing content unless WithAllowTrailingContent", func(t *testing.T) {
+		const sd = `{"a":1}{"b":2}`
+
+		_, err := UnmarshalReader(iotest.OneByteReader(strings.NewReader(sd)))
+		require.ErrorIs(t, err, ErrJSON)
+
+		data, err := UnmarshalReader(iotest.OneByteReader(strings.NewReader(sd)), WithAllowTrailingContent(true))
+		require.NoError(t, err)
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: int64(1)}}, data)
+	})
+
+	t.Run("should fail on reader error", func(t *testing.T) {
+		_, err := UnmarshalReader(iotest.ErrReader(errTestWriter))
+		require.ErrorIs(t, err, errTestWriter)
+
+		_, err = UnmarshalReader(io.MultiReader(strings.NewReader(`{"a":`), iotest.ErrReader(errTestWriter)))
+		require.ErrorIs(t, err, errTestWriter)
+	})
+}
+
+func TestUnmarshalContext(t *testing.T) {
+	large := makeLargeMapSlice(1000)
+	jazon, err := large.MarshalJSON()
+	require.NoError(t, err)
+
+	t.Run("should unmarshal with a live context", func(t *testing.T) {
+		data, err := UnmarshalContext(context.Background(), bytes.NewReader(jazon))
+		require.NoError(t, err)
+		assert.True(t, data.Equal(large))
+	})
+
+	t.Run("should abort when the context is cancelled mid-parse", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r := &cancellingReader{r: iotest.OneByteReader(bytes.NewReader(jazon)), cancel: cancel, after: 50}
+		data, err := UnmarshalContext(ctx, r)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, data)
+
+		// decoding stopped shortly after the cancellation, without consuming the whole input
+		assert.Less(t, r.reads, 100)
+	})
+
+	t.Run("should not start with a done context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := UnmarshalContext(ctx, strings.NewReader(`{"a":1}`))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("should abort on deadline", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		_, err := UnmarshalContext(ctx, bytes.NewReader(jazon))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// cancellingReader cancels a context after a number of reads.
+type cancellingReader struct {
+	r      io.Reader
+	cancel context.CancelFunc
+	after  int
+	reads  int
+}
+
+func (r *cancellingReader) Read(p []byte) (int, error) {
+	r.reads++
+	if r.reads == r.after {
+		r.cancel()
+	}
+
+	return r.r.Read(p)
+}
+
+// chunkedReader returns data in chunks of a small size.
+type chunkedReader struct {
+	data []byte
+	size int
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+
+	if len(p) > r.size {
+		p = p[:r.size]
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+func TestDecodeStream(t *testing.T) {
+	t.Run("should decode top-level keys in order", func(t *testing.T) {
+		const sd = `{"a":1,"b":{"c":[true,"x"]},"d":null}`
+
+		var keys []string
+		var values []any
+		require.NoError(t, DecodeStream(strings.NewReader(sd), func(key string, value any) error {
+			keys = append(keys, key)
+			values = append(values, value)
+
+			return nil
+		}))
+
+		assert.Equal(t, []string{"a", "b", "d"}, keys)
+		assert.Equal(t, []any{
+			int64(1),
+			JSONMapSlice{{Key: "c", Value: []any{true, "x"}}},
+			nil,
+		}, values)
+	})
+
+	t.Run("should decode a large object", func(t *testing.T) {
+		const size = 10000
+		data := makeLargeMapSlice(size)
+
+		pr, pw := io.Pipe()
+		go func() {
+			_ = pw.CloseWithError(data.EncodeTo(pw))
+		}()
+
+		var count int
+		require.NoError(t, DecodeStream(pr, func(key string, value any) error {
+			expected := "key" + strconv.Itoa(count)
+			if key != expected {
+				return errors.New("unexpected key: " + key)
+			}
+			if _, ok := value.(JSONMapSlice); !ok {
+				return errors.New("unexpected value")
+			}
+			count++
+
+			return nil
+		}))
+		assert.Equal(t, size, count)
+	})
+
+	t.Run("should stop when the callback returns an error", func(t *testing.T) {
+		const sd = `{"a":1,"b":2,"c":3}`
+		errStop := errors.New("stop")
+
+		var keys []string
+		err := DecodeStream(strings.NewReader(sd), func(key string, _ any) error {
+			keys = append(keys, key)
+			if key == "b" {
+				return errStop
+			}
+
+			return nil
+		})
+		require.ErrorIs(t, err, errStop)
+		assert.Equal(t, []string{"a", "b"}, keys)
+	})
+
+	t.Run("should not call back on null or empty input", func(t *testing.T) {
+		for _, sd := range []string{``, `null`, `{}`} {
+			require.NoError(t, DecodeStream(strings.NewReader(sd), func(_ string, _ any) error {
+				return errors.New("unexpected call")
+			}))
+		}
+	})
+
+	t.Run("should fail on invalid input", func(t *testing.T) {
+		for _, sd := range []string{`[1,2]`, `{"a":1`, `{"a":|}`} {
+			require.Error(t, DecodeStream(strings.NewReader(sd), func(_ string, _ any) error {
+				return nil
+			}))
+		}
+	})
+}
+
+func TestNDJSON(t *testing.T) {
+	items := []JSONMapSlice{
+		{{Key: "z", Value: int64(1)}, {Key: "a", Value: "x\ny"}},
+		{{Key: "b", Value: JSONMapSlice{{Key: "c", Value: []any{true, nil}}}}},
+		{},
+	}
+	const expected = `{"z":1,"a":"x\ny"}` + "\n" + `{"b":{"c":[true,null]}}` + "\n" + `{}` + "\n"
+
+	t.Run("should encode one object per line", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, EncodeNDJSON(&buf, items))
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("should round-trip objects", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, EncodeNDJSON(&buf, items))
+
+		decoded, err := DecodeNDJSON(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, items, decoded)
+	})
+
+	t.Run("should encode nothing for no object", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, EncodeNDJSON(&buf, nil))
+		assert.Empty(t, buf.String())
+
+		decoded, err := DecodeNDJSON(&buf)
+		require.NoError(t, err)
+		assert.Empty(t, decoded)
+	})
+
+	t.Run("should skip blank lines, with or without a trailing newline", func(t *testing.T) {
+		for _, input := range []string{
+			"\n\n" + `{"a":1}` + "\r\n  \n" + `{"b":2}`,
+			`{"a":1}` + "\n" + `{"b":2}` + "\n\n",
+		} {
+			decoded, err := DecodeNDJSON(iotest.OneByteReader(strings.NewReader(input)))
+			require.NoError(t, err)
+			assert.Equal(t, []JSONMapSlice{
+				{{Key: "a", Value: int64(1)}},
+				{{Key: "b", Value: int64(2)}},
+			}, decoded)
+		}
+	})
+
+	t.Run("should decode a null line as a nil object", func(t *testing.T) {
+		decoded, err := DecodeNDJSON(strings.NewReader("null\n{}\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []JSONMapSlice{nil, {}}, decoded)
+
+		var buf bytes.Buffer
+		require.NoError(t, EncodeNDJSON(&buf, decoded))
+		assert.Equal(t, "null\n{}\n", buf.String())
+	})
+
+	t.Run("should apply options", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, EncodeNDJSON(&buf, []JSONMapSlice{{{Key: "a", Value: "<&>"}}}, WithEscapeHTML(false)))
+		assert.Equal(t, `{"a":"<&>"}`+"\n", buf.String())
+
+		decoded, err := DecodeNDJSON(strings.NewReader(`{"a":1.50}`), WithUseNumber(true))
+		require.NoError(t, err)
+		assert.Equal(t, []JSONMapSlice{{{Key: "a", Value: json.Number("1.50")}}}, decoded)
+	})
+
+	t.Run("should encode many objects", func(t *testing.T) {
+		const size = 1000
+		many := make([]JSONMapSlice, size)
+		for i := range many {
+			many[i] = JSONMapSlice{{Key: "i", Value: int64(i)}}
+		}
+
+		w := &countingWriter{}
+		require.NoError(t, EncodeNDJSON(w, many))
+		assert.Greater(t, w.writes, 1)
+
+		decoded, err := DecodeNDJSON(strings.NewReader(w.String()))
+		require.NoError(t, err)
+		assert.Equal(t, many, decoded)
+	})
+
+	t.Run("with errors", func(t *testing.T) {
+		t.Run("should report the line of invalid input", func(t *testing.T) {
+			_, err := DecodeNDJSON(strings.NewReader(`{"a":1}` + "\n\n" + `{"b":}` + "\n"))
+			require.ErrorIs(t, err, ErrJSON)
+			assert.Contains(t, err.Error(), "line 3:")
+
+			_, err = DecodeNDJSON(strings.NewReader(`[1]`))
+			require.ErrorIs(t, err, ErrJSON)
+		})
+
+		t.Run("should reject several objects on the same line", func(t *testing.T) {
+			_, err := DecodeNDJSON(strings.NewReader(`{"a":1} {"b":2}`))
+			require.ErrorIs(t, err, ErrJSON)
+		})
+
+		t.Run("should return errors from the reader", func(t *testing.T) {
+			_, err := DecodeNDJSON(io.MultiReader(strings.NewReader("{}\n"), iotest.ErrReader(errTestWriter)))
+			require.ErrorIs(t, err, errTestWriter)
+		})
+
+		t.Run("should return errors from values", func(t *testing.T) {
+			var buf bytes.Buffer
+			require.Error(t, EncodeNDJSON(&buf, []JSONMapSlice{{{Key: "a", Value: make(chan int)}}}))
+		})
+
+		t.Run("should return errors from the writer", func(t *testing.T) {
+			require.ErrorIs(t, EncodeNDJSON(failingWriter{}, items), errTestWriter)
+		})
+	})
+}
+
+func TestDecodeAll(t *testing.T) {
+	expected := []JSONMapSlice{
+		{{Key: "a", Value: int64(1)}},
+		{{Key: "b", Value: JSONMapSlice{{Key: "c", Value: []any{true}}}}},
+	}
+
+	t.Run("should decode concatenated objects", func(t *testing.T) {
+		for _, input := range []string{
+			`{"a":1}` + "\n" + `{"b":{"c":[true]}}` + "\n",
+			`{"a":1}{"b":{"c":[true]}}`,
+			" \t" + `{"a":1}` + "\r\n\n " + `{"b":{"c":[true]}}`,
+		} {
+			decoded, err := DecodeAll(iotest.OneByteReader(strings.NewReader(input)))
+			require.NoError(t, err)
+			assert.Equal(t, expected, decoded)
+		}
+	})
+
+	t.Run("should decode nothing from an empty stream", func(t *testing.T) {
+		for _, input := range []string{"", " \n "} {
+			decoded, err := DecodeAll(strings.NewReader(input))
+			require.NoError(t, err)
+			assert.Empty(t, decoded)
+		}
+	})
+
+	t.Run("should decode null as a nil object", func(t *testing.T) {
+		decoded, err := DecodeAll(strings.NewReader(`null {} null`))
+		require.NoError(t, err)
+		assert.Equal(t, []JSONMapSlice{nil, {}, nil}, decoded)
+	})
+
+	t.Run("should apply options to every object", func(t *testing.T) {
+		decoded, err := DecodeAll(strings.NewReader(`{"a":1.50} {"b":[1]}`), WithUseNumber(true), WithMaxTotalNodes(3))
+		require.NoError(t, err)
+		assert.Equal(t, []JSONMapSlice{
+			{{Key: "a", Value: json.Number("1.50")}},
+			{{Key: "b", Value: []any{json.Number("1")}}},
+		}, decoded)
+	})
+
+	t.Run("should reject a partial last object", func(t *testing.T) {
+		var parseErr *ParseError
+
+		_, err := DecodeAll(strings.NewReader(`{"a":1}` + "\n" + `{"b":`))
+		require.ErrorAs(t, err, &parseErr)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.ErrorContains(t, err, "object 2")
+	})
+
+	t.Run("should reject values which are not objects", func(t *testing.T) {
+		for _, input := range []string{`{"a":1} [1]`, `{"a":1} 2`, `{"a":1}}`} {
+			_, err := DecodeAll(strings.NewReader(input))
+			require.ErrorIsf(t, err, ErrJSON, "expected %q to be rejected", input)
+			assert.ErrorContains(t, err, "object 2")
+		}
+	})
+}
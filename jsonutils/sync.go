@@ -0,0 +1,107 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import "sync"
+
+// SyncMap wraps a [JSONMapSlice] so that it may be shared and mutated by concurrent goroutines,
+// e.g. in services maintaining a shared ordered map.
+//
+// A zero SyncMap is an empty object ready to use. A SyncMap must not be copied after first use.
+//
+// Values returned by [SyncMap.Get] are shared with the map: they must not be mutated.
+// Use [SyncMap.Snapshot] to work on an independent copy.
+type SyncMap struct {
+	mu sync.RWMutex
+	s  JSONMapSlice
+}
+
+// NewSyncMap builds a [SyncMap] holding a deep copy of a [JSONMapSlice].
+func NewSyncMap(s JSONMapSlice) *SyncMap {
+	return &SyncMap{s: s.Clone()}
+}
+
+// Get returns the value associated to a key, like [JSONMapSlice.Get].
+func (m *SyncMap) Get(key string) (any, bool) {
+	m.mu.RLock()
+	i := m.s.index(key)
+	if i < 0 {
+		m.mu.RUnlock()
+
+		return nil, false
+	}
+	value := m.s[i].Value
+	m.mu.RUnlock()
+
+	if _, isLazy := value.(*LazyValue); !isLazy {
+		return value, true
+	}
+
+	// lazy values are resolved in place
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.s.Get(key)
+}
+
+// Has tells if a key exists, like [JSONMapSlice.Has].
+func (m *SyncMap) Has(key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.s.Has(key)
+}
+
+// Len returns the number of keys.
+func (m *SyncMap) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.s.Len()
+}
+
+// Set the value of a key, like [JSONMapSlice.Set].
+func (m *SyncMap) Set(key string, value any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.s.Set(key, value)
+}
+
+// Delete removes a key, like [JSONMapSlice.Delete].
+//
+// It returns true if the key was found.
+func (m *SyncMap) Delete(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.s.Delete(key)
+}
+
+// Snapshot returns a deep copy of the current content of the map.
+func (m *SyncMap) Snapshot() JSONMapSlice {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.s.Clone()
+}
+
+// MarshalJSON renders the current content of the map as JSON bytes, like [JSONMapSlice.MarshalJSON].
+func (m *SyncMap) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.s.MarshalJSON()
+}
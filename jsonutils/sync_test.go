@@ -0,0 +1,122 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncMap(t *testing.T) {
+	t.Run("should delegate to the JSONMapSlice", func(t *testing.T) {
+		source := JSONMapSlice{{Key: "a", Value: int64(1)}, {Key: "b", Value: []any{"x"}}}
+		m := NewSyncMap(source)
+
+		v, ok := m.Get("a")
+		require.True(t, ok)
+		assert.Equal(t, int64(1), v)
+		assert.True(t, m.Has("b"))
+		assert.False(t, m.Has("c"))
+
+		m.Set("c", true)
+		m.Set("a", int64(2))
+		assert.True(t, m.Delete("b"))
+		assert.False(t, m.Delete("b"))
+		assert.Equal(t, 2, m.Len())
+
+		jazon, err := m.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":2,"c":true}`, string(jazon))
+
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: int64(1)}, {Key: "b", Value: []any{"x"}}}, source,
+			"the source should not be mutated",
+		)
+	})
+
+	t.Run("should return an independent snapshot", func(t *testing.T) {
+		m := NewSyncMap(JSONMapSlice{{Key: "a", Value: JSONMapSlice{{Key: "b", Value: "c"}}}})
+
+		snapshot := m.Snapshot()
+		snapshot[0].Value.(JSONMapSlice)[0].Value = "changed"
+		snapshot.Set("d", "e")
+
+		jazon, err := m.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":{"b":"c"}}`, string(jazon))
+	})
+
+	t.Run("should be usable as a zero value", func(t *testing.T) {
+		var m SyncMap
+		_, ok := m.Get("a")
+		assert.False(t, ok)
+
+		m.Set("a", "b")
+		assert.Equal(t, JSONMapSlice{{Key: "a", Value: "b"}}, m.Snapshot())
+	})
+
+	t.Run("should resolve lazy values", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"a":{"b":1}}`), WithLazyNested(true)))
+		m := NewSyncMap(data)
+
+		v, ok := m.Get("a")
+		require.True(t, ok)
+		assert.Equal(t, JSONMapSlice{{Key: "b", Value: int64(1)}}, v)
+	})
+
+	t.Run("should support concurrent reads and writes", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSONWithOptions([]byte(`{"shared":1,"lazy":{"x":[1,2]}}`), WithLazyNested(true)))
+		m := NewSyncMap(data)
+
+		const workers = 8
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(2)
+
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					key := "k" + strconv.Itoa(w) + "-" + strconv.Itoa(i%10)
+					m.Set(key, i)
+					m.Set("shared", i)
+					if i%3 == 0 {
+						m.Delete(key)
+					}
+				}
+			}(w)
+
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					_, _ = m.Get("shared")
+					_, _ = m.Get("lazy")
+					_ = m.Has("k0-0")
+					_ = m.Len()
+					_, _ = m.MarshalJSON()
+					_ = m.Snapshot()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.True(t, m.Has("shared"))
+		assert.True(t, m.Has("lazy"))
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// TokenHandler receives the tokens of a JSON document from [Tokenize], in the order of the document.
+//
+// Every method may return an error to stop tokenizing: this error is then returned by [Tokenize].
+type TokenHandler interface {
+	// ObjectStart is called when a JSON object starts.
+	ObjectStart() error
+	// ObjectEnd is called when a JSON object ends.
+	ObjectEnd() error
+	// Key is called for every key of a JSON object, before its value.
+	Key(key string) error
+	// Value is called for every scalar value, with the same go types as those held by a [JSONMapSlice].
+	Value(value any) error
+	// ArrayStart is called when a JSON array starts.
+	ArrayStart() error
+	// ArrayEnd is called when a JSON array ends.
+	ArrayEnd() error
+}
+
+// Tokenize reads a JSON document from a reader and drives a [TokenHandler] with its tokens, SAX-style.
+//
+// This allows building custom processors without materializing the document as a [JSONMapSlice]:
+// keys are reported in the order of the document, and scalar values are converted like when unmarshaling
+// a [JSONMapSlice], e.g. integers are int64 values unless [WithUseNumber] is enabled.
+//
+// The document may hold any JSON value at the top level. Options about numbers, limits and trailing
+// content apply. Duplicate keys are always reported, regardless of the [DuplicateKeyPolicy].
+//
+// Invalid JSON yields a [ParseError].
+func Tokenize(r io.Reader, handler TokenHandler, opts ...Option) error {
+	d := newJSONDecoder(r, optionsWithDefaults(opts).decodeOptions)
+
+	t, ok := d.nextToken()
+	if !ok {
+		return d.err
+	}
+
+	d.tokenize(t, handler)
+	if d.err != nil {
+		return d.err
+	}
+
+	return d.endDocument()
+}
+
+// tokenize reports a JSON value starting with the current token to the handler.
+func (d *jsonDecoder) tokenize(t json.Token, h TokenHandler) {
+	if !d.countNode(t) {
+		return
+	}
+
+	switch v := t.(type) {
+	case json.Delim:
+		if !d.enterNested(v) {
+			return
+		}
+		defer d.leaveNested()
+
+		if v == '{' {
+			d.tokenizeObject(h)
+		} else {
+			d.tokenizeArray(h)
+		}
+	case json.Number:
+		n := d.asNumber(v)
+		if d.err != nil {
+			return
+		}
+		d.handleToken(h.Value(n))
+	default:
+		d.handleToken(h.Value(v))
+	}
+}
+
+func (d *jsonDecoder) tokenizeObject(h TokenHandler) {
+	if !d.handleToken(h.ObjectStart()) {
+		return
+	}
+
+	for keys := 1; ; keys++ {
+		t, ok := d.nextToken()
+		if !ok {
+			return
+		}
+		if del, ok := t.(json.Delim); ok && del == '}' {
+			d.handleToken(h.ObjectEnd())
+
+			return
+		}
+		if d.maxKeys > 0 && keys > d.maxKeys {
+			d.err = newParseError(d.decoder, fmt.Sprintf("at most %d keys in a JSON object", d.maxKeys), t)
+
+			return
+		}
+
+		key, ok := t.(string)
+		if !ok {
+			d.err = newParseError(d.decoder, "a JSON object key", t)
+
+			return
+		}
+		if !d.handleToken(h.Key(d.internKey(key))) {
+			return
+		}
+
+		if t, ok = d.nextToken(); !ok {
+			return
+		}
+		if d.tokenize(t, h); d.err != nil {
+			return
+		}
+	}
+}
+
+func (d *jsonDecoder) tokenizeArray(h TokenHandler) {
+	if !d.handleToken(h.ArrayStart()) {
+		return
+	}
+
+	for {
+		t, ok := d.nextToken()
+		if !ok {
+			return
+		}
+		if del, ok := t.(json.Delim); ok && del == ']' {
+			d.handleToken(h.ArrayEnd())
+
+			return
+		}
+		if d.tokenize(t, h); d.err != nil {
+			return
+		}
+	}
+}
+
+// handleToken records the error returned by a [TokenHandler], and reports whether tokenizing may continue.
+func (d *jsonDecoder) handleToken(err error) bool {
+	if err != nil {
+		d.err = err
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceHandler reconstructs a document from the tokens reported by [Tokenize].
+type sliceHandler struct {
+	stack  []any // *JSONMapSlice or *[]any
+	keys   []string
+	result any
+	events []string
+}
+
+func (h *sliceHandler) push(container any) {
+	h.stack = append(h.stack, container)
+	h.keys = append(h.keys, "")
+}
+
+func (h *sliceHandler) pop() error {
+	top := h.stack[len(h.stack)-1]
+	h.stack = h.stack[:len(h.stack)-1]
+	h.keys = h.keys[:len(h.keys)-1]
+
+	switch c := top.(type) {
+	case *JSONMapSlice:
+		return h.Value(*c)
+	case *[]any:
+		return h.Value(*c)
+	}
+
+	return nil
+}
+
+func (h *sliceHandler) ObjectStart() error {
+	h.events = append(h.events, "{")
+	h.push(&JSONMapSlice{})
+
+	return nil
+}
+
+func (h *sliceHandler) ObjectEnd() error {
+	h.events = append(h.events, "}")
+
+	return h.pop()
+}
+
+func (h *sliceHandler) ArrayStart() error {
+	h.events = append(h.events, "[")
+	h.push(&[]any{})
+
+	return nil
+}
+
+func (h *sliceHandler) ArrayEnd() error {
+	h.events = append(h.events, "]")
+
+	return h.pop()
+}
+
+func (h *sliceHandler) Key(key string) error {
+	h.events = append(h.events, "key:"+key)
+	h.keys[len(h.keys)-1] = key
+
+	return nil
+}
+
+func (h *sliceHandler) Value(value any) error {
+	if len(h.stack) == 0 {
+		h.result = value
+
+		return nil
+	}
+
+	switch c := h.stack[len(h.stack)-1].(type) {
+	case *JSONMapSlice:
+		*c = append(*c, JSONMapItem{Key: h.keys[len(h.keys)-1], Value: value})
+	case *[]any:
+		*c = append(*c, value)
+	}
+
+	return nil
+}
+
+func TestTokenize(t *testing.T) {
+	const doc = `{
+		"name": "test",
+		"count": 12,
+		"ratio": 1.5,
+		"enabled": true,
+		"missing": null,
+		"name": "duplicate",
+		"empty": {},
+		"nested": {"z": [1, "two", [3.5, false], {"a": null}], "a": []}
+	}`
+
+	t.Run("should reconstruct the same JSONMapSlice as direct parsing", func(t *testing.T) {
+		var expected JSONMapSlice
+		require.NoError(t, expected.UnmarshalJSON([]byte(doc)))
+
+		h := &sliceHandler{}
+		require.NoError(t, Tokenize(strings.NewReader(doc), h))
+		assert.Equal(t, expected, h.result)
+	})
+
+	t.Run("should report tokens in order", func(t *testing.T) {
+		h := &sliceHandler{}
+		require.NoError(t, Tokenize(strings.NewReader(`{"b":[1,{"a":2}],"c":{}}`), h))
+		assert.Equal(t, []string{"{", "key:b", "[", "{", "key:a", "}", "]", "key:c", "{", "}", "}"}, h.events)
+	})
+
+	t.Run("should tokenize scalar documents", func(t *testing.T) {
+		h := &sliceHandler{}
+		require.NoError(t, Tokenize(strings.NewReader(`"abc"`), h))
+		assert.Equal(t, "abc", h.result)
+	})
+
+	t.Run("should apply options", func(t *testing.T) {
+		h := &sliceHandler{}
+		require.NoError(t, Tokenize(strings.NewReader(`[12345678901234567890, 1.0]`), h, WithUseNumber(true)))
+		assert.Equal(t, []any{json.Number("12345678901234567890"), json.Number("1.0")}, h.result)
+
+		err := Tokenize(strings.NewReader(`{"a":{"b":{}}}`), &sliceHandler{}, WithMaxDepth(2))
+		require.ErrorIs(t, err, ErrJSON)
+
+		err = Tokenize(strings.NewReader(`{"a":1,"b":2}`), &sliceHandler{}, WithMaxKeys(1))
+		require.ErrorIs(t, err, ErrJSON)
+	})
+
+	t.Run("should stop when the handler returns an error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		h := &stoppingHandler{sliceHandler: &sliceHandler{}, stopAt: "b", err: errStop}
+
+		err := Tokenize(strings.NewReader(`{"a":1,"b":2,"c":3}`), h)
+		require.ErrorIs(t, err, errStop)
+		assert.Equal(t, []string{"{", "key:a", "key:b"}, h.events)
+	})
+
+	t.Run("should reject invalid JSON", func(t *testing.T) {
+		for _, input := range []string{``, `{"a":}`, `{"a":1`, `[1,2`, `{"a":1} {}`} {
+			var parseErr *ParseError
+
+			err := Tokenize(strings.NewReader(input), &sliceHandler{})
+			require.Errorf(t, err, "expected an error for %q", input)
+			require.ErrorAsf(t, err, &parseErr, "expected a ParseError for %q", input)
+		}
+	})
+}
+
+type stoppingHandler struct {
+	*sliceHandler
+	stopAt string
+	err    error
+}
+
+func (h *stoppingHandler) Key(key string) error {
+	if err := h.sliceHandler.Key(key); err != nil {
+		return err
+	}
+	if key == h.stopAt {
+		return h.err
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Rule is a structural check applied to a [JSONMapSlice] by [JSONMapSlice.Validate].
+//
+// A rule returns nil when the object complies, or an error describing the violation.
+type Rule func(JSONMapSlice) error
+
+// Validate checks a [JSONMapSlice] against a set of structural rules.
+//
+// All rules are evaluated, and all violations are reported together as a single error,
+// in the order of the rules. The individual violations may be retrieved with [errors.Is] or [errors.As],
+// and all of them are an [ErrJSON].
+//
+// This is a lightweight alternative to a full JSON schema validation, e.g. to check the overall
+// shape of a document before processing it.
+func (s JSONMapSlice) Validate(rules ...Rule) error {
+	var violations []error
+	for _, rule := range rules {
+		if err := rule(s); err != nil {
+			violations = append(violations, err)
+		}
+	}
+
+	return errors.Join(violations...)
+}
+
+// RequireKey is a [Rule] that checks that a top-level key is present.
+func RequireKey(key string) Rule {
+	return func(s JSONMapSlice) error {
+		if !s.Has(key) {
+			return fmt.Errorf("required key %q is missing: %w", key, ErrJSON)
+		}
+
+		return nil
+	}
+}
+
+// KeyIsObject is a [Rule] that checks that the value of a top-level key is a JSON object,
+// i.e. a non-nil [JSONMapSlice] or go map.
+//
+// The rule is satisfied when the key is absent: combine it with [RequireKey] for a mandatory object.
+func KeyIsObject(key string) Rule {
+	return keyIs(key, "an object", func(value any) bool {
+		if obj, ok := value.(JSONMapSlice); ok {
+			return obj != nil
+		}
+
+		v := reflect.ValueOf(value)
+
+		return v.Kind() == reflect.Map && !v.IsNil()
+	})
+}
+
+// KeyIsArray is a [Rule] that checks that the value of a top-level key is a JSON array,
+// i.e. a non-nil []any or any other go slice or array, except []byte and [JSONMapSlice].
+//
+// The rule is satisfied when the key is absent: combine it with [RequireKey] for a mandatory array.
+func KeyIsArray(key string) Rule {
+	return keyIs(key, "an array", func(value any) bool {
+		if _, isObject := value.(JSONMapSlice); isObject {
+			return false
+		}
+
+		v := reflect.ValueOf(value)
+		switch v.Kind() {
+		case reflect.Slice:
+			return !v.IsNil() && v.Type().Elem().Kind() != reflect.Uint8
+		case reflect.Array:
+			return v.Type().Elem().Kind() != reflect.Uint8
+		default:
+			return false
+		}
+	})
+}
+
+func keyIs(key, expected string, check func(any) bool) Rule {
+	return func(s JSONMapSlice) error {
+		value, ok := s.Get(key)
+		if !ok || check(value) {
+			return nil
+		}
+
+		return fmt.Errorf("key %q must be %s, but got %s: %w", key, expected, describeValue(value), ErrJSON)
+	}
+}
+
+// describeValue renders the JSON type of a value in error messages.
+func describeValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case JSONMapSlice:
+		if v == nil {
+			return "null"
+		}
+
+		return "an object"
+	case []any:
+		if v == nil {
+			return "null"
+		}
+
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return "a number"
+	default:
+		return fmt.Sprintf("a %T", value)
+	}
+}
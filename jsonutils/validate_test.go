@@ -0,0 +1,110 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceValidate(t *testing.T) {
+	rules := []Rule{
+		RequireKey("openapi"),
+		RequireKey("info"),
+		KeyIsObject("info"),
+		KeyIsObject("paths"),
+		KeyIsArray("servers"),
+	}
+
+	t.Run("should accept a valid object", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSON([]byte(`{"openapi":"3.1.0","info":{"title":"x"},"paths":{},"servers":[]}`)))
+		require.NoError(t, data.Validate(rules...))
+
+		t.Run("should accept absent optional keys", func(t *testing.T) {
+			require.NoError(t, JSONMapSlice{{Key: "openapi", Value: "3.1.0"}, {Key: "info", Value: JSONMapSlice{}}}.Validate(rules...))
+		})
+
+		t.Run("should accept go maps and slices", func(t *testing.T) {
+			data := JSONMapSlice{
+				{Key: "paths", Value: map[string]any{}},
+				{Key: "servers", Value: []string{"a"}},
+				{Key: "tags", Value: [1]int{1}},
+			}
+			require.NoError(t, data.Validate(KeyIsObject("paths"), KeyIsArray("servers"), KeyIsArray("tags")))
+		})
+
+		t.Run("should accept no rule", func(t *testing.T) {
+			require.NoError(t, JSONMapSlice(nil).Validate())
+		})
+	})
+
+	t.Run("should report all violations at once", func(t *testing.T) {
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSON([]byte(`{"info":"x","paths":[],"servers":{"url":"y"}}`)))
+
+		err := data.Validate(rules...)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrJSON)
+
+		assert.Equal(t,
+			`required key "openapi" is missing: json error`+"\n"+
+				`key "info" must be an object, but got a string: json error`+"\n"+
+				`key "paths" must be an object, but got an array: json error`+"\n"+
+				`key "servers" must be an array, but got an object: json error`,
+			err.Error(),
+		)
+
+		unwrapper, ok := err.(interface{ Unwrap() []error })
+		require.True(t, ok)
+		assert.Len(t, unwrapper.Unwrap(), 4)
+	})
+
+	t.Run("should reject null values", func(t *testing.T) {
+		data := JSONMapSlice{
+			{Key: "a", Value: nil},
+			{Key: "b", Value: JSONMapSlice(nil)},
+			{Key: "c", Value: []any(nil)},
+			{Key: "d", Value: []byte("x")},
+			{Key: "e", Value: int64(1)},
+		}
+
+		err := data.Validate(KeyIsObject("a"), KeyIsObject("b"), KeyIsArray("c"), KeyIsArray("d"), KeyIsArray("e"))
+		require.ErrorIs(t, err, ErrJSON)
+		assert.Equal(t,
+			`key "a" must be an object, but got null: json error`+"\n"+
+				`key "b" must be an object, but got null: json error`+"\n"+
+				`key "c" must be an array, but got null: json error`+"\n"+
+				`key "d" must be an array, but got a []uint8: json error`+"\n"+
+				`key "e" must be an array, but got a number: json error`,
+			err.Error(),
+		)
+	})
+
+	t.Run("should compose custom rules", func(t *testing.T) {
+		nonEmpty := func(s JSONMapSlice) error {
+			if s.IsEmpty() {
+				return ErrJSON
+			}
+
+			return nil
+		}
+
+		require.ErrorIs(t, JSONMapSlice{}.Validate(nonEmpty, RequireKey("a")), ErrJSON)
+		require.NoError(t, JSONMapSlice{{Key: "a", Value: true}}.Validate(nonEmpty, RequireKey("a")))
+	})
+}
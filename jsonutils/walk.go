@@ -0,0 +1,278 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import "strconv"
+
+// WalkFunc is called by [JSONMapSlice.Walk] for every node visited.
+//
+// The path is the JSON Pointer to the node, and key is its last token, i.e. the key of the node in its
+// parent object, or its index in its parent array.
+//
+// The function returns the value to retain for this node, and whether the node should be kept:
+// returning false drops the node from its parent.
+type WalkFunc func(path string, key string, value any) (any, bool)
+
+// Walk visits all nodes of this [JSONMapSlice] in depth-first order, and returns a transformed copy.
+//
+// Each key of an object and each element of an array is passed to the [WalkFunc], which may
+// replace the value of the node or drop the node altogether. Dropped nodes are removed while the order
+// of the remaining keys or elements is preserved.
+//
+// Nested objects and arrays are visited after their parent, so the function may replace a whole
+// subtree without visiting its content: when a value is replaced, the replacement is visited instead.
+//
+// The receiver is not mutated.
+func (s JSONMapSlice) Walk(fn WalkFunc) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	return s.walk("", fn)
+}
+
+func (s JSONMapSlice) walk(prefix string, fn WalkFunc) JSONMapSlice {
+	result := make(JSONMapSlice, 0, len(s))
+	for _, item := range s {
+		value, keep := walkValue(prefix, item.Key, item.Value, fn)
+		if !keep {
+			continue
+		}
+
+		result = append(result, JSONMapItem{Key: item.Key, Value: value, Comment: item.Comment})
+	}
+
+	return result
+}
+
+func walkValue(prefix, key string, value any, fn WalkFunc) (any, bool) {
+	value, _ = resolveLazy(value)
+	path := prefix + "/" + escapePointerToken(key)
+	value, keep := fn(path, key, value)
+	if !keep {
+		return nil, false
+	}
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		if v == nil {
+			return v, true
+		}
+
+		return v.walk(path, fn), true
+	case []any:
+		if v == nil {
+			return v, true
+		}
+
+		result := make([]any, 0, len(v))
+		for i, elem := range v {
+			elem, keep := walkValue(path, strconv.Itoa(i), elem, fn)
+			if !keep {
+				continue
+			}
+
+			result = append(result, elem)
+		}
+
+		return result, true
+	default:
+		return value, true
+	}
+}
+
+// Filter returns a new [JSONMapSlice] with only the top-level keys for which the predicate returns true,
+// in their original order.
+//
+// The receiver is not mutated, but nested values are shared with the result: use [JSONMapSlice.Clone]
+// to obtain an independent copy.
+func (s JSONMapSlice) Filter(pred func(key string, value any) bool) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, 0, len(s))
+	for _, item := range s {
+		if pred(item.Key, item.Value) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// MapValues returns a new [JSONMapSlice] with the same top-level keys, in the same order,
+// where every value is replaced by the result of the function.
+//
+// Unlike [JSONMapSlice.Walk], nested objects and arrays are not visited. The receiver is not mutated.
+func (s JSONMapSlice) MapValues(fn func(key string, value any) any) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	for i, item := range s {
+		result[i] = JSONMapItem{Key: item.Key, Value: fn(item.Key, item.Value), Comment: item.Comment}
+	}
+
+	return result
+}
+
+// RenameKeys returns a copy of this [JSONMapSlice] with the keys of all objects renamed by a function,
+// together with a table to retrieve the original keys.
+//
+// This is useful to derive go identifiers from the keys of an arbitrary document, in code generation,
+// e.g. with the ToGoName method of a NameMangler from the mangling package.
+//
+// Nested objects are renamed too, including objects found inside arrays. Keys which are renamed to the
+// same name as a previous key of the same object are made unique with a numerical suffix, e.g. "ID2".
+//
+// The table maps the JSON pointer of every key in the result to the original key, e.g. "/Info/APIVersion"
+// to "api-version".
+//
+// The receiver is not mutated.
+func (s JSONMapSlice) RenameKeys(rename func(key string) string) (JSONMapSlice, map[string]string) {
+	originals := make(map[string]string)
+	if s == nil {
+		return nil, originals
+	}
+
+	return s.renameKeys("", rename, originals), originals
+}
+
+func (s JSONMapSlice) renameKeys(prefix string, rename func(string) string, originals map[string]string) JSONMapSlice {
+	result := make(JSONMapSlice, len(s))
+	used := make(map[string]struct{}, len(s))
+
+	for i := range s {
+		key := rename(s[i].Key)
+		if _, found := used[key]; found {
+			base := key
+			for n := 2; ; n++ {
+				key = base + strconv.Itoa(n)
+				if _, found := used[key]; !found {
+					break
+				}
+			}
+		}
+		used[key] = struct{}{}
+
+		path := prefix + "/" + escapePointerToken(key)
+		originals[path] = s[i].Key
+		result[i] = JSONMapItem{Key: key, Value: renameValueKeys(path, s[i].Value, rename, originals), Comment: s[i].Comment}
+	}
+
+	return result
+}
+
+func renameValueKeys(path string, value any, rename func(string) string, originals map[string]string) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		if v == nil {
+			return v
+		}
+
+		return v.renameKeys(path, rename, originals)
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = renameValueKeys(path+"/"+strconv.Itoa(i), elem, rename, originals)
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+// TransformKeys returns a copy of this [JSONMapSlice] with its top-level keys transformed by a function,
+// e.g. [ToCamelKeys] or [ToPascalKeys].
+//
+// Unlike [JSONMapSlice.RenameKeys], keys transformed to the same name are not made unique, so the result
+// may hold duplicate keys. Values are shared with the receiver, which is not mutated.
+func (s JSONMapSlice) TransformKeys(fn func(key string) string) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	for i, item := range s {
+		result[i] = JSONMapItem{Key: fn(item.Key), Value: item.Value, Comment: item.Comment}
+	}
+
+	return result
+}
+
+// TransformKeysRecursive is like [JSONMapSlice.TransformKeys], but transforms the keys of nested objects too,
+// including objects found inside arrays.
+//
+// The receiver is not mutated.
+func (s JSONMapSlice) TransformKeysRecursive(fn func(key string) string) JSONMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(JSONMapSlice, len(s))
+	for i := range s {
+		result[i] = JSONMapItem{Key: fn(s[i].Key), Value: transformValueKeys(s[i].Value, fn), Comment: s[i].Comment}
+	}
+
+	return result
+}
+
+func transformValueKeys(value any, fn func(string) string) any {
+	value, _ = resolveLazy(value)
+
+	switch v := value.(type) {
+	case JSONMapSlice:
+		return v.TransformKeysRecursive(fn)
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = transformValueKeys(elem, fn)
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+// ToCamelKeys transforms a key to camelCase, e.g. "user_id" to "userID", for use with [JSONMapSlice.TransformKeys].
+//
+// Common initialisms such as "ID", "URL" or "HTTP" are upper-cased like in go identifiers, unless they start the key:
+// "http_url" becomes "httpURL".
+// Initialisms default to those of the mangling package, and may be configured with [AddInitialisms].
+func ToCamelKeys(key string) string {
+	return keyMangler().ToVarName(key)
+}
+
+// ToPascalKeys transforms a key to PascalCase, e.g. "user_id" to "UserID", for use with [JSONMapSlice.TransformKeys].
+//
+// Common initialisms such as "ID", "URL" or "HTTP" are upper-cased like in go identifiers.
+// Initialisms default to those of the mangling package, and may be configured with [AddInitialisms].
+func ToPascalKeys(key string) string {
+	return keyMangler().ToGoName(key)
+}
@@ -0,0 +1,349 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/swag/mangling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceWalk(t *testing.T) {
+	const sd = `{"user":"a","password":"secret","nested":{"password":"x","keep":1,"deeper":[{"password":"y","id":2},"password"]},"a/b~c":{"password":null}}`
+
+	parse := func(t *testing.T, sd string) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		return data
+	}
+
+	marshal := func(t *testing.T, data JSONMapSlice) string {
+		t.Helper()
+
+		jazon, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		return string(jazon)
+	}
+
+	t.Run("should redact passwords at any depth", func(t *testing.T) {
+		data := parse(t, sd)
+
+		redacted := data.Walk(func(_, key string, value any) (any, bool) {
+			if key == "password" {
+				return "***", true
+			}
+
+			return value, true
+		})
+		assert.Equal(t,
+			`{"user":"a","password":"***","nested":{"password":"***","keep":1,"deeper":[{"password":"***","id":2},"password"]},"a/b~c":{"password":"***"}}`,
+			marshal(t, redacted),
+		)
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			assert.Equal(t, sd, marshal(t, data))
+		})
+	})
+
+	t.Run("should drop passwords at any depth", func(t *testing.T) {
+		dropped := parse(t, sd).Walk(func(_, key string, value any) (any, bool) {
+			return value, key != "password"
+		})
+		assert.Equal(t,
+			`{"user":"a","nested":{"keep":1,"deeper":[{"id":2},"password"]},"a/b~c":{}}`,
+			marshal(t, dropped),
+		)
+	})
+
+	t.Run("should drop array elements", func(t *testing.T) {
+		dropped := parse(t, `{"a":[1,"x",2,"y",3]}`).Walk(func(_, _ string, value any) (any, bool) {
+			_, isString := value.(string)
+
+			return value, !isString
+		})
+		assert.Equal(t, `{"a":[1,2,3]}`, marshal(t, dropped))
+	})
+
+	t.Run("should visit all nodes with their JSON pointer", func(t *testing.T) {
+		var paths, keys []string
+		parse(t, sd).Walk(func(path, key string, value any) (any, bool) {
+			paths = append(paths, path)
+			keys = append(keys, key)
+
+			return value, true
+		})
+
+		assert.Equal(t, []string{
+			"/user", "/password", "/nested", "/nested/password", "/nested/keep", "/nested/deeper",
+			"/nested/deeper/0", "/nested/deeper/0/password", "/nested/deeper/0/id", "/nested/deeper/1",
+			"/a~1b~0c", "/a~1b~0c/password",
+		}, paths)
+		assert.Equal(t, []string{
+			"user", "password", "nested", "password", "keep", "deeper",
+			"0", "password", "id", "1",
+			"a/b~c", "password",
+		}, keys)
+
+		t.Run("paths should resolve with AtPointer", func(t *testing.T) {
+			data := parse(t, sd)
+			data.Walk(func(path, _ string, value any) (any, bool) {
+				resolved, err := data.AtPointer(path)
+				require.NoError(t, err)
+				assert.Equal(t, value, resolved)
+
+				return value, true
+			})
+		})
+	})
+
+	t.Run("should visit replaced values instead of the original ones", func(t *testing.T) {
+		var paths []string
+		replaced := parse(t, `{"a":{"b":1},"c":2}`).Walk(func(path, key string, value any) (any, bool) {
+			paths = append(paths, path)
+			if key == "a" {
+				return []any{JSONMapSlice{{Key: "d", Value: true}}}, true
+			}
+
+			return value, true
+		})
+
+		assert.Equal(t, []string{"/a", "/a/0", "/a/0/d", "/c"}, paths)
+		assert.Equal(t, `{"a":[{"d":true}],"c":2}`, marshal(t, replaced))
+	})
+
+	t.Run("should walk nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		assert.Nil(t, empty.Walk(nil))
+		assert.Equal(t, JSONMapSlice{}, JSONMapSlice{}.Walk(nil))
+	})
+}
+
+func TestJSONMapSliceFilterAndMapValues(t *testing.T) {
+	const sd = `{"x-a":1,"name":"n","x-b":{"c":2},"count":1.5,"x-c":[3]}`
+
+	parse := func(t *testing.T) JSONMapSlice {
+		t.Helper()
+
+		var data JSONMapSlice
+		require.NoError(t, data.UnmarshalJSON([]byte(sd)))
+
+		return data
+	}
+
+	t.Run("should filter keys by prefix", func(t *testing.T) {
+		data := parse(t)
+		extensions := data.Filter(func(key string, _ any) bool {
+			return strings.HasPrefix(key, "x-")
+		})
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "x-a", Value: int64(1)},
+			{Key: "x-b", Value: JSONMapSlice{{Key: "c", Value: int64(2)}}},
+			{Key: "x-c", Value: []any{int64(3)}},
+		}, extensions)
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			assert.Equal(t, parse(t), data)
+		})
+
+		t.Run("should return an empty object when nothing matches", func(t *testing.T) {
+			none := data.Filter(func(string, any) bool { return false })
+			require.NotNil(t, none)
+			assert.Empty(t, none)
+		})
+	})
+
+	t.Run("should double numerical values", func(t *testing.T) {
+		data := parse(t)
+		doubled := data.MapValues(func(_ string, value any) any {
+			switch v := value.(type) {
+			case int64:
+				return v * 2
+			case float64:
+				return v * 2
+			default:
+				return value
+			}
+		})
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "x-a", Value: int64(2)},
+			{Key: "name", Value: "n"},
+			{Key: "x-b", Value: JSONMapSlice{{Key: "c", Value: int64(2)}}},
+			{Key: "count", Value: float64(3)},
+			{Key: "x-c", Value: []any{int64(3)}},
+		}, doubled, "nested values should not be visited")
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			assert.Equal(t, parse(t), data)
+		})
+	})
+
+	t.Run("should preserve comments", func(t *testing.T) {
+		data := JSONMapSlice{{Key: "a", Value: 1, Comment: "first"}, {Key: "b", Value: 2}}
+
+		assert.Equal(t, "first", data.Filter(func(string, any) bool { return true })[0].Comment)
+		assert.Equal(t, "first", data.MapValues(func(_ string, v any) any { return v })[0].Comment)
+	})
+
+	t.Run("should handle nil objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		assert.Nil(t, empty.Filter(nil))
+		assert.Nil(t, empty.MapValues(nil))
+		assert.Equal(t, JSONMapSlice{}, JSONMapSlice{}.MapValues(nil))
+	})
+}
+
+func TestJSONMapSliceRenameKeys(t *testing.T) {
+	mangler := mangling.NewNameMangler()
+
+	t.Run("should rename keys to go names", func(t *testing.T) {
+		const sd = `{"api-version":"1.0","x-request-id":"abc","2fa_enabled":true,"url":"http://x",` +
+			`"owner":{"userId":1,"http_server":"s","tags":[{"$ref":"#/a"},"t"]},"x_id":1,"X-ID":2,"ID2":3}`
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(sd), &data))
+
+		renamed, originals := data.RenameKeys(mangler.ToGoName)
+
+		jazon, err := json.Marshal(renamed)
+		require.NoError(t, err)
+		assert.Equal(t,
+			`{"APIVersion":"1.0","XRequestID":"abc","X2faEnabled":true,"URL":"http://x",`+
+				`"Owner":{"UserID":1,"HTTPServer":"s","Tags":[{"DollarRef":"#/a"},"t"]},"XID":1,"XID2":2,"ID2":3}`,
+			string(jazon),
+			"initialisms should be recognized, leading digits prefixed and collisions made unique",
+		)
+
+		assert.Equal(t, map[string]string{
+			"/APIVersion":             "api-version",
+			"/XRequestID":             "x-request-id",
+			"/X2faEnabled":            "2fa_enabled",
+			"/URL":                    "url",
+			"/Owner":                  "owner",
+			"/Owner/UserID":           "userId",
+			"/Owner/HTTPServer":       "http_server",
+			"/Owner/Tags":             "tags",
+			"/Owner/Tags/0/DollarRef": "$ref",
+			"/XID":                    "x_id",
+			"/XID2":                   "X-ID",
+			"/ID2":                    "ID2",
+		}, originals)
+
+		t.Run("should not mutate the receiver", func(t *testing.T) {
+			jazon, err := json.Marshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, sd, string(jazon))
+		})
+
+		t.Run("should retrieve original keys from the table", func(t *testing.T) {
+			for pointer, original := range originals {
+				_, err := renamed.AtPointer(pointer)
+				require.NoErrorf(t, err, "pointer %q should exist in the result", pointer)
+				assert.NotEmpty(t, original)
+			}
+		})
+	})
+
+	t.Run("should make keys unique by appending a suffix", func(t *testing.T) {
+		renamed, originals := JSONMapSlice{
+			{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3}, {Key: "X2", Value: 4},
+		}.RenameKeys(func(string) string { return "X" })
+
+		assert.Equal(t, JSONMapSlice{
+			{Key: "X", Value: 1}, {Key: "X2", Value: 2}, {Key: "X3", Value: 3}, {Key: "X4", Value: 4},
+		}, renamed)
+		assert.Equal(t, map[string]string{"/X": "a", "/X2": "b", "/X3": "c", "/X4": "X2"}, originals)
+	})
+
+	t.Run("should rename nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		renamed, originals := empty.RenameKeys(mangler.ToGoName)
+		assert.Nil(t, renamed)
+		assert.Empty(t, originals)
+
+		renamed, originals = JSONMapSlice{}.RenameKeys(mangler.ToGoName)
+		assert.Equal(t, JSONMapSlice{}, renamed)
+		assert.Empty(t, originals)
+	})
+}
+
+func TestJSONMapSliceTransformKeys(t *testing.T) {
+	data := JSONMapSlice{
+		{Key: "user_id", Value: int64(1)},
+		{Key: "api_version", Value: "v1"},
+		{Key: "home_url", Value: JSONMapSlice{{Key: "http_status", Value: int64(200)}}},
+		{Key: "ip_addresses", Value: []any{JSONMapSlice{{Key: "id", Value: "a"}}}},
+	}
+
+	t.Run("should transform top-level keys to camelCase", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "userID", Value: int64(1)},
+			{Key: "apiVersion", Value: "v1"},
+			{Key: "homeURL", Value: JSONMapSlice{{Key: "http_status", Value: int64(200)}}},
+			{Key: "ipAddresses", Value: []any{JSONMapSlice{{Key: "id", Value: "a"}}}},
+		}, data.TransformKeys(ToCamelKeys))
+	})
+
+	t.Run("should transform all keys to camelCase", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "userID", Value: int64(1)},
+			{Key: "apiVersion", Value: "v1"},
+			{Key: "homeURL", Value: JSONMapSlice{{Key: "httpStatus", Value: int64(200)}}},
+			{Key: "ipAddresses", Value: []any{JSONMapSlice{{Key: "id", Value: "a"}}}},
+		}, data.TransformKeysRecursive(ToCamelKeys))
+	})
+
+	t.Run("should transform all keys to PascalCase", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "UserID", Value: int64(1)},
+			{Key: "APIVersion", Value: "v1"},
+			{Key: "HomeURL", Value: JSONMapSlice{{Key: "HTTPStatus", Value: int64(200)}}},
+			{Key: "IPAddresses", Value: []any{JSONMapSlice{{Key: "ID", Value: "a"}}}},
+		}, data.TransformKeysRecursive(ToPascalKeys))
+	})
+
+	t.Run("should not mutate the receiver", func(t *testing.T) {
+		_ = data.TransformKeysRecursive(ToPascalKeys)
+
+		assert.Equal(t, "user_id", data[0].Key)
+		assert.Equal(t, "http_status", data[2].Value.(JSONMapSlice)[0].Key)
+	})
+
+	t.Run("should keep duplicate keys", func(t *testing.T) {
+		assert.Equal(t, JSONMapSlice{
+			{Key: "userID", Value: 1}, {Key: "userID", Value: 2},
+		}, JSONMapSlice{{Key: "user_id", Value: 1}, {Key: "userID", Value: 2}}.TransformKeys(ToCamelKeys))
+	})
+
+	t.Run("should transform nil or empty objects", func(t *testing.T) {
+		var empty JSONMapSlice
+
+		assert.Nil(t, empty.TransformKeys(ToCamelKeys))
+		assert.Nil(t, empty.TransformKeysRecursive(ToCamelKeys))
+		assert.Equal(t, JSONMapSlice{}, JSONMapSlice{}.TransformKeysRecursive(ToPascalKeys))
+	})
+}
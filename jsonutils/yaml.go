@@ -0,0 +1,328 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/swag/internal/yamlnodes"
+	yaml "gopkg.in/yaml.v3"
+)
+
+const ( // See https://yaml.org/type/
+	yamlStringScalar = "tag:yaml.org,2002:str"
+	yamlIntScalar    = "tag:yaml.org,2002:int"
+	yamlBoolScalar   = "tag:yaml.org,2002:bool"
+	yamlFloatScalar  = "tag:yaml.org,2002:float"
+	yamlNull         = "tag:yaml.org,2002:null"
+	yamlMerge        = "tag:yaml.org,2002:merge"
+)
+
+var (
+	_ yaml.Marshaler   = JSONMapSlice{}
+	_ yaml.Unmarshaler = &JSONMapSlice{}
+)
+
+// MarshalYAML renders a [JSONMapSlice] as a YAML mapping node, preserving the order of keys.
+//
+// Nested [JSONMapSlice] objects are rendered as mappings and []any arrays as sequences.
+// Strings, numbers, booleans and nulls are rendered as scalars with the corresponding YAML tag.
+func (s JSONMapSlice) MarshalYAML() (any, error) {
+	return s.yamlNode()
+}
+
+func (s JSONMapSlice) yamlNode() (*yaml.Node, error) {
+	if s == nil {
+		return yamlScalarNode(yamlNull, "null"), nil
+	}
+
+	n := &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Content: make([]*yaml.Node, 0, 2*len(s)),
+	}
+
+	for _, item := range s {
+		child, err := yamlValueNode(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		n.Content = append(n.Content, yamlScalarNode(yamlStringScalar, item.Key), child)
+	}
+
+	return n, nil
+}
+
+func yamlScalarNode(tag, value string) *yaml.Node {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   tag,
+		Value: value,
+	}
+}
+
+func yamlValueNode(value any) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case nil:
+		return yamlScalarNode(yamlNull, "null"), nil
+	case JSONMapSlice:
+		return v.yamlNode()
+	case []any:
+		if v == nil {
+			return yamlScalarNode(yamlNull, "null"), nil
+		}
+
+		n := &yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Content: make([]*yaml.Node, 0, len(v)),
+		}
+		for _, elem := range v {
+			child, err := yamlValueNode(elem)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, child)
+		}
+
+		return n, nil
+	case string:
+		return yamlScalarNode(yamlStringScalar, v), nil
+	case bool:
+		return yamlScalarNode(yamlBoolScalar, strconv.FormatBool(v)), nil
+	case int:
+		return yamlScalarNode(yamlIntScalar, strconv.Itoa(v)), nil
+	case int8:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatInt(int64(v), 10)), nil
+	case int16:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatInt(int64(v), 10)), nil
+	case int32:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatInt(int64(v), 10)), nil
+	case int64:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatInt(v, 10)), nil
+	case uint:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatUint(uint64(v), 10)), nil
+	case uint8:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatUint(uint64(v), 10)), nil
+	case uint16:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatUint(uint64(v), 10)), nil
+	case uint32:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatUint(uint64(v), 10)), nil
+	case uint64:
+		return yamlScalarNode(yamlIntScalar, strconv.FormatUint(v, 10)), nil
+	case float32:
+		return yamlScalarNode(yamlFloatScalar, yamlFloat(float64(v), 32)), nil
+	case float64:
+		return yamlScalarNode(yamlFloatScalar, yamlFloat(v, 64)), nil
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return yamlScalarNode(yamlFloatScalar, v.String()), nil
+		}
+
+		return yamlScalarNode(yamlIntScalar, v.String()), nil
+	case *LazyValue:
+		decoded, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		return yamlValueNode(decoded)
+	default:
+		switch reflect.TypeOf(v).Kind() { //nolint:exhaustive
+		case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+			return nil, fmt.Errorf("unsupported type for YAML: %T: %w", v, ErrJSON)
+		}
+
+		// let the YAML encoder figure out other types
+		var n yaml.Node
+		if err := n.Encode(v); err != nil {
+			return nil, err
+		}
+
+		return &n, nil
+	}
+}
+
+// yamlFloat formats a float so that it is always resolved as a float by YAML parsers.
+func yamlFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return ".nan"
+	case math.IsInf(f, 1):
+		return ".inf"
+	case math.IsInf(f, -1):
+		return "-.inf"
+	}
+
+	str := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+
+	return str
+}
+
+// UnmarshalYAML builds a [JSONMapSlice] from a YAML mapping node, preserving the order of keys.
+//
+// Nested mappings are unmarshaled as [JSONMapSlice] and sequences as []any.
+// Scalars are unmarshaled as the same go types as with [JSONMapSlice.UnmarshalJSON]:
+// integers become int64 (or float64 if they overflow), floats become float64.
+// Timestamps are unmarshaled as strings.
+//
+// Aliases are expanded, but an error is returned if an anchor is referenced from within its own value,
+// or if aliases expand to too many nodes compared to the size of the document.
+func (s *JSONMapSlice) UnmarshalYAML(value *yaml.Node) error {
+	if err := yamlnodes.CheckAliases(value); err != nil {
+		return fmt.Errorf("unable to expand YAML aliases: %w: %w", err, ErrJSON)
+	}
+
+	v, err := fromYAMLNode(value)
+	if err != nil {
+		return err
+	}
+
+	switch obj := v.(type) {
+	case nil:
+		*s = nil
+	case JSONMapSlice:
+		*s = obj
+	default:
+		return fmt.Errorf("expected a YAML mapping, but got %T: %w", v, ErrJSON)
+	}
+
+	return nil
+}
+
+func fromYAMLNode(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		if len(node.Content) != 1 {
+			return nil, fmt.Errorf("unexpected YAML document node content length: %d: %w", len(node.Content), ErrJSON)
+		}
+
+		return fromYAMLNode(node.Content[0])
+	case yaml.MappingNode:
+		return fromYAMLMapping(node)
+	case yaml.SequenceNode:
+		a := make([]any, 0, len(node.Content))
+		for _, child := range node.Content {
+			v, err := fromYAMLNode(child)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+
+		return a, nil
+	case yaml.ScalarNode:
+		return fromYAMLScalar(node)
+	case yaml.AliasNode:
+		return fromYAMLNode(node.Alias)
+	default:
+		return nil, fmt.Errorf("unsupported YAML node kind: %v: %w", node.Kind, ErrJSON)
+	}
+}
+
+// fromYAMLMapping decodes a YAML mapping node, preserving the order of keys.
+//
+// Merge keys ("<<") are resolved: the keys of the merged mappings which are not explicitly
+// set by the mapping are appended after its own keys.
+func fromYAMLMapping(node *yaml.Node) (JSONMapSlice, error) {
+	const pair = 2
+	s := make(JSONMapSlice, 0, len(node.Content)/pair)
+
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += pair {
+		keyNode := node.Content[i]
+		if keyNode.Kind == yaml.AliasNode {
+			keyNode = keyNode.Alias
+		}
+		if keyNode.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("expected a scalar YAML key at line %d, but got node kind %v: %w", keyNode.Line, keyNode.Kind, ErrJSON)
+		}
+
+		if keyNode.LongTag() == yamlMerge {
+			merged = append(merged, node.Content[i+1])
+
+			continue
+		}
+
+		v, err := fromYAMLNode(node.Content[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		s = append(s, JSONMapItem{Key: keyNode.Value, Value: v})
+	}
+
+	if len(merged) == 0 {
+		return s, nil
+	}
+
+	s, err := yamlnodes.Merge(s, jsonMapItemKey, merged, fromYAMLMapping)
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge YAML mappings: %w: %w", err, ErrJSON)
+	}
+
+	return s, nil
+}
+
+func jsonMapItemKey(item JSONMapItem) string {
+	return item.Key
+}
+
+func fromYAMLScalar(node *yaml.Node) (any, error) {
+	switch node.LongTag() {
+	case yamlNull:
+		return nil, nil
+	case yamlBoolScalar:
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return nil, err
+		}
+
+		return b, nil
+	case yamlIntScalar:
+		var i int64
+		if err := node.Decode(&i); err == nil {
+			return i, nil
+		}
+
+		// overflow: fall back to a float, like with JSON
+		f, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode YAML integer %q: %w: %w", node.Value, err, ErrJSON)
+		}
+
+		return f, nil
+	case yamlFloatScalar:
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return nil, err
+		}
+
+		return f, nil
+	default:
+		// strings, timestamps, binary and any other tag
+		return node.Value, nil
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestJSONMapSliceMarshalYAML(t *testing.T) {
+	t.Run("should marshal a nested object as YAML, preserving the order of keys", func(t *testing.T) {
+		const (
+			input = `{
+  "z": "a string",
+  "y": {"b": 1, "a": [1.5, true, null, {"x": "true", "w": "12"}]},
+  "x": [],
+  "w": {},
+  "v": 2.0,
+  "u": -3
+}`
+			expected = `z: a string
+y:
+    b: 1
+    a:
+        - 1.5
+        - true
+        - null
+        - x: "true"
+          w: "12"
+x: []
+w: {}
+v: 2.0
+u: -3
+`
+		)
+
+		var data JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(input), &data))
+
+		y, err := yaml.Marshal(data)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(y))
+
+		t.Run("should unmarshal the YAML back with the same types", func(t *testing.T) {
+			var back map[string]any
+			require.NoError(t, yaml.Unmarshal(y, &back))
+
+			assert.Equal(t, 2.0, back["v"])
+			assert.Equal(t, -3, back["u"])
+			assert.Equal(t, "12", back["y"].(map[string]any)["a"].([]any)[3].(map[string]any)["w"])
+		})
+	})
+
+	t.Run("should marshal scalars with YAML tags", func(t *testing.T) {
+		for _, fixture := range []struct {
+			Title    string
+			Value    any
+			Expected string
+		}{
+			{Title: "string", Value: "x", Expected: "a: x\n"},
+			{Title: "quoted string", Value: "null", Expected: "a: \"null\"\n"},
+			{Title: "int", Value: 1, Expected: "a: 1\n"},
+			{Title: "uint64", Value: uint64(math.MaxUint64), Expected: "a: 18446744073709551615\n"},
+			{Title: "integral float", Value: float64(10), Expected: "a: 10.0\n"},
+			{Title: "large float", Value: 1e21, Expected: "a: 1e+21\n"},
+			{Title: "float32", Value: float32(1.5), Expected: "a: 1.5\n"},
+			{Title: "NaN", Value: math.NaN(), Expected: "a: .nan\n"},
+			{Title: "Inf", Value: math.Inf(-1), Expected: "a: -.inf\n"},
+			{Title: "integer number", Value: json.Number("12345678901234567890"), Expected: "a: 12345678901234567890\n"},
+			{Title: "float number", Value: json.Number("1.50"), Expected: "a: 1.50\n"},
+			{Title: "bool", Value: false, Expected: "a: false\n"},
+			{Title: "null", Value: nil, Expected: "a: null\n"},
+			{Title: "null array", Value: []any(nil), Expected: "a: null\n"},
+			{Title: "null object", Value: JSONMapSlice(nil), Expected: "a: null\n"},
+			{Title: "other type", Value: []string{"x", "y"}, Expected: "a:\n    - x\n    - \"y\"\n"},
+		} {
+			t.Run(fixture.Title, func(t *testing.T) {
+				y, err := yaml.Marshal(JSONMapSlice{{Key: "a", Value: fixture.Value}})
+				require.NoError(t, err)
+				assert.Equal(t, fixture.Expected, string(y))
+			})
+		}
+	})
+
+	t.Run("should fail on unsupported values", func(t *testing.T) {
+		_, err := yaml.Marshal(JSONMapSlice{{Key: "a", Value: []any{JSONMapSlice{{Key: "b", Value: make(chan int)}}}}})
+		require.ErrorIs(t, err, ErrJSON)
+	})
+}
+
+func TestJSONMapSliceUnmarshalYAML(t *testing.T) {
+	t.Run("should unmarshal a nested YAML document, preserving the order of keys", func(t *testing.T) {
+		const (
+			input = `
+z: a string
+y:
+  b: 1
+  a:
+    - 1.5
+    - true
+    - ~
+    - x: "true"
+      w: 12
+x: []
+w: {}
+v: 2.0
+u: -3
+t: 2025-04-01
+s: 0x1F
+r: 123456789012345678901234567890
+`
+			asJSON = `{"z":"a string","y":{"b":1,"a":[1.5,true,null,{"x":"true","w":12}]},"x":[],"w":{},"v":2.0,"u":-3,"t":"2025-04-01","s":31,"r":123456789012345678901234567890}`
+		)
+
+		var data JSONMapSlice
+		require.NoError(t, yaml.Unmarshal([]byte(input), &data))
+
+		var expected JSONMapSlice
+		require.NoError(t, json.Unmarshal([]byte(asJSON), &expected))
+		require.Equal(t, expected, data)
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+
+		expectedJSON, err := expected.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, string(expectedJSON), string(jazon))
+
+		t.Run("should round-trip through YAML", func(t *testing.T) {
+			y, err := yaml.Marshal(data)
+			require.NoError(t, err)
+
+			var back JSONMapSlice
+			require.NoError(t, yaml.Unmarshal(y, &back))
+			assert.Equal(t, data, back)
+		})
+	})
+
+	t.Run("should resolve aliases", func(t *testing.T) {
+		const input = `
+a: &anchor
+  b: 1
+c: *anchor
+`
+		var data JSONMapSlice
+		require.NoError(t, yaml.Unmarshal([]byte(input), &data))
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":{"b":1},"c":{"b":1}}`, string(jazon))
+
+		t.Run("should fail on an anchor referenced from within its own value", func(t *testing.T) {
+			for _, input := range []string{
+				"a: &x\n  b: *x\n",
+				"a: &x\n  - [*x]\n",
+				"a: &x\n  <<: *x\n",
+			} {
+				require.ErrorIs(t, yaml.Unmarshal([]byte(input), &data), ErrJSON)
+			}
+		})
+
+		t.Run("should fail on aliases expanding to too many nodes", func(t *testing.T) {
+			const laughs = 10
+			var input strings.Builder
+			input.WriteString("l0: &l0 lol\n")
+			for i := 1; i <= 8; i++ {
+				alias := fmt.Sprintf("*l%d", i-1)
+				fmt.Fprintf(&input, "l%d: &l%d [%s%s]\n", i, i, strings.Repeat(alias+", ", laughs-1), alias)
+			}
+
+			require.ErrorIs(t, yaml.Unmarshal([]byte(input.String()), &data), ErrJSON)
+		})
+	})
+
+	t.Run("should resolve merge keys", func(t *testing.T) {
+		const input = `
+a: &a {x: 1, y: 1}
+b: &b {y: 2, w: 2}
+c:
+  z: 3
+  <<: *a
+  x: 9
+d:
+  <<: [*a, *b]
+e:
+  <<: *b
+  <<: {v: 5}
+`
+		var data JSONMapSlice
+		require.NoError(t, yaml.Unmarshal([]byte(input), &data))
+
+		jazon, err := data.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":{"x":1,"y":1},"b":{"y":2,"w":2},`+
+			`"c":{"z":3,"x":9,"y":1},"d":{"x":1,"y":1,"w":2},"e":{"y":2,"w":2,"v":5}}`, string(jazon),
+			"merged keys should be appended, and explicit keys or first merged mappings should take precedence",
+		)
+
+		t.Run("should fail to merge a value which is not a mapping", func(t *testing.T) {
+			require.ErrorIs(t, yaml.Unmarshal([]byte("a: &a [1]\nb:\n  <<: *a\n"), &data), ErrJSON)
+			require.ErrorIs(t, yaml.Unmarshal([]byte("b:\n  <<: [{x: 1}, 2]\n"), &data), ErrJSON)
+		})
+
+		t.Run("should fail on merge keys expanding to too many nodes", func(t *testing.T) {
+			const input = `
+a: &a {x: 1, y: 2, z: 3}
+b: &b {<<: [*a, *a, *a, *a], w: [*a, *a, *a, *a]}
+c: &c {<<: [*b, *b, *b, *b], w: [*b, *b, *b, *b]}
+d: &d {<<: [*c, *c, *c, *c], w: [*c, *c, *c, *c]}
+e: &e {<<: [*d, *d, *d, *d], w: [*d, *d, *d, *d]}
+f: &f {<<: [*e, *e, *e, *e], w: [*e, *e, *e, *e]}
+g: &g {<<: [*f, *f, *f, *f], w: [*f, *f, *f, *f]}
+`
+			require.ErrorIs(t, yaml.Unmarshal([]byte(input), &data), ErrJSON)
+		})
+	})
+
+	t.Run("should unmarshal null documents", func(t *testing.T) {
+		data := JSONMapSlice{{Key: "a", Value: 1}}
+		require.NoError(t, yaml.Unmarshal([]byte(`null`), &data))
+		assert.Nil(t, data)
+	})
+
+	t.Run("should fail on non-mapping documents", func(t *testing.T) {
+		var data JSONMapSlice
+		require.ErrorIs(t, yaml.Unmarshal([]byte(`[1,2]`), &data), ErrJSON)
+		require.ErrorIs(t, yaml.Unmarshal([]byte(`{[1]: 2}`), &data), ErrJSON)
+		require.Error(t, yaml.Unmarshal([]byte(`a: !!int x`), &data))
+	})
+}
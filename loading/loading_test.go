@@ -171,6 +171,34 @@ func TestLoadFromHTTP(t *testing.T) {
 			)
 			require.NoError(t, err)
 		})
+
+		t.Run("should load when the server responds before the timeout", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL,
+				WithTimeout(10*delay),
+			)
+			require.NoError(t, err)
+		})
+
+		t.Run("using the timeout of a custom HTTP client", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL,
+				WithTimeout(0),
+				WithHTTPClient(&http.Client{Timeout: wait}),
+			)
+			require.Error(t, err)
+
+			_, err = LoadFromFileOrHTTP(serv.URL,
+				WithTimeout(0),
+				WithHTTPClient(&http.Client{Timeout: 10 * delay}),
+			)
+			require.NoError(t, err)
+		})
+
+		t.Run("should fall back to the default HTTP client", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL,
+				WithHTTPClient(nil),
+			)
+			require.NoError(t, err)
+		})
 	})
 
 	t.Run("should load from local embedded file system (single file)", func(t *testing.T) {
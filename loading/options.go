@@ -53,6 +53,9 @@ func (fo fileOptions) ReadFileFunc() func(string) ([]byte, error) {
 
 // WithTimeout sets a timeout for the remote file loader.
 //
+// The timeout applies to every request, including reading the response body.
+// A timeout lower than or equal to 0 disables it.
+//
 // The default timeout is 30s.
 func WithTimeout(timeout time.Duration) Option {
 	return func(o *options) {
@@ -81,11 +84,18 @@ func WithCustomHeaders(headers map[string]string) Option {
 	}
 }
 
-// WithHTTClient overrides the default HTTP client used to fetch a remote file.
+// WithHTTPClient overrides the default HTTP client used to fetch a remote file.
+//
+// This may be used to configure a proxy, TLS settings or a custom transport.
+// The timeout set with [WithTimeout] still applies to every request, in addition to any timeout of the client.
 //
-// By default, [http.DefaultClient] is used.
+// By default, or if the client is nil, [http.DefaultClient] is used.
 func WithHTTPClient(client *http.Client) Option {
 	return func(o *options) {
+		if client == nil {
+			client = http.DefaultClient
+		}
+
 		o.client = client
 	}
 }
@@ -0,0 +1,71 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loading
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/swag/jsonutils"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// JSONMapSliceDoc loads a JSON or YAML document from either a file or a remote url,
+// then unmarshals it into a [jsonutils.JSONMapSlice], preserving the order of keys.
+//
+// The document is loaded like with [LoadFromFileOrHTTP], with the same options.
+// Its format is determined by the extension of the path, as recognized by [JSONMatcher] or [YAMLMatcher]:
+// for remote documents, the extension is taken from the path of the url. Documents with another extension, or
+// without one, are unmarshaled as JSON if they start with '{', and as YAML otherwise.
+//
+// Like with [jsonutils.JSONMapSlice.UnmarshalJSON], an empty or null document yields a nil [jsonutils.JSONMapSlice].
+func JSONMapSliceDoc(path string, opts ...Option) (jsonutils.JSONMapSlice, error) {
+	data, err := LoadFromFileOrHTTP(path, opts...)
+	if err != nil {
+		return nil, errors.Join(err, ErrLoader)
+	}
+
+	var doc jsonutils.JSONMapSlice
+	if isJSONDoc(path, data) {
+		err = doc.UnmarshalJSON(data)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse document at %q: %w: %w", path, err, ErrLoader)
+	}
+
+	return doc, nil
+}
+
+func isJSONDoc(path string, data []byte) bool {
+	if strings.HasPrefix(path, "http") {
+		if u, err := url.Parse(path); err == nil {
+			path = u.Path
+		}
+	}
+
+	switch {
+	case JSONMatcher(path):
+		return true
+	case YAMLMatcher(path):
+		return false
+	default:
+		return bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n\ufeff"), []byte("{"))
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 go-swagger maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loading
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag/jsonutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMapSliceDoc(t *testing.T) {
+	keys := func(doc jsonutils.JSONMapSlice) []string {
+		result := make([]string, 0, len(doc))
+		for _, item := range doc {
+			result = append(result, item.Key)
+		}
+
+		return result
+	}
+
+	t.Run("should retrieve pet store API as JSON and YAML, preserving the order of keys", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", serveJSONPestore)
+		mux.HandleFunc("/spec.yaml", serveYAMLPestore)
+		mux.HandleFunc("/yaml", serveYAMLPestore)
+		serv := httptest.NewServer(mux)
+		defer serv.Close()
+
+		fromJSON, err := JSONMapSliceDoc(serv.URL)
+		require.NoError(t, err)
+		require.Equal(t, []string{"swagger", "info", "host", "basePath", "schemes", "consumes", "produces", "paths", "definitions"}, keys(fromJSON))
+
+		info, ok := fromJSON.Get("info")
+		require.True(t, ok)
+		require.IsType(t, jsonutils.JSONMapSlice{}, info)
+		assert.Equal(t, []string{"version", "title", "description", "termsOfService", "contact", "license"}, keys(info.(jsonutils.JSONMapSlice)))
+
+		for _, pth := range []string{"/spec.yaml", "/spec.yaml?version=1", "/yaml"} {
+			fromYAML, err := JSONMapSliceDoc(serv.URL + pth)
+			require.NoErrorf(t, err, "unexpected error for %q", pth)
+			assert.Truef(t, fromJSON.Equal(fromYAML), "YAML document at %q should be equivalent to the JSON document", pth)
+			assert.Equal(t, keys(fromJSON), keys(fromYAML))
+		}
+	})
+
+	t.Run("should load a local file", func(t *testing.T) {
+		dir := t.TempDir()
+		jsonFile := filepath.Join(dir, "spec.json")
+		yamlFile := filepath.Join(dir, "spec.yml")
+		require.NoError(t, os.WriteFile(jsonFile, []byte(`{"b":1,"a":[true]}`), 0o600))
+		require.NoError(t, os.WriteFile(yamlFile, []byte("b: 1\na: [true]\n"), 0o600))
+
+		expected := jsonutils.JSONMapSlice{{Key: "b", Value: int64(1)}, {Key: "a", Value: []any{true}}}
+		for _, pth := range []string{jsonFile, yamlFile} {
+			doc, err := JSONMapSliceDoc(pth)
+			require.NoErrorf(t, err, "unexpected error for %q", pth)
+			assert.Equal(t, expected, doc)
+		}
+	})
+
+	t.Run("should load with options", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveRequireHeaderFunc("X-Custom", "value")))
+		defer serv.Close()
+
+		_, err := JSONMapSliceDoc(serv.URL)
+		require.ErrorIs(t, err, ErrLoader)
+
+		client := &http.Client{Timeout: time.Second}
+		doc, err := JSONMapSliceDoc(serv.URL, WithHTTPClient(client), WithCustomHeaders(map[string]string{"X-Custom": "value"}))
+		require.NoError(t, err)
+		assert.Nil(t, doc, "an empty document should yield a nil object")
+	})
+
+	t.Run("should not retrieve any doc", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveKO))
+		defer serv.Close()
+
+		_, err := JSONMapSliceDoc(serv.URL)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+
+	t.Run("should not parse invalid docs", func(t *testing.T) {
+		for _, content := range []string{`{"a":`, "- a\n- b\n", "a: [1\n"} {
+			serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write([]byte(content))
+			}))
+
+			_, err := JSONMapSliceDoc(serv.URL + "/doc")
+			serv.Close()
+			require.ErrorIsf(t, err, ErrLoader, "expected an error for %q", content)
+			assert.Contains(t, err.Error(), serv.URL+"/doc")
+		}
+	})
+}
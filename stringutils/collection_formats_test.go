@@ -48,6 +48,37 @@ func TestSplitByFormat(t *testing.T) {
 	}
 }
 
+func TestSplitByFormatEdgeCases(t *testing.T) {
+	separators := map[string]string{
+		collectionFormatComma: ",",
+		collectionFormatSpace: " ",
+		collectionFormatTab:   "\t",
+		collectionFormatPipe:  "|",
+	}
+
+	for fmt, sep := range separators {
+		t.Run("with format "+fmt, func(t *testing.T) {
+			t.Run("should split empty input as nil", func(t *testing.T) {
+				assert.Nil(t, SplitByFormat("", fmt))
+			})
+
+			t.Run("should split a single value", func(t *testing.T) {
+				assert.Equal(t, []string{"one"}, SplitByFormat("one", fmt))
+			})
+
+			t.Run("should trim values and skip empty ones", func(t *testing.T) {
+				assert.Equal(t, []string{"one", "two"}, SplitByFormat(sep+" one "+sep+sep+"two"+sep, fmt))
+				assert.Nil(t, SplitByFormat(sep+sep, fmt))
+			})
+		})
+	}
+
+	t.Run("should split with csv by default", func(t *testing.T) {
+		assert.Equal(t, []string{"one", "two"}, SplitByFormat("one,two", ""))
+		assert.Equal(t, []string{"one", "two"}, SplitByFormat("one, two", "unknown"))
+	})
+}
+
 func TestJoinByFormat(t *testing.T) {
 	for _, fmt := range []string{collectionFormatComma, collectionFormatPipe, collectionFormatTab, collectionFormatSpace, collectionFormatMulti} {
 
@@ -1,6 +1,7 @@
 module github.com/go-openapi/swag/yamlutils
 
 require (
+	github.com/go-openapi/swag/internal/yamlnodes v0.0.0-00010101000000-000000000000
 	github.com/go-openapi/swag/jsonutils v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.10.0
 	gopkg.in/yaml.v3 v3.0.1
@@ -8,6 +9,7 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-openapi/swag/mangling v0.0.0-00010101000000-000000000000 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/kr/pretty v0.2.1 // indirect
 	github.com/mailru/easyjson v0.9.0 // indirect
@@ -16,4 +18,8 @@ require (
 
 replace github.com/go-openapi/swag/jsonutils => ../jsonutils
 
+replace github.com/go-openapi/swag/internal/yamlnodes => ../internal/yamlnodes
+
+replace github.com/go-openapi/swag/mangling => ../mangling
+
 go 1.20
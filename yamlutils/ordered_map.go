@@ -11,8 +11,8 @@ import (
 )
 
 var (
-	_ yaml.Marshaler = YAMLMapSlice{}
-	//_ yaml.Unmarshaler = &YAMLMapSlice{} // TODO: implement yaml.Unmarshaler
+	_ yaml.Marshaler   = YAMLMapSlice{}
+	_ yaml.Unmarshaler = &YAMLMapSlice{}
 )
 
 // YAMLMapSlice represents a YAML object, with the order of keys maintained.
@@ -44,47 +44,100 @@ func (s *YAMLMapSlice) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// MarshalYAML produces a YAML document as bytes
+// MarshalYAML renders this object as a YAML mapping node, preserving the order of keys.
+//
+// Values are rendered like with [JSONToYAML].
 func (s YAMLMapSlice) MarshalYAML() (interface{}, error) {
-	var n yaml.Node
-	n.Kind = yaml.DocumentNode
-	var nodes []*yaml.Node
-
-	for _, item := range s {
-		nn, err := json2yaml(item.Value)
-		if err != nil {
-			return nil, err
-		}
+	return json2yaml(s)
+}
 
-		ns := []*yaml.Node{
-			{
-				Kind:  yaml.ScalarNode,
-				Tag:   yamlStringScalar,
-				Value: item.Key,
-			},
-			nn,
-		}
-		nodes = append(nodes, ns...)
+// UnmarshalYAML builds a YAMLMapSlice object from a YAML document [yaml.Node], preserving the order of keys.
+//
+// The node must be a mapping, or a document holding a mapping. Nested mappings are unmarshaled as [YAMLMapSlice],
+// sequences as []any and scalars according to their YAML tag, like with [YAMLToJSON].
+// A null node yields a nil [YAMLMapSlice]. Aliases are expanded like with [YAMLToJSON].
+func (s *YAMLMapSlice) UnmarshalYAML(value *yaml.Node) error {
+	v, err := yamlTree(value)
+	if err != nil {
+		return err
 	}
 
-	n.Content = []*yaml.Node{
-		{
-			Kind:    yaml.MappingNode,
-			Content: nodes,
-		},
+	switch m := v.(type) {
+	case nil:
+		*s = nil
+	case YAMLMapSlice:
+		*s = m
+	default:
+		return fmt.Errorf("expecting a YAML mapping but got %T: %w", v, ErrYAML)
 	}
 
-	return yaml.Marshal(&n)
+	return nil
 }
 
-/*
-// UnmarshalYAML builds a YAMLMapSlice object from a YAML document [yaml.Node].
-func (s *YAMLMapSlice) UnmarshalYAML(value *yaml.Node) error {
-	panic("not implemented")
+// ToJSONMapSlice converts this YAML object into a [jsonutils.JSONMapSlice], preserving the order of keys.
+//
+// Nested [YAMLMapSlice] objects, including those held in arrays, are converted as well.
+// Other values are retained as they are.
+func (s YAMLMapSlice) ToJSONMapSlice() jsonutils.JSONMapSlice {
+	if s == nil {
+		return nil
+	}
 
-	return nil
+	result := make(jsonutils.JSONMapSlice, len(s))
+	for i, item := range s {
+		result[i] = jsonutils.JSONMapItem{Key: item.Key, Value: convertObjects(item.Value, toJSONMapSlice), Comment: item.Comment}
+	}
+
+	return result
+}
+
+// FromJSONMapSlice converts a [jsonutils.JSONMapSlice] into a [YAMLMapSlice], preserving the order of keys.
+//
+// Nested [jsonutils.JSONMapSlice] objects, including those held in arrays, are converted as well.
+// Other values are retained as they are.
+func FromJSONMapSlice(s jsonutils.JSONMapSlice) YAMLMapSlice {
+	if s == nil {
+		return nil
+	}
+
+	result := make(YAMLMapSlice, len(s))
+	for i, item := range s {
+		result[i] = YAMLMapItem{Key: item.Key, Value: convertObjects(item.Value, fromJSONMapSlice), Comment: item.Comment}
+	}
+
+	return result
+}
+
+func toJSONMapSlice(value any) any {
+	if v, ok := value.(YAMLMapSlice); ok {
+		return v.ToJSONMapSlice()
+	}
+
+	return value
+}
+
+func fromJSONMapSlice(value any) any {
+	if v, ok := value.(jsonutils.JSONMapSlice); ok {
+		return FromJSONMapSlice(v)
+	}
+
+	return value
+}
+
+// convertObjects applies a conversion to objects, looking into arrays.
+func convertObjects(value any, convert func(any) any) any {
+	a, ok := value.([]any)
+	if !ok || a == nil {
+		return convert(value)
+	}
+
+	result := make([]any, len(a))
+	for i, elem := range a {
+		result[i] = convertObjects(elem, convert)
+	}
+
+	return result
 }
-*/
 
 func isNil(input interface{}) bool {
 	if input == nil {
@@ -186,6 +239,12 @@ func json2yaml(item interface{}) (*yaml.Node, error) {
 			Tag:   yamlFloatScalar,
 			Value: strconv.FormatFloat(val, 'f', -1, 64),
 		}, nil
+	case int:
+		return &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   yamlIntScalar,
+			Value: strconv.Itoa(val),
+		}, nil
 	case int64:
 		return &yaml.Node{
 			Kind:  yaml.ScalarNode,
@@ -15,10 +15,14 @@
 package yamlutils
 
 import (
+	"bytes"
 	json "encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 
+	"github.com/go-openapi/swag/internal/yamlnodes"
 	"github.com/go-openapi/swag/jsonutils"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -26,6 +30,9 @@ import (
 // YAMLToJSON converts a YAML document into JSON bytes.
 //
 // Note: a YAML document is the output from a [yaml.Marshaler], e.g a pointer to a [yaml.Node].
+//
+// Aliases are expanded, but an error is returned if an anchor is referenced from within its own value,
+// or if aliases expand to too many nodes compared to the size of the document.
 func YAMLToJSON(value interface{}) (json.RawMessage, error) {
 	jm, err := transformData(value)
 	if err != nil {
@@ -37,6 +44,54 @@ func YAMLToJSON(value interface{}) (json.RawMessage, error) {
 	return json.RawMessage(b), err
 }
 
+// YAMLBytesToJSON converts YAML bytes into JSON bytes, preserving the order of keys in mappings.
+//
+// This is a shorthand for [YAMLToJSON] which takes care of parsing the YAML document.
+// [YAMLToJSON] retains its name for the version which works on an already parsed document.
+//
+// Any kind of YAML document is supported, not only mappings. An empty input yields a JSON null.
+// Only a single YAML document is supported: input with several documents, separated by "---", is rejected.
+func YAMLBytesToJSON(data []byte) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	var document yaml.Node
+	if err := dec.Decode(&document); err != nil {
+		if errors.Is(err, io.EOF) {
+			return []byte("null"), nil
+		}
+
+		return nil, err
+	}
+
+	var next yaml.Node
+	if err := dec.Decode(&next); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("only single YAML documents are supported: %w", ErrYAML)
+	}
+
+	return YAMLToJSON(&document)
+}
+
+// JSONToYAML converts JSON bytes into YAML bytes, preserving the order of keys in objects.
+//
+// Any kind of JSON document is supported, not only objects. Numbers are converted like for [jsonutils.JSONMapSlice].
+func JSONToYAML(data []byte) ([]byte, error) {
+	value, err := jsonutils.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := json2yaml(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(node)
+}
+
 // BytesToYAMLDoc converts a byte slice into a YAML document.
 //
 // This function only supports root documents that are objects.
@@ -53,6 +108,15 @@ func BytesToYAMLDoc(data []byte) (interface{}, error) {
 	return &document, nil
 }
 
+// yamlTree converts a YAML node tree, once its aliases are checked to expand safely.
+func yamlTree(root *yaml.Node) (interface{}, error) {
+	if err := yamlnodes.CheckAliases(root); err != nil {
+		return nil, fmt.Errorf("unable to expand YAML aliases: %w: %w", err, ErrYAML)
+	}
+
+	return yamlNode(root)
+}
+
 func yamlNode(root *yaml.Node) (interface{}, error) {
 	switch root.Kind {
 	case yaml.DocumentNode:
@@ -77,12 +141,22 @@ func yamlDocument(node *yaml.Node) (interface{}, error) {
 	return yamlNode(node.Content[0])
 }
 
+// yamlMapping decodes a YAML mapping node, preserving the order of keys.
+//
+// Merge keys ("<<") are resolved: the keys of the merged mappings which are not explicitly
+// set by the mapping are appended after its own keys.
 func yamlMapping(node *yaml.Node) (interface{}, error) {
 	const sensibleAllocDivider = 2
-	m := make(YAMLMapSlice, len(node.Content)/sensibleAllocDivider)
+	m := make(YAMLMapSlice, 0, len(node.Content)/sensibleAllocDivider)
 
-	var j int
+	var merged []*yaml.Node
 	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Kind == yaml.ScalarNode && node.Content[i].LongTag() == yamlMerge {
+			merged = append(merged, node.Content[i+1])
+
+			continue
+		}
+
 		var nmi YAMLMapItem
 		k, err := yamlStringScalarC(node.Content[i])
 		if err != nil {
@@ -94,10 +168,32 @@ func yamlMapping(node *yaml.Node) (interface{}, error) {
 			return nil, fmt.Errorf("unable to process YAML map value for key %q: %w: %w", k, err, ErrYAML)
 		}
 		nmi.Value = v
-		m[j] = nmi
-		j++
+		m = append(m, nmi)
+	}
+
+	if len(merged) == 0 {
+		return m, nil
 	}
-	return m, nil
+
+	decode := func(node *yaml.Node) (YAMLMapSlice, error) {
+		v, err := yamlMapping(node)
+		if err != nil {
+			return nil, fmt.Errorf("unable to process merged YAML map: %w: %w", err, ErrYAML)
+		}
+
+		return v.(YAMLMapSlice), nil
+	}
+
+	s, err := yamlnodes.Merge(m, yamlMapItemKey, merged, decode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge YAML maps: %w: %w", err, ErrYAML)
+	}
+
+	return s, nil
+}
+
+func yamlMapItemKey(item YAMLMapItem) string {
+	return item.Key
 }
 
 func yamlSequence(node *yaml.Node) (interface{}, error) {
@@ -121,6 +217,7 @@ const ( // See https://yaml.org/type/
 	yamlFloatScalar  = "tag:yaml.org,2002:float"
 	yamlTimestamp    = "tag:yaml.org,2002:timestamp"
 	yamlNull         = "tag:yaml.org,2002:null"
+	yamlMerge        = "tag:yaml.org,2002:merge"
 )
 
 func yamlScalar(node *yaml.Node) (interface{}, error) {
@@ -199,9 +296,9 @@ func transformData(input interface{}) (out interface{}, err error) {
 
 	switch in := input.(type) {
 	case yaml.Node:
-		return yamlNode(&in)
+		return yamlTree(&in)
 	case *yaml.Node:
-		return yamlNode(in)
+		return yamlTree(in)
 	case map[interface{}]interface{}:
 		o := make(YAMLMapSlice, 0, len(in))
 		for ke, va := range in {
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-openapi/swag/jsonutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v3"
@@ -58,9 +59,9 @@ y: some value
 			var data YAMLMapSlice
 			require.NoError(t, json.Unmarshal([]byte(input), &data))
 
-			y, err := data.MarshalYAML()
+			y, err := yaml.Marshal(data)
 			require.NoError(t, err)
-			assert.Equal(t, expected, string(y.([]byte)))
+			assert.Equal(t, expected, string(y))
 		})
 
 		t.Run("with nested object", func(t *testing.T) {
@@ -76,9 +77,9 @@ tag:
 
 			var data YAMLMapSlice
 			require.NoError(t, json.Unmarshal([]byte(input), &data))
-			ny, err := data.MarshalYAML()
+			ny, err := yaml.Marshal(data)
 			require.NoError(t, err)
-			assert.Equal(t, expected, string(ny.([]byte)))
+			assert.Equal(t, expected, string(ny))
 		})
 	})
 
@@ -96,13 +97,10 @@ tag:
 					require.NoError(t, json.Unmarshal(buf, &data))
 
 					t.Run("should marshal YAMLMapSlice into the original doc", func(t *testing.T) {
-						reconstructed, err := data.MarshalYAML()
+						reconstructed, err := yaml.Marshal(data)
 						require.NoError(t, err)
 
-						text, ok := reconstructed.([]byte)
-						require.True(t, ok)
-
-						assert.YAMLEq(t, string(fixture2224), string(text))
+						assert.YAMLEq(t, string(fixture2224), string(reconstructed))
 					})
 				})
 			})
@@ -120,12 +118,35 @@ y: some value
 	)
 	var data YAMLMapSlice
 	require.NoError(t, json.Unmarshal([]byte(jazon), &data))
-	ny, err := data.MarshalYAML()
+	ny, err := yaml.Marshal(data)
 	require.NoError(t, err)
-	assert.Equal(t, expected, string(ny.([]byte)))
+	assert.Equal(t, expected, string(ny))
 }
 
 func TestMarshalYAML(t *testing.T) {
+	t.Run("should marshal as a YAML mapping", func(t *testing.T) {
+		data := YAMLMapSlice{{Key: "a", Value: int64(1)}, {Key: "b", Value: 2}, {Key: "c", Value: YAMLMapSlice{{Key: "d", Value: []any{"x", 1.5}}}}}
+
+		y, err := yaml.Marshal(data)
+		require.NoError(t, err)
+		assert.Equal(t, "a: 1\nb: 2\nc:\n    d:\n        - x\n        - 1.5\n", string(y))
+
+		t.Run("should round-trip through YAML", func(t *testing.T) {
+			var back YAMLMapSlice
+			require.NoError(t, yaml.Unmarshal(y, &back))
+
+			again, err := yaml.Marshal(back)
+			require.NoError(t, err)
+			assert.Equal(t, string(y), string(again))
+		})
+
+		t.Run("should marshal nested in other values", func(t *testing.T) {
+			y, err := yaml.Marshal(map[string]any{"root": data[:1]})
+			require.NoError(t, err)
+			assert.Equal(t, "root:\n    a: 1\n", string(y))
+		})
+	})
+
 	t.Run("marshalYAML should be deterministic", func(t *testing.T) {
 		const (
 			jazon    = `{"1":"x","2":null,"3":{"a":1.1,"b":2.2,"c":3.3}}`
@@ -141,13 +162,96 @@ func TestMarshalYAML(t *testing.T) {
 		for n := 0; n < iterations; n++ {
 			var data YAMLMapSlice
 			require.NoError(t, json.Unmarshal([]byte(jazon), &data))
-			ny, err := data.MarshalYAML()
+			ny, err := yaml.Marshal(data)
 			require.NoError(t, err)
-			assert.Equal(t, expected, string(ny.([]byte)))
+			assert.Equal(t, expected, string(ny))
 		}
 	})
 }
 
+func TestUnmarshalYAML(t *testing.T) {
+	const sd = `---
+z: last letter
+a:
+  d: [1, {f: true, e: 1.5}]
+  c: null
+"1": the int key value
+`
+
+	t.Run("should unmarshal a YAML mapping, preserving the order of keys", func(t *testing.T) {
+		var data YAMLMapSlice
+		require.NoError(t, yaml.Unmarshal([]byte(sd), &data))
+
+		assert.Equal(t, YAMLMapSlice{
+			{Key: "z", Value: "last letter"},
+			{Key: "a", Value: YAMLMapSlice{
+				{Key: "d", Value: []any{int64(1), YAMLMapSlice{{Key: "f", Value: true}, {Key: "e", Value: 1.5}}}},
+				{Key: "c", Value: nil},
+			}},
+			{Key: "1", Value: "the int key value"},
+		}, data)
+
+		t.Run("should convert to a JSONMapSlice", func(t *testing.T) {
+			js := data.ToJSONMapSlice()
+			assert.Equal(t, jsonutils.JSONMapSlice{
+				{Key: "z", Value: "last letter"},
+				{Key: "a", Value: jsonutils.JSONMapSlice{
+					{Key: "d", Value: []any{int64(1), jsonutils.JSONMapSlice{{Key: "f", Value: true}, {Key: "e", Value: 1.5}}}},
+					{Key: "c", Value: nil},
+				}},
+				{Key: "1", Value: "the int key value"},
+			}, js)
+
+			jazon, err := js.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `{"z":"last letter","a":{"d":[1,{"f":true,"e":1.5}],"c":null},"1":"the int key value"}`, string(jazon))
+
+			t.Run("should convert back to the original YAMLMapSlice", func(t *testing.T) {
+				assert.Equal(t, data, FromJSONMapSlice(js))
+			})
+
+			t.Run("should render YAML in the original order", func(t *testing.T) {
+				y, err := yaml.Marshal(FromJSONMapSlice(js))
+				require.NoError(t, err)
+				assert.Equal(t, `z: last letter
+a:
+    d:
+        - 1
+        - f: true
+          e: 1.5
+    c: null
+"1": the int key value
+`, string(y))
+			})
+		})
+	})
+
+	t.Run("should unmarshal a YAML document node", func(t *testing.T) {
+		var doc yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(sd), &doc))
+
+		var data YAMLMapSlice
+		require.NoError(t, data.UnmarshalYAML(&doc))
+		require.Len(t, data, 3)
+		assert.Equal(t, "z", data[0].Key)
+	})
+
+	t.Run("should unmarshal null as a nil YAMLMapSlice", func(t *testing.T) {
+		data := YAMLMapSlice{{Key: "a", Value: 1}}
+		require.NoError(t, yaml.Unmarshal([]byte(`null`), &data))
+		assert.Nil(t, data)
+		assert.Nil(t, data.ToJSONMapSlice())
+		assert.Nil(t, FromJSONMapSlice(nil))
+	})
+
+	t.Run("should not unmarshal a YAML document which is not a mapping", func(t *testing.T) {
+		var data YAMLMapSlice
+		err := yaml.Unmarshal([]byte(`[1, 2]`), &data)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrYAML)
+	})
+}
+
 func TestYAMLToJSON(t *testing.T) {
 	const sd = `---
 1: the int key value
@@ -291,6 +395,222 @@ name: a string value
 	})
 }
 
+func TestYAMLBytesToJSON(t *testing.T) {
+	const (
+		spec = `swagger: "2.0"
+info:
+    title: a spec fragment
+    version: 1.0.0
+paths:
+    /pets/{id}:
+        get:
+            operationId: getPet
+            parameters:
+                - name: id
+                  in: path
+                  required: true
+                  type: integer
+            responses:
+                "200":
+                    description: a pet
+                    schema:
+                        $ref: '#/definitions/pet'
+definitions:
+    pet:
+        type: object
+        required:
+            - name
+        properties:
+            name:
+                type: string
+            age:
+                type: number
+                minimum: 0.5
+`
+		jazon = `{"swagger":"2.0","info":{"title":"a spec fragment","version":"1.0.0"},` +
+			`"paths":{"/pets/{id}":{"get":{"operationId":"getPet",` +
+			`"parameters":[{"name":"id","in":"path","required":true,"type":"integer"}],` +
+			`"responses":{"200":{"description":"a pet","schema":{"$ref":"#/definitions/pet"}}}}}},` +
+			`"definitions":{"pet":{"type":"object","required":["name"],` +
+			`"properties":{"name":{"type":"string"},"age":{"type":"number","minimum":0.5}}}}}`
+	)
+
+	t.Run("should convert YAML to JSON, preserving the order of keys", func(t *testing.T) {
+		j, err := YAMLBytesToJSON([]byte(spec))
+		require.NoError(t, err)
+		assert.Equal(t, jazon, string(j))
+
+		t.Run("should convert JSON back to the original YAML", func(t *testing.T) {
+			y, err := JSONToYAML(j)
+			require.NoError(t, err)
+			assert.Equal(t, spec, string(y))
+		})
+	})
+
+	t.Run("should convert documents which are not mappings", func(t *testing.T) {
+		j, err := YAMLBytesToJSON([]byte("- a\n- 1\n- {b: true}\n"))
+		require.NoError(t, err)
+		assert.Equal(t, `["a",1,{"b":true}]`, string(j))
+
+		j, err = YAMLBytesToJSON([]byte("---\nhello\n"))
+		require.NoError(t, err)
+		assert.Equal(t, `"hello"`, string(j))
+
+		j, err = YAMLBytesToJSON(nil)
+		require.NoError(t, err)
+		assert.Equal(t, `null`, string(j))
+	})
+
+	t.Run("should reject multiple YAML documents", func(t *testing.T) {
+		_, err := YAMLBytesToJSON([]byte("a: 1\n---\nb: 2\n"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrYAML)
+	})
+
+	t.Run("should reject invalid YAML", func(t *testing.T) {
+		_, err := YAMLBytesToJSON([]byte("a: [1\n"))
+		require.Error(t, err)
+
+		_, err = YAMLBytesToJSON([]byte("a: 1\n---\nb: [\n"))
+		require.Error(t, err)
+	})
+}
+
+func TestJSONBytesToYAML(t *testing.T) {
+	t.Run("should convert JSON of any kind to YAML", func(t *testing.T) {
+		y, err := JSONToYAML([]byte(`[{"b":"true","a":null},1.5]`))
+		require.NoError(t, err)
+		assert.Equal(t, `- b: "true"
+  a: null
+- 1.5
+`, string(y))
+	})
+
+	t.Run("should reject invalid JSON", func(t *testing.T) {
+		_, err := JSONToYAML([]byte(`{"a":`))
+		require.Error(t, err)
+	})
+}
+
+func TestYAMLAliases(t *testing.T) {
+	t.Run("should expand aliases", func(t *testing.T) {
+		const sd = `definitions:
+  error: &error
+    type: object
+    properties:
+      code: {type: integer}
+responses:
+  default:
+    schema: *error
+  "404":
+    schema: *error
+codes: &codes [400, 404]
+more: *codes
+`
+		j, err := YAMLBytesToJSON([]byte(sd))
+		require.NoError(t, err)
+		assert.Equal(t, `{"definitions":{"error":{"type":"object","properties":{"code":{"type":"integer"}}}},`+
+			`"responses":{"default":{"schema":{"type":"object","properties":{"code":{"type":"integer"}}}},`+
+			`"404":{"schema":{"type":"object","properties":{"code":{"type":"integer"}}}}},`+
+			`"codes":[400,404],"more":[400,404]}`, string(j))
+
+		t.Run("expanded aliases should not share values", func(t *testing.T) {
+			var data YAMLMapSlice
+			require.NoError(t, yaml.Unmarshal([]byte(sd), &data))
+
+			responses := data[1].Value.(YAMLMapSlice)
+			first := responses[0].Value.(YAMLMapSlice)[0].Value.(YAMLMapSlice)
+			second := responses[1].Value.(YAMLMapSlice)[0].Value.(YAMLMapSlice)
+			first[0].Value = "string"
+			assert.Equal(t, "object", second[0].Value)
+		})
+	})
+
+	t.Run("should expand merge keys", func(t *testing.T) {
+		t.Run("with a single merged mapping", func(t *testing.T) {
+			const sd = `base: &base
+  x: 1
+  y: 2
+other:
+  z: 3
+  <<: *base
+  x: 9
+`
+			j, err := YAMLBytesToJSON([]byte(sd))
+			require.NoError(t, err)
+			assert.Equal(t, `{"base":{"x":1,"y":2},"other":{"z":3,"x":9,"y":2}}`, string(j),
+				"merged keys should be appended, and explicit keys should take precedence",
+			)
+		})
+
+		t.Run("with several merged mappings", func(t *testing.T) {
+			const sd = `a: &a {x: 1, y: 1}
+b: &b {y: 2, w: 2}
+c:
+  <<: [*a, *b, {v: 3, x: 3}]
+  z: 0
+`
+			j, err := YAMLBytesToJSON([]byte(sd))
+			require.NoError(t, err)
+			assert.Equal(t, `{"a":{"x":1,"y":1},"b":{"y":2,"w":2},"c":{"z":0,"x":1,"y":1,"w":2,"v":3}}`, string(j),
+				"the first merged mappings should take precedence",
+			)
+		})
+
+		t.Run("with nested merge keys", func(t *testing.T) {
+			const sd = `a: &a {x: 1}
+b: &b
+  <<: *a
+  y: 2
+c:
+  <<: *b
+`
+			var data YAMLMapSlice
+			require.NoError(t, yaml.Unmarshal([]byte(sd), &data))
+			assert.Equal(t, YAMLMapSlice{{Key: "y", Value: int64(2)}, {Key: "x", Value: int64(1)}}, data[2].Value)
+		})
+
+		t.Run("should reject merging a value which is not a mapping", func(t *testing.T) {
+			for _, sd := range []string{
+				"a: &a [1]\nb:\n  <<: *a\n",
+				"b:\n  <<: [{x: 1}, 2]\n",
+				"b:\n  <<: 1\n",
+			} {
+				_, err := YAMLBytesToJSON([]byte(sd))
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrYAML)
+			}
+		})
+	})
+
+	t.Run("should reject an anchor referenced from within its own value", func(t *testing.T) {
+		for _, sd := range []string{
+			"a: &x\n  b: *x\n",
+			"a: &x\n  <<: *x\n",
+			"a: &x\n  <<: [{y: 1}, *x]\n",
+		} {
+			_, err := YAMLBytesToJSON([]byte(sd))
+			require.ErrorIs(t, err, ErrYAML)
+
+			var data YAMLMapSlice
+			require.ErrorIs(t, yaml.Unmarshal([]byte(sd), &data), ErrYAML)
+		}
+	})
+
+	t.Run("should reject aliases expanding to too many nodes", func(t *testing.T) {
+		const sd = `a: &a {x: 1, y: 2, z: 3}
+b: &b {<<: [*a, *a, *a, *a], w: [*a, *a, *a, *a]}
+c: &c {<<: [*b, *b, *b, *b], w: [*b, *b, *b, *b]}
+d: &d {<<: [*c, *c, *c, *c], w: [*c, *c, *c, *c]}
+e: &e {<<: [*d, *d, *d, *d], w: [*d, *d, *d, *d]}
+f: &f {<<: [*e, *e, *e, *e], w: [*e, *e, *e, *e]}
+g: &g {<<: [*f, *f, *f, *f], w: [*f, *f, *f, *f]}
+`
+		_, err := YAMLBytesToJSON([]byte(sd))
+		require.ErrorIs(t, err, ErrYAML)
+	})
+}
+
 func TestWithYKey(t *testing.T) {
 	doc, err := BytesToYAMLDoc([]byte(fixtureWithYKey))
 	require.NoError(t, err)
@@ -355,16 +675,13 @@ func TestYAMLTags(t *testing.T) {
 				require.NoError(t, json.Unmarshal(jazon, &data))
 
 				t.Run("YAMLMapSlice should marshal to YAML bytes", func(t *testing.T) {
-					text, err := data.MarshalYAML()
+					text, err := yaml.Marshal(data)
 					require.NoError(t, err)
 
-					buf, ok := text.([]byte)
-					require.True(t, ok)
-
 					// standard YAML used by [assert.YAMLEq] interprets YAML timestamp as [time.Time],
 					// but in our context, we use string
 					neutralizeTimestamp := strings.ReplaceAll(string(fixtureSpecTags), "default:", "default: !!str ")
-					assert.YAMLEq(t, neutralizeTimestamp, string(buf))
+					assert.YAMLEq(t, neutralizeTimestamp, string(text))
 				})
 			})
 		})